@@ -1,14 +1,15 @@
 package asset
 
 import (
+	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"time"
 )
 
-func create(db *gorm.DB, tenantId uuid.UUID, compartmentId uuid.UUID, templateId uint32, slot int16, expiration time.Time, referenceId uint32, referenceType ReferenceType) (Model[any], error) {
+func create(db *gorm.DB, t tenant.Model, compartmentId uuid.UUID, templateId uint32, slot int16, expiration time.Time, referenceId uint32, referenceType ReferenceType) (Model[any], error) {
 	e := &Entity{
-		TenantId:      tenantId,
+		TenantId:      t.Id(),
 		CompartmentId: compartmentId,
 		Slot:          slot,
 		TemplateId:    templateId,
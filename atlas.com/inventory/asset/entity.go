@@ -4,22 +4,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
-func Migration(db *gorm.DB) error {
-	return db.AutoMigrate(&Entity{})
-}
-
 type Entity struct {
 	TenantId      uuid.UUID `gorm:"not null"`
 	Id            uint32    `gorm:"primaryKey;autoIncrement;not null"`
-	CompartmentId uuid.UUID `gorm:"not null"`
-	Slot          int16     `gorm:"not null"`
+	CompartmentId uuid.UUID `gorm:"not null;uniqueIndex:idx_assets_compartment_slot,priority:1"`
+	Slot          int16     `gorm:"not null;uniqueIndex:idx_assets_compartment_slot,priority:2"`
 	TemplateId    uint32    `gorm:"not null"`
-	Expiration    time.Time `gorm:"not null"`
-	ReferenceId   uint32    `gorm:"not null"`
-	ReferenceType string    `gorm:"not null"`
+	Expiration    time.Time `gorm:"not null;index"`
+	ReferenceId   uint32    `gorm:"not null;index:idx_assets_reference,priority:1"`
+	ReferenceType string    `gorm:"not null;index:idx_assets_reference,priority:2"`
 }
 
 func (e Entity) TableName() string {
@@ -0,0 +1,121 @@
+package asset
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+// entityV2 is the assets table as the service created it before migrations were versioned.
+type entityV2 struct {
+	TenantId      uuid.UUID `gorm:"not null"`
+	Id            uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	CompartmentId uuid.UUID `gorm:"not null"`
+	Slot          int16     `gorm:"not null"`
+	TemplateId    uint32    `gorm:"not null"`
+	Expiration    time.Time `gorm:"not null"`
+	ReferenceId   uint32    `gorm:"not null"`
+	ReferenceType string    `gorm:"not null"`
+}
+
+func (e entityV2) TableName() string {
+	return "assets"
+}
+
+// CreateTable creates the assets table, or leaves it as it is where it predates versioned migrations.
+func CreateTable(db *gorm.DB) error {
+	return db.AutoMigrate(&entityV2{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("assets")
+}
+
+// AddIndexes keeps each slot of a compartment to a single asset, and indexes the lookups of assets by expiration and
+// by reference. Assets sharing a slot before the index existed are reslotted first.
+func AddIndexes(db *gorm.DB) error {
+	err := reslotDuplicates(db)
+	if err != nil {
+		return err
+	}
+	for _, ddl := range []string{
+		"CREATE UNIQUE INDEX idx_assets_compartment_slot ON assets (compartment_id, slot)",
+		"CREATE INDEX idx_assets_expiration ON assets (expiration)",
+		"CREATE INDEX idx_assets_reference ON assets (reference_id, reference_type)",
+	} {
+		err = db.Exec(ddl).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reslotDuplicates leaves the oldest asset in each slot shared by several, moving the others to the lowest free
+// inventory slots of their compartment. Those past its capacity are reported by the consistency check.
+func reslotDuplicates(db *gorm.DB) error {
+	var shared []entityV2
+	err := db.Model(&entityV2{}).
+		Select("compartment_id, slot").
+		Group("compartment_id, slot").
+		Having("COUNT(*) > 1").
+		Scan(&shared).Error
+	if err != nil {
+		return err
+	}
+	for _, s := range shared {
+		var ids []uint32
+		err = db.Model(&entityV2{}).Where("compartment_id = ? AND slot = ?", s.CompartmentId, s.Slot).Order("id").Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		for _, id := range ids[1:] {
+			var slot int16
+			slot, err = freeSlot(db, s.CompartmentId)
+			if err != nil {
+				return err
+			}
+			err = db.Model(&entityV2{}).Where("id = ?", id).Update("slot", slot).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// freeSlot is the lowest inventory slot of the compartment no asset is in.
+func freeSlot(db *gorm.DB, compartmentId uuid.UUID) (int16, error) {
+	var slots []int16
+	err := db.Model(&entityV2{}).Where("compartment_id = ? AND slot > 0", compartmentId).Order("slot").Pluck("slot", &slots).Error
+	if err != nil {
+		return 0, err
+	}
+	free := int16(1)
+	for _, s := range slots {
+		if s == free {
+			free++
+		} else if s > free {
+			break
+		}
+	}
+	return free, nil
+}
+
+func DropIndexes(db *gorm.DB) error {
+	for _, ddl := range []string{
+		"DROP INDEX idx_assets_reference",
+		"DROP INDEX idx_assets_expiration",
+		"DROP INDEX idx_assets_compartment_slot",
+	} {
+		err := db.Exec(ddl).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
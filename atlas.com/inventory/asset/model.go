@@ -26,6 +26,7 @@ type Model[E any] struct {
 	referenceId   uint32
 	referenceType ReferenceType
 	referenceData E
+	reserved      uint32
 }
 
 func (m Model[E]) Id() uint32 {
@@ -104,6 +105,11 @@ func (m Model[E]) CompartmentId() uuid.UUID {
 	return m.compartmentId
 }
 
+// Reserved is the quantity held by active reservations on the asset's slot.
+func (m Model[E]) Reserved() uint32 {
+	return m.reserved
+}
+
 func Clone[E any](m Model[E]) *ModelBuilder[E] {
 	return &ModelBuilder[E]{
 		id:            m.id,
@@ -114,6 +120,7 @@ func Clone[E any](m Model[E]) *ModelBuilder[E] {
 		referenceId:   m.referenceId,
 		referenceType: m.referenceType,
 		referenceData: m.referenceData,
+		reserved:      m.reserved,
 	}
 }
 
@@ -126,6 +133,7 @@ type ModelBuilder[E any] struct {
 	referenceId   uint32
 	referenceType ReferenceType
 	referenceData E
+	reserved      uint32
 }
 
 func NewBuilder[E any](id uint32, compartmentId uuid.UUID, templateId uint32, referenceId uint32, referenceType ReferenceType) *ModelBuilder[E] {
@@ -155,6 +163,11 @@ func (b *ModelBuilder[E]) SetReferenceData(e E) *ModelBuilder[E] {
 	return b
 }
 
+func (b *ModelBuilder[E]) SetReserved(reserved uint32) *ModelBuilder[E] {
+	b.reserved = reserved
+	return b
+}
+
 func (b *ModelBuilder[E]) Build() Model[E] {
 	return Model[E]{
 		id:            b.id,
@@ -165,5 +178,6 @@ func (b *ModelBuilder[E]) Build() Model[E] {
 		referenceId:   b.referenceId,
 		referenceType: b.referenceType,
 		referenceData: b.referenceData,
+		reserved:      b.reserved,
 	}
 }
@@ -3,14 +3,16 @@ package asset
 import (
 	"atlas-inventory/cash"
 	"atlas-inventory/data/consumable"
+	equipable2 "atlas-inventory/data/equipable"
 	"atlas-inventory/data/etc"
 	"atlas-inventory/data/setup"
 	"atlas-inventory/database"
 	"atlas-inventory/equipable"
 	"atlas-inventory/kafka/message"
 	"atlas-inventory/kafka/message/asset"
-	"atlas-inventory/kafka/producer"
+	"atlas-inventory/outbox"
 	"atlas-inventory/pet"
+	"atlas-inventory/reservation"
 	"atlas-inventory/stackable"
 	"context"
 	"errors"
@@ -26,70 +28,78 @@ import (
 )
 
 type Processor struct {
-	l                   logrus.FieldLogger
-	ctx                 context.Context
-	db                  *gorm.DB
-	t                   tenant.Model
-	equipableProcessor  *equipable.Processor
-	stackableProcessor  *stackable.Processor
-	cashProcessor       *cash.Processor
-	petProcessor        *pet.Processor
-	consumableProcessor consumable.Processor
-	setupProcessor      *setup.Processor
-	etcProcessor        *etc.Processor
+	l                      logrus.FieldLogger
+	ctx                    context.Context
+	db                     *gorm.DB
+	t                      tenant.Model
+	equipableProcessor     *equipable.Processor
+	stackableProcessor     *stackable.Processor
+	cashProcessor          *cash.Processor
+	petProcessor           *pet.Processor
+	consumableProcessor    consumable.Processor
+	equipableDataProcessor *equipable2.Processor
+	setupProcessor         *setup.Processor
+	etcProcessor           *etc.Processor
+	outboxProcessor        *outbox.Processor
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
 	return &Processor{
-		l:                   l,
-		ctx:                 ctx,
-		db:                  db,
-		t:                   tenant.MustFromContext(ctx),
-		equipableProcessor:  equipable.NewProcessor(l, ctx),
-		stackableProcessor:  stackable.NewProcessor(l, ctx, db),
-		cashProcessor:       cash.NewProcessor(l, ctx),
-		petProcessor:        pet.NewProcessor(l, ctx),
-		consumableProcessor: consumable.NewProcessor(l, ctx),
-		setupProcessor:      setup.NewProcessor(l, ctx),
-		etcProcessor:        etc.NewProcessor(l, ctx),
+		l:                      l,
+		ctx:                    ctx,
+		db:                     db,
+		t:                      tenant.MustFromContext(ctx),
+		equipableProcessor:     equipable.NewProcessor(l, ctx),
+		stackableProcessor:     stackable.NewProcessor(l, ctx, db),
+		cashProcessor:          cash.NewProcessor(l, ctx),
+		petProcessor:           pet.NewProcessor(l, ctx),
+		consumableProcessor:    consumable.NewProcessor(l, ctx),
+		equipableDataProcessor: equipable2.NewProcessor(l, ctx),
+		setupProcessor:         setup.NewProcessor(l, ctx),
+		etcProcessor:           etc.NewProcessor(l, ctx),
+		outboxProcessor:        outbox.NewProcessor(l, ctx, db),
 	}
 }
 
 func (p *Processor) WithTransaction(tx *gorm.DB) *Processor {
 	return &Processor{
-		l:                   p.l,
-		ctx:                 p.ctx,
-		db:                  tx,
-		t:                   p.t,
-		equipableProcessor:  p.equipableProcessor,
-		stackableProcessor:  p.stackableProcessor,
-		cashProcessor:       p.cashProcessor,
-		petProcessor:        p.petProcessor,
-		consumableProcessor: p.consumableProcessor,
-		setupProcessor:      p.setupProcessor,
-		etcProcessor:        p.etcProcessor,
+		l:                      p.l,
+		ctx:                    p.ctx,
+		db:                     tx,
+		t:                      p.t,
+		equipableProcessor:     p.equipableProcessor,
+		stackableProcessor:     p.stackableProcessor,
+		cashProcessor:          p.cashProcessor,
+		petProcessor:           p.petProcessor,
+		consumableProcessor:    p.consumableProcessor,
+		equipableDataProcessor: p.equipableDataProcessor,
+		setupProcessor:         p.setupProcessor,
+		etcProcessor:           p.etcProcessor,
+		outboxProcessor:        p.outboxProcessor,
 	}
 }
 
 func (p *Processor) WithConsumableProcessor(conp consumable.Processor) *Processor {
 	return &Processor{
-		l:                   p.l,
-		ctx:                 p.ctx,
-		db:                  p.db,
-		t:                   p.t,
-		equipableProcessor:  p.equipableProcessor,
-		stackableProcessor:  p.stackableProcessor,
-		cashProcessor:       p.cashProcessor,
-		petProcessor:        p.petProcessor,
-		consumableProcessor: conp,
-		setupProcessor:      p.setupProcessor,
-		etcProcessor:        p.etcProcessor,
+		l:                      p.l,
+		ctx:                    p.ctx,
+		db:                     p.db,
+		t:                      p.t,
+		equipableProcessor:     p.equipableProcessor,
+		stackableProcessor:     p.stackableProcessor,
+		cashProcessor:          p.cashProcessor,
+		petProcessor:           p.petProcessor,
+		consumableProcessor:    conp,
+		equipableDataProcessor: p.equipableDataProcessor,
+		setupProcessor:         p.setupProcessor,
+		etcProcessor:           p.etcProcessor,
+		outboxProcessor:        p.outboxProcessor,
 	}
 }
 
 func (p *Processor) ByCompartmentIdProvider(compartmentId uuid.UUID) model.Provider[[]Model[any]] {
 	ap := model.SliceMap(Make)(getByCompartmentId(p.t.Id(), compartmentId)(p.db))(model.ParallelMap())
-	return model.SliceMap(p.DecorateAsset)(ap)(model.ParallelMap())
+	return model.Map(p.DecorateAssets)(ap)
 }
 
 func (p *Processor) GetByCompartmentId(compartmentId uuid.UUID) ([]Model[any], error) {
@@ -113,6 +123,112 @@ func (p *Processor) DecorateAsset(m Model[any]) (Model[any], error) {
 	return decorator(m)
 }
 
+// DecorateAssets decorates a batch of assets as DecorateAsset would, but resolves their references with one request
+// per referenced service, and one stackable query per compartment, rather than one per asset.
+func (p *Processor) DecorateAssets(as []Model[any]) ([]Model[any], error) {
+	var equipableIds, cashIds, petIds []uint32
+	var compartmentIds []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, a := range as {
+		if a.IsEquipable() {
+			equipableIds = append(equipableIds, a.ReferenceId())
+		} else if a.IsCashEquipable() || a.IsCash() {
+			cashIds = append(cashIds, a.ReferenceId())
+		} else if a.IsPet() {
+			petIds = append(petIds, a.ReferenceId())
+		} else if (a.IsConsumable() || a.IsSetup() || a.IsEtc()) && !seen[a.CompartmentId()] {
+			seen[a.CompartmentId()] = true
+			compartmentIds = append(compartmentIds, a.CompartmentId())
+		}
+	}
+
+	es, err := p.equipableProcessor.ByIdsProvider(equipableIds)()
+	if err != nil {
+		return nil, err
+	}
+	equipables := make(map[uint32]equipable.Model)
+	for _, e := range es {
+		equipables[e.Id()] = e
+	}
+	cis, err := p.cashProcessor.ByIdsProvider(cashIds)()
+	if err != nil {
+		return nil, err
+	}
+	cashItems := make(map[uint32]cash.Model)
+	for _, ci := range cis {
+		cashItems[ci.Id()] = ci
+	}
+	pis, err := p.petProcessor.ByIdsProvider(petIds)()
+	if err != nil {
+		return nil, err
+	}
+	pets := make(map[uint32]pet.Model)
+	for _, pi := range pis {
+		pets[pi.Id()] = pi
+	}
+	stackables := make(map[uint32]stackable.Model)
+	for _, compartmentId := range compartmentIds {
+		ss, err := p.stackableProcessor.WithTransaction(p.db).ByCompartmentIdProvider(compartmentId)()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range ss {
+			stackables[s.Id()] = s
+		}
+	}
+
+	results := make([]Model[any], 0, len(as))
+	for _, a := range as {
+		var rd any
+		var ok bool
+		b := Clone(a)
+		if a.IsEquipable() {
+			var e equipable.Model
+			e, ok = equipables[a.ReferenceId()]
+			rd = MakeEquipableReferenceData(e)
+		} else if a.IsCashEquipable() {
+			var ci cash.Model
+			ci, ok = cashItems[a.ReferenceId()]
+			rd = MakeCashEquipableReferenceData(ci)
+		} else if a.IsCash() {
+			var ci cash.Model
+			ci, ok = cashItems[a.ReferenceId()]
+			rd = MakeCashReferenceData(ci)
+		} else if a.IsPet() {
+			var pi pet.Model
+			pi, ok = pets[a.ReferenceId()]
+			rd = MakePetReferenceData(pi)
+			b.SetExpiration(pi.Expiration())
+		} else if a.IsConsumable() || a.IsSetup() || a.IsEtc() {
+			var s stackable.Model
+			s, ok = stackables[a.ReferenceId()]
+			rd = makeStackableReferenceData(a.ReferenceType(), s)
+		} else {
+			return nil, errors.New("no decorators for reference type")
+		}
+		if !ok {
+			return nil, errors.New("cannot locate reference")
+		}
+		results = append(results, b.SetReferenceData(rd).Build())
+	}
+	return results, nil
+}
+
+// DecorateReserved annotates each asset of a compartment with the quantity active reservations hold on its slot.
+func (p *Processor) DecorateReserved(characterId uint32, inventoryType inventory.Type) func(as []Model[any]) ([]Model[any], error) {
+	return func(as []Model[any]) ([]Model[any], error) {
+		rq, err := reservation.NewProcessor(p.l, p.ctx, p.db).GetReservedQuantities(characterId, inventoryType)
+		if err != nil {
+			return nil, err
+		}
+		results := make([]Model[any], 0, len(as))
+		for _, a := range as {
+			results = append(results, Clone(a).SetReserved(rq[a.Slot()]).Build())
+		}
+		return results, nil
+	}
+}
+
 func (p *Processor) GetBySlot(compartmentId uuid.UUID, slot int16) (Model[any], error) {
 	return p.BySlotProvider(compartmentId)(slot)()
 }
@@ -139,6 +255,31 @@ func (p *Processor) GetById(id uint32) (Model[any], error) {
 	return model.CollapseProvider(p.ByIdProvider)(id)
 }
 
+// AllProvider yields every asset held by the tenant, undecorated, so assets whose reference is missing are included.
+func (p *Processor) AllProvider() model.Provider[[]Model[any]] {
+	return model.SliceMap(Make)(getAll(p.t.Id())(p.db))(model.ParallelMap())
+}
+
+// UndecoratedByCompartmentIdProvider yields the assets of a compartment without resolving their references.
+func (p *Processor) UndecoratedByCompartmentIdProvider(compartmentId uuid.UUID) model.Provider[[]Model[any]] {
+	return model.SliceMap(Make)(getByCompartmentId(p.t.Id(), compartmentId)(p.db))(model.ParallelMap())
+}
+
+// UndecoratedByIdProvider yields an asset without resolving its reference, which may no longer exist.
+func (p *Processor) UndecoratedByIdProvider(id uint32) model.Provider[Model[any]] {
+	return model.Map(Make)(getById(p.t.Id(), id)(p.db))
+}
+
+// ExpiredProvider yields the undecorated assets whose expiration has passed as of now.
+func (p *Processor) ExpiredProvider(now time.Time) model.Provider[[]Model[any]] {
+	return model.SliceMap(Make)(getExpired(p.t.Id(), now)(p.db))(model.ParallelMap())
+}
+
+// ExpiringProvider yields the undecorated assets which have not expired as of now, but will have by the horizon.
+func (p *Processor) ExpiringProvider(now time.Time, horizon time.Time) model.Provider[[]Model[any]] {
+	return model.SliceMap(Make)(getExpiring(p.t.Id(), now, horizon)(p.db))(model.ParallelMap())
+}
+
 func (p *Processor) DecorateEquipable(m Model[any]) (Model[any], error) {
 	e, err := p.equipableProcessor.GetById(m.ReferenceId())
 	if err != nil {
@@ -191,34 +332,44 @@ func (p *Processor) DecorateCashEquipable(m Model[any]) (Model[any], error) {
 		return m, errors.New("cannot locate reference")
 	}
 	return Clone(m).
-		SetReferenceData(CashEquipableReferenceData{
-			CashData: CashData{
-				cashId: ci.CashId(),
-			},
-		}).
+		SetReferenceData(MakeCashEquipableReferenceData(ci)).
 		Build(), nil
 }
 
+func MakeCashEquipableReferenceData(ci cash.Model) CashEquipableReferenceData {
+	return CashEquipableReferenceData{
+		CashData: CashData{
+			cashId: ci.CashId(),
+		},
+		OwnerData: OwnerData{
+			ownerId: ci.PurchasedBy(),
+		},
+		expiration: ci.Expiration(),
+	}
+}
+
 func (p *Processor) DecorateStackable(m Model[any]) (Model[any], error) {
-	s, err := p.stackableProcessor.GetById(m.ReferenceId())
+	s, err := p.stackableProcessor.WithTransaction(p.db).GetById(m.ReferenceId())
 	if err != nil {
 		return m, errors.New("cannot locate reference")
 	}
 
-	var rd any
-	if m.ReferenceType() == ReferenceTypeConsumable {
-		rd = MakeConsumableReferenceData(s)
-	} else if m.ReferenceType() == ReferenceTypeSetup {
-		rd = MakeSetupReferenceData(s)
-	} else if m.ReferenceType() == ReferenceTypeEtc {
-		rd = MakeEtcReferenceData(s)
-	}
-
 	return Clone(m).
-		SetReferenceData(rd).
+		SetReferenceData(makeStackableReferenceData(m.ReferenceType(), s)).
 		Build(), nil
 }
 
+func makeStackableReferenceData(referenceType ReferenceType, s stackable.Model) any {
+	if referenceType == ReferenceTypeConsumable {
+		return MakeConsumableReferenceData(s)
+	} else if referenceType == ReferenceTypeSetup {
+		return MakeSetupReferenceData(s)
+	} else if referenceType == ReferenceTypeEtc {
+		return MakeEtcReferenceData(s)
+	}
+	return nil
+}
+
 func MakeEtcReferenceData(s stackable.Model) EtcReferenceData {
 	return EtcReferenceData{
 		StackableData: StackableData{
@@ -269,23 +420,7 @@ func (p *Processor) DecorateCash(m Model[any]) (Model[any], error) {
 			return m, errors.New("cannot locate reference")
 		}
 		return Clone(m).
-			SetReferenceData(CashReferenceData{
-				CashData: CashData{
-					cashId: ci.CashId(),
-				},
-				StackableData: StackableData{
-					quantity: ci.Quantity(),
-				},
-				OwnerData: OwnerData{
-					ownerId: 0, // TODO
-				},
-				FlagData: FlagData{
-					flag: ci.Flag(),
-				},
-				PurchaseData: PurchaseData{
-					purchaseBy: ci.PurchasedBy(),
-				},
-			}).
+			SetReferenceData(MakeCashReferenceData(ci)).
 			Build(), nil
 	} else if m.ReferenceType() == ReferenceTypePet {
 		pi, err := p.petProcessor.GetById(m.ReferenceId())
@@ -300,6 +435,26 @@ func (p *Processor) DecorateCash(m Model[any]) (Model[any], error) {
 	return m, nil
 }
 
+func MakeCashReferenceData(ci cash.Model) CashReferenceData {
+	return CashReferenceData{
+		CashData: CashData{
+			cashId: ci.CashId(),
+		},
+		StackableData: StackableData{
+			quantity: ci.Quantity(),
+		},
+		OwnerData: OwnerData{
+			ownerId: ci.PurchasedBy(),
+		},
+		FlagData: FlagData{
+			flag: ci.Flag(),
+		},
+		PurchaseData: PurchaseData{
+			purchaseBy: ci.PurchasedBy(),
+		},
+	}
+}
+
 func MakePetReferenceData(pi pet.Model) PetReferenceData {
 	return PetReferenceData{
 		CashData: CashData{
@@ -324,38 +479,105 @@ func MakePetReferenceData(pi pet.Model) PetReferenceData {
 }
 
 func (p *Processor) Delete(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
+	return p.delete(mb, "")
+}
+
+// Expire deletes an asset whose expiration has passed, announcing it with the EXPIRED reason. Where the item data names
+// a replacement template, the replacement is created in the vacated slot.
+func (p *Processor) Expire(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
+	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
+		return func(a Model[any]) error {
+			replacementId, err := p.replacementTemplate(a.TemplateId())
+			if err != nil {
+				return err
+			}
+			return database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
+				err := p.WithTransaction(tx).delete(mb, asset.DeletedReasonExpired)(transactionId, characterId, compartmentId)(a)
+				if err != nil {
+					return err
+				}
+				if replacementId == 0 {
+					p.l.Debugf("Asset [%d] of item [%d] expired for character [%d].", a.Id(), a.TemplateId(), characterId)
+					return nil
+				}
+				quantity := uint32(1)
+				if a.HasQuantity() {
+					quantity = a.Quantity()
+				}
+				_, err = p.WithTransaction(tx).Create(mb)(transactionId, characterId, compartmentId, replacementId, a.Slot(), quantity, time.Time{}, 0, 0, 0)
+				if err != nil {
+					return err
+				}
+				p.l.Debugf("Asset [%d] of item [%d] expired for character [%d], replaced by item [%d].", a.Id(), a.TemplateId(), characterId, replacementId)
+				return nil
+			}))
+		}
+	}
+}
+
+// WarnExpiring announces that an asset will expire within threshold.
+func (p *Processor) WarnExpiring(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32) func(a Model[any], threshold time.Duration) error {
+	return func(transactionId uuid.UUID, characterId uint32) func(a Model[any], threshold time.Duration) error {
+		return func(a Model[any], threshold time.Duration) error {
+			p.l.Debugf("Asset [%d] of item [%d] for character [%d] expires within [%s].", a.Id(), a.TemplateId(), characterId, threshold)
+			return mb.Put(asset.EnvEventTopicStatus, ExpiringSoonEventStatusProvider(transactionId, characterId, a, threshold))
+		}
+	}
+}
+
+// replacementTemplate yields the item an expired item is converted to, or zero when the item data names none.
+func (p *Processor) replacementTemplate(templateId uint32) (uint32, error) {
+	inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
+	if !ok || inventoryType != inventory.TypeValueUse {
+		return 0, nil
+	}
+	m, err := p.consumableProcessor.GetById(templateId)
+	if err != nil {
+		return 0, err
+	}
+	return m.ReplaceItemId(), nil
+}
+
+func (p *Processor) delete(mb *message.Buffer, reason string) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
 	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
 		return func(a Model[any]) error {
 			p.l.Debugf("Attempting to delete asset [%d].", a.Id())
-			txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+			txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
+				// References held by other services are deleted once the asset is gone for good, so a rollback never
+				// leaves an asset pointing at a deleted reference.
 				var deleteRefFunc func(id uint32) error
 				if a.ReferenceType() == ReferenceTypeEquipable {
 					deleteRefFunc = p.equipableProcessor.Delete
 				} else if a.ReferenceType() == ReferenceTypeCashEquipable {
-					// TODO Cash Shop
+					deleteRefFunc = p.cashProcessor.Delete
 				} else if a.ReferenceType() == ReferenceTypeConsumable || a.ReferenceType() == ReferenceTypeSetup || a.ReferenceType() == ReferenceTypeEtc {
-					deleteRefFunc = p.stackableProcessor.Delete
+					err := p.stackableProcessor.WithTransaction(tx).Delete(a.ReferenceId())
+					if err != nil {
+						p.l.WithError(err).Errorf("Unable to delete asset [%d], due to error deleting reference [%d].", a.Id(), a.ReferenceId())
+						return err
+					}
 				} else if a.ReferenceType() == ReferenceTypeCash {
-					// TODO Cash Shop
+					deleteRefFunc = p.cashProcessor.Delete
 				} else if a.ReferenceType() == ReferenceTypePet {
-					// TODO Cash Shop
-				}
-
-				if deleteRefFunc == nil {
+					deleteRefFunc = p.petProcessor.Delete
+				} else {
 					p.l.Errorf("Unable to locate delete function for asset [%d]. This will lead to a dangling asset.", a.Id())
 					return nil
 				}
-				err := deleteRefFunc(a.ReferenceId())
-				if err != nil {
-					p.l.WithError(err).Errorf("Unable to delete asset [%d], due to error deleting reference [%d].", a.Id(), a.ReferenceId())
-					return err
+				if deleteRefFunc != nil {
+					database.AfterCommit(tx, func() {
+						err := deleteRefFunc(a.ReferenceId())
+						if err != nil {
+							p.l.WithError(err).Errorf("Deleted asset [%d], but unable to delete reference [%d].", a.Id(), a.ReferenceId())
+						}
+					})
 				}
-				err = deleteById(tx, p.t.Id(), a.Id())
+				err := deleteById(tx, p.t.Id(), a.Id())
 				if err != nil {
 					return err
 				}
-				return mb.Put(asset.EnvEventTopicStatus, DeletedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot()))
-			})
+				return mb.Put(asset.EnvEventTopicStatus, DeletedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot(), reason))
+			}))
 			if txErr != nil {
 				p.l.WithError(txErr).Errorf("Unable to delete asset [%d].", a.Id())
 				return txErr
@@ -366,17 +588,28 @@ func (p *Processor) Delete(mb *message.Buffer) func(transactionId uuid.UUID, cha
 	}
 }
 
+// Drop removes an asset whose reference lives on outside the inventory, as on the ground of a map.
 func (p *Processor) Drop(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
+	return p.remove(mb, "")
+}
+
+// Discard removes an asset whose reference no longer exists, announcing it with the DANGLING reason.
+func (p *Processor) Discard(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
+	return p.remove(mb, asset.DeletedReasonDangling)
+}
+
+// remove deletes an asset, leaving its reference in place.
+func (p *Processor) remove(mb *message.Buffer, reason string) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
 	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
 		return func(a Model[any]) error {
 			p.l.Debugf("Attempting to delete asset [%d].", a.Id())
-			txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+			txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 				err := deleteById(tx, p.t.Id(), a.Id())
 				if err != nil {
 					return err
 				}
-				return mb.Put(asset.EnvEventTopicStatus, DeletedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot()))
-			})
+				return mb.Put(asset.EnvEventTopicStatus, DeletedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot(), reason))
+			}))
 			if txErr != nil {
 				p.l.WithError(txErr).Errorf("Unable to delete asset [%d].", a.Id())
 				return txErr
@@ -412,22 +645,43 @@ func (p *Processor) UpdateSlot(mb *message.Buffer) func(transactionId uuid.UUID,
 	}
 }
 
+// Reslot moves an asset to a slot, announcing the move even when it leaves the temporary slot.
+func (p *Processor) Reslot(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any], slot int16) error {
+	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID) func(a Model[any], slot int16) error {
+		return func(a Model[any], slot int16) error {
+			p.l.Debugf("Character [%d] asset [%d] being re-slotted to [%d] from [%d].", characterId, a.Id(), slot, a.Slot())
+			err := updateSlot(p.db, p.t.Id(), a.Id(), slot)
+			if err != nil {
+				return err
+			}
+			return mb.Put(asset.EnvEventTopicStatus, MovedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), slot, a.Slot()))
+		}
+	}
+}
+
 func (p *Processor) UpdateQuantity(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, a Model[any], quantity uint32) error {
 	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, a Model[any], quantity uint32) error {
 		if !a.HasQuantity() {
 			return errors.New("cannot update quantity of non-stackable")
 		}
 		if a.IsConsumable() || a.IsSetup() || a.IsEtc() {
-			err := p.stackableProcessor.UpdateQuantity(a.ReferenceId(), quantity)
+			err := p.stackableProcessor.WithTransaction(p.db).UpdateQuantity(a.ReferenceId(), quantity)
 			if err != nil {
 				return err
 			}
 			return mb.Put(asset.EnvEventTopicStatus, QuantityChangedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot(), quantity))
 		} else if a.IsCash() {
+			previous := a.Quantity()
 			err := p.cashProcessor.UpdateQuantity(a.ReferenceId(), quantity)
 			if err != nil {
 				return err
 			}
+			database.AfterRollback(p.db, func() {
+				err := p.cashProcessor.UpdateQuantity(a.ReferenceId(), previous)
+				if err != nil {
+					p.l.WithError(err).Errorf("Unable to restore quantity [%d] of cash item [%d] after rollback.", previous, a.ReferenceId())
+				}
+			})
 			return mb.Put(asset.EnvEventTopicStatus, QuantityChangedEventStatusProvider(transactionId, characterId, compartmentId, a.Id(), a.TemplateId(), a.Slot(), quantity))
 		}
 		return errors.New("unknown ReferenceData which implements HasQuantity")
@@ -435,7 +689,7 @@ func (p *Processor) UpdateQuantity(mb *message.Buffer) func(transactionId uuid.U
 }
 
 func (p *Processor) RelayUpdateAndEmit(transactionId uuid.UUID, characterId uint32, referenceId uint32, referenceType ReferenceType, referenceData interface{}) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	return message.Emit(p.outboxProcessor.Producer())(func(buf *message.Buffer) error {
 		return p.RelayUpdate(buf)(transactionId, characterId, referenceId, referenceType, referenceData)
 	})
 }
@@ -450,7 +704,7 @@ func (p *Processor) DeleteAndEmit(transactionId uuid.UUID, characterId uint32, c
 		return err
 	}
 
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	return message.Emit(p.outboxProcessor.Producer())(func(buf *message.Buffer) error {
 		return p.Delete(buf)(transactionId, characterId, compartmentId)(asset)
 	})
 }
@@ -459,7 +713,7 @@ func (p *Processor) RelayUpdate(mb *message.Buffer) func(transactionId uuid.UUID
 	return func(transactionId uuid.UUID, characterId uint32, referenceId uint32, referenceType ReferenceType, referenceData interface{}) error {
 		p.l.Debugf("Attempting to relay asset update. ReferenceId [%d], ReferenceType [%s].", referenceId, referenceType)
 		var a Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			var ap model.Provider[Model[any]]
 			if referenceData == nil {
 				ap = p.WithTransaction(tx).ByReferenceIdProvider(referenceId, referenceType)
@@ -472,7 +726,7 @@ func (p *Processor) RelayUpdate(mb *message.Buffer) func(transactionId uuid.UUID
 				return err
 			}
 			return mb.Put(asset.EnvEventTopicStatus, UpdatedEventStatusProvider(transactionId, characterId, a))
-		})
+		}))
 		if txErr != nil {
 			return txErr
 		}
@@ -485,7 +739,7 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, templateId uint32, slot int16, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) (Model[any], error) {
 		p.l.Debugf("Character [%d] attempting to create [%d] item(s) [%d] in slot [%d] of compartment [%s].", characterId, quantity, templateId, slot, compartmentId.String())
 		var a Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			var referenceId uint32
 			var referenceType ReferenceType
 			inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
@@ -495,14 +749,29 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 
 			var rd interface{}
 			if inventoryType == inventory.TypeValueEquip {
-				// TODO determine if we're creating an Equip or Cash Equip
-				e, err := p.equipableProcessor.Create(templateId)()
+				ed, err := p.equipableDataProcessor.GetById(templateId)
 				if err != nil {
 					return err
 				}
-				referenceId = e.Id()
-				referenceType = ReferenceTypeEquipable
-				rd = MakeEquipableReferenceData(e)
+				if ed.Cash() {
+					ci, err := p.cashProcessor.Create(characterId, templateId, 1)
+					if err != nil {
+						return err
+					}
+					p.compensate(tx, ci.Id(), p.cashProcessor.Delete)
+					referenceId = ci.Id()
+					referenceType = ReferenceTypeCashEquipable
+					rd = MakeCashEquipableReferenceData(ci)
+				} else {
+					e, err := p.equipableProcessor.Create(templateId)()
+					if err != nil {
+						return err
+					}
+					p.compensate(tx, e.Id(), p.equipableProcessor.Delete)
+					referenceId = e.Id()
+					referenceType = ReferenceTypeEquipable
+					rd = MakeEquipableReferenceData(e)
+				}
 			} else if inventoryType == inventory.TypeValueUse {
 				s, err := p.stackableProcessor.WithTransaction(tx).Create(compartmentId, quantity, ownerId, flag, rechargeable)
 				if err != nil {
@@ -533,11 +802,19 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 					if err != nil {
 						return err
 					}
+					p.compensate(tx, pe.Id(), p.petProcessor.Delete)
 					referenceId = pe.Id()
 					referenceType = ReferenceTypePet
 					rd = MakePetReferenceData(pe)
 				} else {
-					// TODO
+					ci, err := p.cashProcessor.Create(characterId, templateId, quantity)
+					if err != nil {
+						return err
+					}
+					p.compensate(tx, ci.Id(), p.cashProcessor.Delete)
+					referenceId = ci.Id()
+					referenceType = ReferenceTypeCash
+					rd = MakeCashReferenceData(ci)
 				}
 			}
 
@@ -546,13 +823,13 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 			}
 
 			var err error
-			a, err = create(p.db, p.t.Id(), compartmentId, templateId, slot, expiration, referenceId, referenceType)
+			a, err = create(tx, p.t, compartmentId, templateId, slot, expiration, referenceId, referenceType)
 			if err != nil {
 				return err
 			}
 			a = Clone(a).SetReferenceData(rd).Build()
 			return mb.Put(asset.EnvEventTopicStatus, CreatedEventStatusProvider(transactionId, characterId, a))
-		})
+		}))
 		if txErr != nil {
 			return Model[any]{}, txErr
 		}
@@ -560,6 +837,16 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 	}
 }
 
+// compensate deletes a reference created in another service should the transaction creating its asset roll back.
+func (p *Processor) compensate(tx *gorm.DB, referenceId uint32, deleteRefFunc func(id uint32) error) {
+	database.AfterRollback(tx, func() {
+		err := deleteRefFunc(referenceId)
+		if err != nil {
+			p.l.WithError(err).Errorf("Unable to delete reference [%d] after rollback. It is now orphaned.", referenceId)
+		}
+	})
+}
+
 // GetSlotMax retrieves the maximum slot capacity for a given asset template
 func (p *Processor) GetSlotMax(templateId uint32) (uint32, error) {
 	inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
@@ -595,7 +882,7 @@ func (p *Processor) Acquire(mb *message.Buffer) func(transactionId uuid.UUID, ch
 	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, templateId uint32, slot int16, quantity uint32, referenceId uint32) (Model[any], error) {
 		p.l.Debugf("Character [%d] attempting to acquire [%d] item(s) [%d] in slot [%d] of compartment [%s].", characterId, quantity, templateId, slot, compartmentId.String())
 		var a Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			var referenceType ReferenceType
 			inventoryType, ok := inventory.TypeFromItemId(item.Id(templateId))
 			if !ok {
@@ -619,13 +906,13 @@ func (p *Processor) Acquire(mb *message.Buffer) func(transactionId uuid.UUID, ch
 			}
 
 			var err error
-			a, err = create(p.db, p.t.Id(), compartmentId, templateId, slot, expiration, referenceId, referenceType)
+			a, err = create(tx, p.t, compartmentId, templateId, slot, expiration, referenceId, referenceType)
 			if err != nil {
 				return err
 			}
 			a = Clone(a).SetReferenceData(rd).Build()
 			return mb.Put(asset.EnvEventTopicStatus, CreatedEventStatusProvider(transactionId, characterId, a))
-		})
+		}))
 		if txErr != nil {
 			return Model[any]{}, txErr
 		}
@@ -633,12 +920,12 @@ func (p *Processor) Acquire(mb *message.Buffer) func(transactionId uuid.UUID, ch
 	}
 }
 
-func (p *Processor) Accept(mb *message.Buffer) func(characterId uint32, compartmentId uuid.UUID, type_ inventory.Type, slot int16, cashItemId uint32) (Model[any], error) {
-	return func(characterId uint32, compartmentId uuid.UUID, type_ inventory.Type, slot int16, cashItemId uint32) (Model[any], error) {
+func (p *Processor) Accept(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, type_ inventory.Type, slot int16, cashItemId uint32) (Model[any], error) {
+	return func(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, type_ inventory.Type, slot int16, cashItemId uint32) (Model[any], error) {
 		// TODO this eventually needs to not be cash item specific
 		p.l.Debugf("Character [%d] attempting to acquire cash item [%d] in slot [%d] of compartment [%s].", characterId, cashItemId, slot, compartmentId.String())
 		var a Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			// For cash items, we use ReferenceTypeCash
 			var referenceType ReferenceType
 			if type_ == inventory.TypeValueEquip {
@@ -654,13 +941,17 @@ func (p *Processor) Accept(mb *message.Buffer) func(characterId uint32, compartm
 			}
 
 			// Create the asset with the cash item reference
-			expiration := time.Time{} // Cash items typically don't expire
-			a, err = create(p.db, p.t.Id(), compartmentId, ci.TemplateId(), slot, expiration, cashItemId, referenceType)
+			a, err = create(tx, p.t, compartmentId, ci.TemplateId(), slot, ci.Expiration(), cashItemId, referenceType)
 			if err != nil {
 				return err
 			}
-			return nil
-		})
+			if referenceType == ReferenceTypeCashEquipable {
+				a = Clone(a).SetReferenceData(MakeCashEquipableReferenceData(ci)).Build()
+			} else {
+				a = Clone(a).SetReferenceData(MakeCashReferenceData(ci)).Build()
+			}
+			return mb.Put(asset.EnvEventTopicStatus, CreatedEventStatusProvider(transactionId, characterId, a))
+		}))
 		if txErr != nil {
 			return Model[any]{}, txErr
 		}
@@ -672,13 +963,13 @@ func (p *Processor) Release(mb *message.Buffer) func(characterId uint32, compart
 	return func(characterId uint32, compartmentId uuid.UUID) func(a Model[any]) error {
 		return func(a Model[any]) error {
 			p.l.Debugf("Attempting to release asset [%d].", a.Id())
-			txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+			txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 				err := deleteById(tx, p.t.Id(), a.Id())
 				if err != nil {
 					return err
 				}
 				return nil
-			})
+			}))
 			if txErr != nil {
 				p.l.WithError(txErr).Errorf("Unable to delete asset [%d].", a.Id())
 				return txErr
@@ -0,0 +1,269 @@
+package asset_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/cash"
+	"atlas-inventory/equipable"
+	"atlas-inventory/pet"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// standIn serves equipables, cash items and pets for any id requested, singly or by a filter[ids] list, counting the
+// requests it receives. Each record's data is derived from its id. Missing ids are omitted from lists and not found
+// singly.
+type standIn struct {
+	calls   atomic.Int64
+	mu      sync.Mutex
+	missing map[uint32]bool
+}
+
+// miss makes the stand-in treat the ids as missing, in place of those it treated so before.
+func (s *standIn) miss(ids ...uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.missing = make(map[uint32]bool)
+	for _, id := range ids {
+		s.missing[id] = true
+	}
+}
+
+// standInExpiration is the expiration the stand-in gives the cash item or pet with the id.
+func standInExpiration(id uint32) time.Time {
+	return time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Hour * time.Duration(id))
+}
+
+func (s *standIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.calls.Add(1)
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	resource := segments[len(segments)-1]
+	var ids []uint32
+	single := false
+	if id, err := strconv.Atoi(resource); err == nil {
+		resource = segments[len(segments)-2]
+		ids = append(ids, uint32(id))
+		single = true
+	} else {
+		for _, str := range strings.Split(r.URL.Query().Get("filter[ids]"), ",") {
+			id, err := strconv.Atoi(str)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			ids = append(ids, uint32(id))
+		}
+	}
+	s.mu.Lock()
+	present := ids[:0]
+	for _, id := range ids {
+		if !s.missing[id] {
+			present = append(present, id)
+		}
+	}
+	s.mu.Unlock()
+	ids = present
+	if single && len(ids) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var data interface{}
+	switch resource {
+	case "equipables":
+		rms := make([]equipable.RestModel, 0, len(ids))
+		for _, id := range ids {
+			rms = append(rms, equipable.RestModel{Id: id, ItemId: 1302000})
+		}
+		data = rms
+		if single {
+			data = rms[0]
+		}
+	case "items":
+		rms := make([]cash.RestModel, 0, len(ids))
+		for _, id := range ids {
+			rms = append(rms, cash.RestModel{Id: id, CashId: int64(id) * 10, TemplateId: 5000000, Quantity: id, PurchasedBy: 7, Expiration: standInExpiration(id)})
+		}
+		data = rms
+		if single {
+			data = rms[0]
+		}
+	case "pets":
+		rms := make([]pet.RestModel, 0, len(ids))
+		for _, id := range ids {
+			rms = append(rms, pet.RestModel{Id: id, CashId: int64(id) * 10, TemplateId: 5000017, OwnerId: 7, Expiration: standInExpiration(id)})
+		}
+		data = rms
+		if single {
+			data = rms[0]
+		}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := jsonapi.Marshal(data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	_, _ = w.Write(body)
+}
+
+// redirect routes every outbound request to the stand-in server, whatever service it was addressed to.
+type redirect struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (r redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	req.Host = r.target.Host
+	if !strings.HasPrefix(req.URL.Path, "/") {
+		req.URL.Path = "/" + req.URL.Path
+	}
+	return r.next.RoundTrip(req)
+}
+
+func withStandIn(tb testing.TB) *standIn {
+	s := &standIn{}
+	srv := httptest.NewServer(s)
+	target, _ := url.Parse(srv.URL)
+	previous := http.DefaultTransport
+	http.DefaultTransport = redirect{target: target, next: previous}
+	tb.Cleanup(func() {
+		http.DefaultTransport = previous
+		srv.Close()
+	})
+	return s
+}
+
+// inventoryAssets builds a compartment's worth of equipment, cash items and pets, each referring to its own record.
+func inventoryAssets(count int) []asset.Model[any] {
+	compartmentId := uuid.New()
+	as := make([]asset.Model[any], 0, count)
+	for i := 0; i < count; i++ {
+		id := uint32(i + 1)
+		var rt asset.ReferenceType
+		switch i % 3 {
+		case 0:
+			rt = asset.ReferenceTypeEquipable
+		case 1:
+			rt = asset.ReferenceTypeCash
+		default:
+			rt = asset.ReferenceTypePet
+		}
+		as = append(as, asset.NewBuilder[any](id, compartmentId, 1302000, id, rt).SetSlot(int16(i+1)).Build())
+	}
+	return as
+}
+
+func testProcessor(tb testing.TB) *asset.Processor {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		tb.Fatalf("Failed to connect to database: %v", err)
+	}
+	te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return asset.NewProcessor(testLogger(), tenant.WithContext(context.Background(), te), db)
+}
+
+// TestDecorateAssetsMatchesDecorateAsset verifies that decorating a batch of assets gives each asset the reference data
+// and expiration decorating it alone would, and that a reference missing upstream fails both.
+func TestDecorateAssetsMatchesDecorateAsset(t *testing.T) {
+	s := withStandIn(t)
+	p := testProcessor(t)
+	compartmentId := uuid.New()
+	as := []asset.Model[any]{
+		asset.NewBuilder[any](1, compartmentId, 1302000, 1, asset.ReferenceTypeEquipable).SetSlot(1).Build(),
+		asset.NewBuilder[any](2, compartmentId, 1702000, 2, asset.ReferenceTypeCashEquipable).SetSlot(2).Build(),
+		asset.NewBuilder[any](3, compartmentId, 5000000, 3, asset.ReferenceTypeCash).SetSlot(3).Build(),
+		asset.NewBuilder[any](4, compartmentId, 5000017, 4, asset.ReferenceTypePet).SetSlot(4).Build(),
+	}
+
+	batched, err := p.DecorateAssets(as)
+	if err != nil {
+		t.Fatalf("Failed to decorate assets: %v", err)
+	}
+	if len(batched) != len(as) {
+		t.Fatalf("Expected [%d] decorated assets, got [%d].", len(as), len(batched))
+	}
+	for i, a := range as {
+		individual, err := p.DecorateAsset(a)
+		if err != nil {
+			t.Fatalf("Failed to decorate asset [%d]: %v", a.Id(), err)
+		}
+		if !reflect.DeepEqual(batched[i], individual) {
+			t.Errorf("Asset [%d] decorated in a batch as [%+v], alone as [%+v].", a.Id(), batched[i], individual)
+		}
+	}
+
+	if _, ok := batched[1].ReferenceData().(asset.CashEquipableReferenceData); !ok {
+		t.Errorf("Expected cash equipment to carry cash equipable reference data, got [%T].", batched[1].ReferenceData())
+	}
+	if rd, ok := batched[2].ReferenceData().(asset.CashReferenceData); !ok || rd.Quantity() != 3 {
+		t.Errorf("Expected a cash item to carry its quantity, got [%+v].", batched[2].ReferenceData())
+	}
+	if !batched[3].Expiration().Equal(standInExpiration(4)) {
+		t.Errorf("Expected a pet to expire with its pet record at [%s], got [%s].", standInExpiration(4), batched[3].Expiration())
+	}
+
+	for _, a := range as {
+		s.miss(a.ReferenceId())
+		if _, err = p.DecorateAssets(as); err == nil {
+			t.Errorf("Expected decorating a batch to fail while the reference of asset [%d] is missing.", a.Id())
+		}
+		if _, err = p.DecorateAsset(a); err == nil {
+			t.Errorf("Expected decorating asset [%d] to fail while its reference is missing.", a.Id())
+		}
+	}
+}
+
+// BenchmarkDecorateAssetsIndividually decorates a full compartment one asset at a time, as loading a compartment
+// used to.
+func BenchmarkDecorateAssetsIndividually(b *testing.B) {
+	s := withStandIn(b)
+	p := testProcessor(b)
+	as := inventoryAssets(96)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := model.SliceMap(p.DecorateAsset)(model.FixedProvider(as))(model.ParallelMap())()
+		if err != nil {
+			b.Fatalf("Failed to decorate assets: %v", err)
+		}
+	}
+	b.ReportMetric(float64(s.calls.Load())/float64(b.N), "requests/op")
+}
+
+// BenchmarkDecorateAssetsBatched decorates a full compartment with one request per referenced service.
+func BenchmarkDecorateAssetsBatched(b *testing.B) {
+	s := withStandIn(b)
+	p := testProcessor(b)
+	as := inventoryAssets(96)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := p.DecorateAssets(as)
+		if err != nil {
+			b.Fatalf("Failed to decorate assets: %v", err)
+		}
+	}
+	b.ReportMetric(float64(s.calls.Load())/float64(b.N), "requests/op")
+}
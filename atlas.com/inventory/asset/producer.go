@@ -6,6 +6,7 @@ import (
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
+	"time"
 )
 
 func CreatedEventStatusProvider(transactionId uuid.UUID, characterId uint32, a Model[any]) model.Provider[[]kafka.Message] {
@@ -28,7 +29,7 @@ func CreatedEventStatusProvider(transactionId uuid.UUID, characterId uint32, a M
 	return producer.SingleMessageProvider(key, value)
 }
 
-func DeletedEventStatusProvider(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, assetId uint32, templateId uint32, slot int16) model.Provider[[]kafka.Message] {
+func DeletedEventStatusProvider(transactionId uuid.UUID, characterId uint32, compartmentId uuid.UUID, assetId uint32, templateId uint32, slot int16, reason string) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(assetId))
 	value := &asset.StatusEvent[asset.DeletedStatusEventBody]{
 		TransactionId: transactionId,
@@ -38,7 +39,26 @@ func DeletedEventStatusProvider(transactionId uuid.UUID, characterId uint32, com
 		TemplateId:    templateId,
 		Slot:          slot,
 		Type:          asset.StatusEventTypeDeleted,
-		Body:          asset.DeletedStatusEventBody{},
+		Body:          asset.DeletedStatusEventBody{Reason: reason},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+// ExpiringSoonEventStatusProvider warns that an asset expires within threshold, which is carried in seconds.
+func ExpiringSoonEventStatusProvider(transactionId uuid.UUID, characterId uint32, a Model[any], threshold time.Duration) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(a.Id()))
+	value := &asset.StatusEvent[asset.ExpiringSoonStatusEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: a.CompartmentId(),
+		AssetId:       a.Id(),
+		TemplateId:    a.TemplateId(),
+		Slot:          a.Slot(),
+		Type:          asset.StatusEventTypeExpiringSoon,
+		Body: asset.ExpiringSoonStatusEventBody{
+			Expiration: a.Expiration(),
+			Threshold:  int64(threshold.Seconds()),
+		},
 	}
 	return producer.SingleMessageProvider(key, value)
 }
@@ -2,6 +2,7 @@ package asset
 
 import (
 	"atlas-inventory/database"
+	"time"
 
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/google/uuid"
@@ -14,6 +15,12 @@ func getByCompartmentId(tenantId uuid.UUID, compartmentId uuid.UUID) database.En
 	}
 }
 
+func getAll(tenantId uuid.UUID) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Order("id"), &Entity{TenantId: tenantId})
+	}
+}
+
 func getBySlot(tenantId uuid.UUID, compartmentId uuid.UUID, slot int16) database.EntityProvider[Entity] {
 	return func(db *gorm.DB) model.Provider[Entity] {
 		return database.Query[Entity](db, &Entity{TenantId: tenantId, CompartmentId: compartmentId, Slot: slot})
@@ -31,3 +38,17 @@ func getById(tenantId uuid.UUID, id uint32) database.EntityProvider[Entity] {
 		return database.Query[Entity](db, &Entity{TenantId: tenantId, Id: id})
 	}
 }
+
+// getExpired yields the assets of a tenant whose expiration has passed as of now. A zero expiration never expires.
+func getExpired(tenantId uuid.UUID, now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("expiration > ? AND expiration <= ?", time.Time{}, now).Order("id"), &Entity{TenantId: tenantId})
+	}
+}
+
+// getExpiring yields the assets of a tenant which have not yet expired as of now, but will by the horizon.
+func getExpiring(tenantId uuid.UUID, now time.Time, horizon time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("expiration > ? AND expiration <= ?", now, horizon).Order("id"), &Entity{TenantId: tenantId})
+	}
+}
@@ -0,0 +1,317 @@
+package asset_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/cash"
+	"atlas-inventory/data/equipment/statistics"
+	"atlas-inventory/database"
+	"atlas-inventory/kafka/message"
+	"atlas-inventory/schema"
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus/hooks/test"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// referenceStandIn plays the cash shop and pet services, creating, updating and deleting references on request and
+// recording each change it is asked to make. Every equipment template it is asked about is cash equipment.
+type referenceStandIn struct {
+	mu         sync.Mutex
+	nextId     uint32
+	quantities map[uint32]uint32
+	changes    []string
+}
+
+func (s *referenceStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	resource := segments[len(segments)-1]
+	id := uint32(0)
+	if v, err := strconv.Atoi(resource); err == nil {
+		resource = segments[len(segments)-2]
+		id = uint32(v)
+	}
+
+	var data interface{}
+	switch {
+	case resource == "equipment" && r.Method == http.MethodGet:
+		data = statistics.RestModel{Id: id, Cash: true}
+	case r.Method == http.MethodPost:
+		s.nextId++
+		id = s.nextId
+		s.changes = append(s.changes, "create "+resource+" "+strconv.Itoa(int(id)))
+		body, _ := io.ReadAll(r.Body)
+		if resource == "pets" {
+			var rm referencePet
+			_ = jsonapi.Unmarshal(body, &rm)
+			data = referencePet{Id: id, TemplateId: rm.TemplateId, OwnerId: rm.OwnerId}
+		} else {
+			var rm cash.RestModel
+			_ = jsonapi.Unmarshal(body, &rm)
+			s.quantities[id] = rm.Quantity
+			data = cash.RestModel{Id: id, TemplateId: rm.TemplateId, Quantity: rm.Quantity, PurchasedBy: rm.PurchasedBy}
+		}
+	case r.Method == http.MethodPatch:
+		body, _ := io.ReadAll(r.Body)
+		var rm cash.RestModel
+		_ = jsonapi.Unmarshal(body, &rm)
+		s.quantities[id] = rm.Quantity
+		s.changes = append(s.changes, "update "+resource+" "+strconv.Itoa(int(id))+" "+strconv.Itoa(int(rm.Quantity)))
+		data = cash.RestModel{Id: id, Quantity: rm.Quantity}
+	case r.Method == http.MethodDelete:
+		s.changes = append(s.changes, "delete "+resource+" "+strconv.Itoa(int(id)))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := jsonapi.Marshal(data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	_, _ = w.Write(body)
+}
+
+// referencePet is the subset of a pet the stand-in reads and returns, mirroring pet.RestModel.
+type referencePet struct {
+	Id         uint32 `json:"-"`
+	TemplateId uint32 `json:"templateId"`
+	OwnerId    uint32 `json:"ownerId"`
+}
+
+func (r referencePet) GetName() string {
+	return "pets"
+}
+
+func (r referencePet) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *referencePet) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func (s *referenceStandIn) recorded() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.changes...)
+}
+
+func withReferenceStandIn(t *testing.T) *referenceStandIn {
+	s := &referenceStandIn{quantities: make(map[uint32]uint32)}
+	srv := httptest.NewServer(s)
+	target, _ := url.Parse(srv.URL)
+	previous := http.DefaultTransport
+	http.DefaultTransport = redirect{target: target, next: previous}
+	t.Cleanup(func() {
+		http.DefaultTransport = previous
+		srv.Close()
+	})
+	return s
+}
+
+func referenceDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+var errAbort = errors.New("abort")
+
+type referenceCase struct {
+	name          string
+	templateId    uint32
+	referenceType asset.ReferenceType
+	resource      string
+}
+
+var referenceCases = []referenceCase{
+	{name: "cash item", templateId: 5040000, referenceType: asset.ReferenceTypeCash, resource: "items"},
+	{name: "cash equipment", templateId: 1702000, referenceType: asset.ReferenceTypeCashEquipable, resource: "items"},
+	{name: "pet", templateId: 5000017, referenceType: asset.ReferenceTypePet, resource: "pets"},
+}
+
+// TestCreateReferences verifies that creating a cash item, cash equipment or pet creates its reference in the
+// owning service, and that the reference is deleted again when the transaction creating the asset rolls back.
+func TestCreateReferences(t *testing.T) {
+	for _, tc := range referenceCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := withReferenceStandIn(t)
+			db := referenceDatabase(t)
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			ctx := tenant.WithContext(context.Background(), te)
+			p := asset.NewProcessor(testLogger(), ctx, db)
+			compartmentId := uuid.New()
+
+			a, err := p.Create(message.NewBuffer())(uuid.New(), 7, compartmentId, tc.templateId, 1, 1, time.Time{}, 0, 0, 0)
+			if err != nil {
+				t.Fatalf("Failed to create asset: %v", err)
+			}
+			if a.ReferenceType() != tc.referenceType || a.ReferenceId() != 1 {
+				t.Fatalf("Asset refers to [%s] [%d], want [%s] [1].", a.ReferenceType(), a.ReferenceId(), tc.referenceType)
+			}
+			if a.ReferenceType() == asset.ReferenceTypeCash {
+				if rd, ok := a.ReferenceData().(asset.CashReferenceData); !ok || rd.OwnerId() != 7 || rd.PurchaseBy() != 7 {
+					t.Fatalf("Cash item [%v] is not owned and purchased by character [7].", a.ReferenceData())
+				}
+			}
+
+			err = database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+				_, err := p.WithTransaction(tx).Create(message.NewBuffer())(uuid.New(), 7, compartmentId, tc.templateId, 2, 1, time.Time{}, 0, 0, 0)
+				if err != nil {
+					return err
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("Expected the transaction to abort, got: %v", err)
+			}
+
+			want := []string{"create " + tc.resource + " 1", "create " + tc.resource + " 2", "delete " + tc.resource + " 2"}
+			if got := s.recorded(); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("Recorded %v, want %v.", got, want)
+			}
+			as, err := p.UndecoratedByCompartmentIdProvider(compartmentId)()
+			if err != nil {
+				t.Fatalf("Failed to read assets: %v", err)
+			}
+			if len(as) != 1 {
+				t.Fatalf("Found [%d] assets after the rollback, want 1.", len(as))
+			}
+		})
+	}
+}
+
+// TestDeleteReferences verifies that deleting a cash item, cash equipment or pet deletes its reference only once the
+// asset's deletion commits.
+func TestDeleteReferences(t *testing.T) {
+	for _, tc := range referenceCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := withReferenceStandIn(t)
+			db := referenceDatabase(t)
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			ctx := tenant.WithContext(context.Background(), te)
+			p := asset.NewProcessor(testLogger(), ctx, db)
+			compartmentId := uuid.New()
+
+			a, err := p.Create(message.NewBuffer())(uuid.New(), 7, compartmentId, tc.templateId, 1, 1, time.Time{}, 0, 0, 0)
+			if err != nil {
+				t.Fatalf("Failed to create asset: %v", err)
+			}
+
+			err = database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+				err := p.WithTransaction(tx).Delete(message.NewBuffer())(uuid.New(), 7, compartmentId)(a)
+				if err != nil {
+					return err
+				}
+				if got := s.recorded(); len(got) != 1 {
+					t.Errorf("Recorded %v before the deletion committed.", got)
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("Expected the transaction to abort, got: %v", err)
+			}
+			if got := s.recorded(); len(got) != 1 {
+				t.Fatalf("Recorded %v after the deletion rolled back, want only the creation.", got)
+			}
+			if _, err = p.UndecoratedByIdProvider(a.Id())(); err != nil {
+				t.Fatalf("Asset was deleted despite the rollback: %v", err)
+			}
+
+			err = p.Delete(message.NewBuffer())(uuid.New(), 7, compartmentId)(a)
+			if err != nil {
+				t.Fatalf("Failed to delete asset: %v", err)
+			}
+			want := []string{"create " + tc.resource + " 1", "delete " + tc.resource + " 1"}
+			if got := s.recorded(); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("Recorded %v, want %v.", got, want)
+			}
+			if _, err = p.UndecoratedByIdProvider(a.Id())(); !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Fatalf("Expected the asset to be deleted, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestUpdateReferences verifies that the quantity of a cash item is updated in the cash shop, and restored there when
+// the transaction updating it rolls back. Neither cash equipment nor pets have a quantity to update.
+func TestUpdateReferences(t *testing.T) {
+	for _, tc := range referenceCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := withReferenceStandIn(t)
+			db := referenceDatabase(t)
+			te, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+			ctx := tenant.WithContext(context.Background(), te)
+			p := asset.NewProcessor(testLogger(), ctx, db)
+			compartmentId := uuid.New()
+
+			a, err := p.Create(message.NewBuffer())(uuid.New(), 7, compartmentId, tc.templateId, 1, 3, time.Time{}, 0, 0, 0)
+			if err != nil {
+				t.Fatalf("Failed to create asset: %v", err)
+			}
+
+			err = database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+				err := p.WithTransaction(tx).UpdateQuantity(message.NewBuffer())(uuid.New(), 7, compartmentId, a, 9)
+				if err != nil {
+					return err
+				}
+				return errAbort
+			})
+			if tc.referenceType != asset.ReferenceTypeCash {
+				if err == nil || errors.Is(err, errAbort) {
+					t.Fatalf("Expected updating the quantity of a [%s] to fail, got: %v", tc.referenceType, err)
+				}
+				if got := s.recorded(); len(got) != 1 {
+					t.Fatalf("Recorded %v, want only the creation.", got)
+				}
+				return
+			}
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("Expected the transaction to abort, got: %v", err)
+			}
+
+			err = p.UpdateQuantity(message.NewBuffer())(uuid.New(), 7, compartmentId, a, 5)
+			if err != nil {
+				t.Fatalf("Failed to update quantity: %v", err)
+			}
+
+			want := []string{"create items 1", "update items 1 9", "update items 1 3", "update items 1 5"}
+			if got := s.recorded(); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Fatalf("Recorded %v, want %v.", got, want)
+			}
+			if q := s.quantities[1]; q != 5 {
+				t.Fatalf("Cash item quantity is [%d], want 5.", q)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ type BaseRestModel struct {
 	ReferenceId   uint32      `json:"referenceId"`
 	ReferenceType string      `json:"referenceType"`
 	ReferenceData interface{} `json:"referenceData"`
+	Reserved      uint32      `json:"reserved"`
 }
 
 func (r BaseRestModel) GetName() string {
@@ -206,6 +207,7 @@ func Transform(m Model[any]) (BaseRestModel, error) {
 		Expiration:    m.expiration,
 		ReferenceId:   m.referenceId,
 		ReferenceType: string(m.referenceType),
+		Reserved:      m.reserved,
 	}
 	if m.ReferenceType() == ReferenceTypeEquipable {
 		if em, ok := m.referenceData.(EquipableReferenceData); ok {
@@ -340,6 +342,7 @@ func Extract(rm BaseRestModel) (Model[any], error) {
 		expiration:    rm.Expiration,
 		referenceId:   rm.ReferenceId,
 		referenceType: ReferenceType(rm.ReferenceType),
+		reserved:      rm.Reserved,
 	}
 
 	if erm, ok := rm.ReferenceData.(EquipableRestData); ok {
@@ -26,7 +26,24 @@ func (p *Processor) ByEquipmentIdModelProvider(id uint32) model.Provider[Model]
 	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(id), Extract)
 }
 
+// ByIdsProvider yields the cash items with the given ids in one request. Ids the cash shop does not know are omitted.
+func (p *Processor) ByIdsProvider(ids []uint32) model.Provider[[]Model] {
+	if len(ids) == 0 {
+		return model.FixedProvider[[]Model](nil)
+	}
+	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByIds(ids), Extract, model.Filters[Model]())
+}
+
+// Create issues a new cash item of the template to the character, returning the record held by the cash shop.
+func (p *Processor) Create(characterId uint32, templateId uint32, quantity uint32) (Model, error) {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestCreate(templateId, quantity, characterId), Extract)()
+}
+
 func (p *Processor) UpdateQuantity(itemId uint32, quantity uint32) error {
-	//TODO
-	return nil
+	_, err := requestUpdateQuantity(itemId, quantity)(p.l, p.ctx)
+	return err
+}
+
+func (p *Processor) Delete(itemId uint32) error {
+	return deleteById(itemId)(p.l, p.ctx)
 }
@@ -10,6 +10,7 @@ import (
 const (
 	itemsResource = "cash-shop/items"
 	itemResource  = itemsResource + "/%d"
+	itemsByIds    = itemsResource + "?filter[ids]=%s"
 )
 
 func getBaseRequest() string {
@@ -19,3 +20,28 @@ func getBaseRequest() string {
 func requestById(id uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+itemResource, id))
 }
+
+func requestByIds(ids []uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+itemsByIds, rest.IdList(ids)))
+}
+
+func requestCreate(templateId uint32, quantity uint32, purchasedBy uint32) requests.Request[RestModel] {
+	input := &RestModel{
+		TemplateId:  templateId,
+		Quantity:    quantity,
+		PurchasedBy: purchasedBy,
+	}
+	return rest.MakePostRequest[RestModel](getBaseRequest()+itemsResource, input)
+}
+
+func requestUpdateQuantity(id uint32, quantity uint32) requests.Request[RestModel] {
+	input := &RestModel{
+		Id:       id,
+		Quantity: quantity,
+	}
+	return rest.MakePatchRequest[RestModel](fmt.Sprintf(getBaseRequest()+itemResource, id), input)
+}
+
+func deleteById(id uint32) requests.EmptyBodyRequest {
+	return rest.MakeDeleteRequest(fmt.Sprintf(getBaseRequest()+itemResource, id))
+}
@@ -0,0 +1,44 @@
+package character
+
+type Model struct {
+	id           uint32
+	level        byte
+	jobId        uint16
+	strength     uint16
+	dexterity    uint16
+	intelligence uint16
+	luck         uint16
+	gm           int
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) Level() byte {
+	return m.level
+}
+
+func (m Model) JobId() uint16 {
+	return m.jobId
+}
+
+func (m Model) Strength() uint16 {
+	return m.strength
+}
+
+func (m Model) Dexterity() uint16 {
+	return m.dexterity
+}
+
+func (m Model) Intelligence() uint16 {
+	return m.intelligence
+}
+
+func (m Model) Luck() uint16 {
+	return m.luck
+}
+
+func (m Model) Gm() bool {
+	return m.gm > 0
+}
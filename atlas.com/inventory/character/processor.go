@@ -0,0 +1,30 @@
+package character
+
+import (
+	"context"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+	}
+	return p
+}
+
+func (p *Processor) ByIdProvider(characterId uint32) model.Provider[Model] {
+	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(characterId), Extract)
+}
+
+func (p *Processor) GetById(characterId uint32) (Model, error) {
+	return p.ByIdProvider(characterId)()
+}
@@ -0,0 +1,20 @@
+package character
+
+import (
+	"atlas-inventory/rest"
+	"fmt"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	Resource = "characters"
+	ById     = Resource + "/%d"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("CHARACTERS")
+}
+
+func requestById(characterId uint32) requests.Request[RestModel] {
+	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+ById, characterId))
+}
@@ -0,0 +1,45 @@
+package character
+
+import "strconv"
+
+type RestModel struct {
+	Id           uint32 `json:"-"`
+	Name         string `json:"name"`
+	Level        byte   `json:"level"`
+	JobId        uint16 `json:"jobId"`
+	Strength     uint16 `json:"strength"`
+	Dexterity    uint16 `json:"dexterity"`
+	Intelligence uint16 `json:"intelligence"`
+	Luck         uint16 `json:"luck"`
+	Gm           int    `json:"gm"`
+}
+
+func (r RestModel) GetName() string {
+	return "characters"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Extract(rm RestModel) (Model, error) {
+	return Model{
+		id:           rm.Id,
+		level:        rm.Level,
+		jobId:        rm.JobId,
+		strength:     rm.Strength,
+		dexterity:    rm.Dexterity,
+		intelligence: rm.Intelligence,
+		luck:         rm.Luck,
+		gm:           rm.Gm,
+	}, nil
+}
@@ -0,0 +1,138 @@
+package compartment
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/configuration"
+	"atlas-inventory/kafka/message/compartment"
+	"errors"
+	"fmt"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-constants/item"
+)
+
+// SlotConflicts evaluates rules for equipping templateId into destination, given the other equipment worn. It returns
+// the worn assets to unequip, or the rule which rejects the equip.
+func SlotConflicts(rules []configuration.SlotConflictRule, templateId uint32, destination int16, worn []asset.Model[any]) ([]asset.Model[any], *configuration.SlotConflictRule) {
+	classification := uint32(item.GetClassification(item.Id(templateId)))
+	var results []asset.Model[any]
+	unequipping := make(map[uint32]bool)
+	for i := range rules {
+		r := rules[i]
+		if !r.Equipping.Matches(classification, destination) {
+			continue
+		}
+		var conflicting []asset.Model[any]
+		for _, a := range worn {
+			if a.Slot() == destination || a.Slot() >= 0 {
+				continue
+			}
+			if !r.AnyLayer && cashLayer(a.Slot()) != cashLayer(destination) {
+				continue
+			}
+			if r.SameTemplate && a.TemplateId() != templateId {
+				continue
+			}
+			if r.Equipped.Matches(uint32(item.GetClassification(item.Id(a.TemplateId()))), a.Slot()) {
+				conflicting = append(conflicting, a)
+			}
+		}
+		if uint32(len(conflicting)) <= r.Limit {
+			continue
+		}
+		if r.Action == configuration.SlotConflictActionReject {
+			return nil, &r
+		}
+		for _, a := range conflicting[r.Limit:] {
+			if !unequipping[a.Id()] {
+				unequipping[a.Id()] = true
+				results = append(results, a)
+			}
+		}
+	}
+	return results, nil
+}
+
+// LoadoutDisplaces works out which worn assets putting on a loadout takes off: those in the slots it fills, and those
+// the slot conflict rules unequip for any item it puts on. targets maps each asset the loadout wears, among as, to its
+// slot. A loadout fails with SLOT_CONFLICT where a rule rejects one of its items, or would take off another of them.
+func LoadoutDisplaces(rules []configuration.SlotConflictRule, as []asset.Model[any], targets map[uint32]int16) ([]asset.Model[any], error) {
+	filled := make(map[int16]bool)
+	for _, t := range targets {
+		filled[t] = true
+	}
+	var results []asset.Model[any]
+	displaced := make(map[uint32]bool)
+	after := make([]asset.Model[any], 0, len(as))
+	for _, a := range as {
+		if t, ok := targets[a.Id()]; ok {
+			after = append(after, asset.Clone(a).SetSlot(t).Build())
+		} else if a.Slot() < 0 && filled[a.Slot()] {
+			displaced[a.Id()] = true
+			results = append(results, a)
+		} else {
+			after = append(after, a)
+		}
+	}
+	for _, a := range after {
+		if _, ok := targets[a.Id()]; !ok {
+			continue
+		}
+		worn := make([]asset.Model[any], 0, len(after))
+		for _, o := range after {
+			if o.Id() != a.Id() && !displaced[o.Id()] {
+				worn = append(worn, o)
+			}
+		}
+		for i := range rules {
+			unequip, rejectedBy := SlotConflicts(rules[i:i+1], a.TemplateId(), a.Slot(), worn)
+			if rejectedBy != nil {
+				return nil, Error{Reason: compartment.ErrorReasonSlotConflict, InventoryType: inventory.TypeValueEquip, Slot: a.Slot(), cause: fmt.Errorf("rejected by slot conflict rule [%s]", rejectedBy.Name)}
+			}
+			for _, u := range unequip {
+				if _, ok := targets[u.Id()]; ok {
+					return nil, Error{Reason: compartment.ErrorReasonSlotConflict, InventoryType: inventory.TypeValueEquip, Slot: a.Slot(), cause: fmt.Errorf("slot conflict rule [%s] would unequip asset [%d] of the loadout", rules[i].Name, u.Id())}
+				}
+				if !displaced[u.Id()] {
+					displaced[u.Id()] = true
+					results = append(results, u)
+				}
+			}
+		}
+	}
+	return results, nil
+}
+
+// cashLayer reports whether an equipped slot holds cash equipment, which is worn over the regular slot 100 below.
+func cashLayer(slot int16) bool {
+	return slot <= -100
+}
+
+// freeSlots tracks the inventory slots of a compartment available to unequip into.
+type freeSlots struct {
+	capacity uint32
+	occupied map[int16]bool
+}
+
+func newFreeSlots(c Model) *freeSlots {
+	f := &freeSlots{capacity: c.Capacity(), occupied: make(map[int16]bool)}
+	for _, a := range c.Assets() {
+		f.occupied[a.Slot()] = true
+	}
+	return f
+}
+
+func (f *freeSlots) release(slot int16) {
+	delete(f.occupied, slot)
+}
+
+// next claims the lowest free slot.
+func (f *freeSlots) next() (int16, error) {
+	for s := int16(1); uint32(s) <= f.capacity; s++ {
+		if !f.occupied[s] {
+			f.occupied[s] = true
+			return s, nil
+		}
+	}
+	return 0, errors.New("no free slots")
+}
@@ -0,0 +1,137 @@
+package compartment_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/compartment"
+	"atlas-inventory/configuration"
+	compartment2 "atlas-inventory/kafka/message/compartment"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func worn(compartmentId uuid.UUID, id uint32, templateId uint32, slot int16) asset.Model[any] {
+	return asset.NewBuilder[any](id, compartmentId, templateId, id, asset.ReferenceTypeEquipable).SetSlot(slot).Build()
+}
+
+func assetIds(as []asset.Model[any]) []uint32 {
+	results := make([]uint32, 0, len(as))
+	for _, a := range as {
+		results = append(results, a.Id())
+	}
+	return results
+}
+
+// TestSlotConflicts verifies the built-in rules unequip overalls and pants, shields and two-handed weapons, on the
+// layer being equipped, and reject a second copy of a ring.
+func TestSlotConflicts(t *testing.T) {
+	rules := configuration.GetTenantConfig(uuid.New()).Equip.SlotConflicts
+	cid := uuid.New()
+	top := worn(cid, 1, 1040002, -5)
+	pants := worn(cid, 2, 1060002, -6)
+	overall := worn(cid, 3, 1050000, -5)
+	shield := worn(cid, 4, 1092000, -10)
+	claw := worn(cid, 5, 1472000, -11)
+	ring := worn(cid, 6, 1112400, -12)
+	cashPants := worn(cid, 7, 1062000, -106)
+
+	tests := []struct {
+		name        string
+		templateId  uint32
+		destination int16
+		worn        []asset.Model[any]
+		unequip     []uint32
+		rejected    string
+	}{
+		{"overall displaces pants", 1050000, -5, []asset.Model[any]{top, pants, cashPants}, []uint32{2}, ""},
+		{"pants displace an overall", 1060002, -6, []asset.Model[any]{overall}, []uint32{3}, ""},
+		{"pants leave a top", 1060002, -6, []asset.Model[any]{top}, []uint32{}, ""},
+		{"cash overall displaces cash pants", 1052000, -105, []asset.Model[any]{pants, cashPants}, []uint32{7}, ""},
+		{"two-handed sword displaces a shield", 1402000, -11, []asset.Model[any]{shield}, []uint32{4}, ""},
+		{"shield displaces a claw", 1092000, -10, []asset.Model[any]{claw}, []uint32{5}, ""},
+		{"one-handed sword keeps a shield", 1302000, -11, []asset.Model[any]{shield}, []uint32{}, ""},
+		{"second copy of a ring", 1112400, -13, []asset.Model[any]{ring}, nil, "duplicate-ring"},
+		{"different ring", 1112401, -13, []asset.Model[any]{ring}, []uint32{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unequip, rejectedBy := compartment.SlotConflicts(rules, tt.templateId, tt.destination, tt.worn)
+			if tt.rejected != "" {
+				if rejectedBy == nil || rejectedBy.Name != tt.rejected {
+					t.Fatalf("Expected the equip to be rejected by [%s], got [%v].", tt.rejected, rejectedBy)
+				}
+				return
+			}
+			if rejectedBy != nil {
+				t.Fatalf("Expected the equip to be allowed, rejected by [%s].", rejectedBy.Name)
+			}
+			if !slices.Equal(assetIds(unequip), tt.unequip) {
+				t.Fatalf("Expected [%v] to be unequipped, got [%v].", tt.unequip, assetIds(unequip))
+			}
+		})
+	}
+}
+
+// TestSlotConflictLimit verifies a rule applies only once more than its limit of worn equipment conflicts, and across
+// layers when configured.
+func TestSlotConflictLimit(t *testing.T) {
+	rules := []configuration.SlotConflictRule{{
+		Name:      "two-medals",
+		Equipping: configuration.SlotConflictMatch{Classifications: []uint32{114}},
+		Equipped:  configuration.SlotConflictMatch{Classifications: []uint32{114}},
+		AnyLayer:  true,
+		Limit:     1,
+		Action:    configuration.SlotConflictActionUnequip,
+	}}
+	cid := uuid.New()
+	first := worn(cid, 1, 1142000, -49)
+	second := worn(cid, 2, 1142001, -149)
+
+	unequip, rejectedBy := compartment.SlotConflicts(rules, 1142002, -50, []asset.Model[any]{first})
+	if rejectedBy != nil || len(unequip) != 0 {
+		t.Fatalf("Expected one worn medal to be tolerated.")
+	}
+	unequip, rejectedBy = compartment.SlotConflicts(rules, 1142002, -50, []asset.Model[any]{first, second})
+	if rejectedBy != nil || !slices.Equal(assetIds(unequip), []uint32{2}) {
+		t.Fatalf("Expected the medal beyond the limit to be unequipped, got [%v].", assetIds(unequip))
+	}
+}
+
+// TestLoadoutDisplaces verifies a loadout takes off what holds the slots it fills and what the rules unequip for its
+// items, and is rejected where a rule refuses one of its items or would take off another.
+func TestLoadoutDisplaces(t *testing.T) {
+	rules := configuration.GetTenantConfig(uuid.New()).Equip.SlotConflicts
+	cid := uuid.New()
+	top := worn(cid, 1, 1040002, -5)
+	pants := worn(cid, 2, 1060002, -6)
+	sword := worn(cid, 3, 1302000, -11)
+	shield := worn(cid, 4, 1092000, -10)
+	ring := worn(cid, 5, 1112400, -12)
+	overall := worn(cid, 6, 1050000, 1)
+	claw := worn(cid, 7, 1472000, 2)
+	secondRing := worn(cid, 8, 1112400, 3)
+	as := []asset.Model[any]{top, pants, sword, shield, ring, overall, claw, secondRing}
+
+	displaced, err := compartment.LoadoutDisplaces(rules, as, map[uint32]int16{6: -5, 7: -11})
+	if err != nil {
+		t.Fatalf("Expected the loadout to be allowed, got [%v].", err)
+	}
+	if !slices.Equal(assetIds(displaced), []uint32{1, 3, 2, 4}) {
+		t.Fatalf("Expected the top, sword, pants and shield to be taken off, got [%v].", assetIds(displaced))
+	}
+
+	displaced, err = compartment.LoadoutDisplaces(rules, as, map[uint32]int16{1: -5, 2: -6, 3: -11})
+	if err != nil || len(displaced) != 0 {
+		t.Fatalf("Expected the equipment worn to be kept, got [%v] and error [%v].", assetIds(displaced), err)
+	}
+
+	_, err = compartment.LoadoutDisplaces(rules, as, map[uint32]int16{6: -5, 2: -6})
+	if compartment.Classify(err).Reason != compartment2.ErrorReasonSlotConflict {
+		t.Fatalf("Expected an overall and pants together to conflict, got [%v].", err)
+	}
+	_, err = compartment.LoadoutDisplaces(rules, as, map[uint32]int16{8: -13})
+	if compartment.Classify(err).Reason != compartment2.ErrorReasonSlotConflict {
+		t.Fatalf("Expected a second copy of a ring to be rejected, got [%v].", err)
+	}
+}
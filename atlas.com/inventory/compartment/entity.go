@@ -6,15 +6,11 @@ import (
 	"gorm.io/gorm"
 )
 
-func Migration(db *gorm.DB) error {
-	return db.AutoMigrate(&Entity{})
-}
-
 type Entity struct {
-	TenantId      uuid.UUID      `gorm:"not null"`
+	TenantId      uuid.UUID      `gorm:"not null;uniqueIndex:idx_compartments_tenant_character_type,priority:1"`
 	Id            uuid.UUID      `gorm:"primaryKey;type:uuid;"`
-	CharacterId   uint32         `gorm:"not null"`
-	InventoryType inventory.Type `gorm:"not null"`
+	CharacterId   uint32         `gorm:"not null;uniqueIndex:idx_compartments_tenant_character_type,priority:2"`
+	InventoryType inventory.Type `gorm:"not null;uniqueIndex:idx_compartments_tenant_character_type,priority:3"`
 	Capacity      uint32         `gorm:"capacity"`
 }
 
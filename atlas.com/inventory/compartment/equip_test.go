@@ -0,0 +1,417 @@
+package compartment_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/character"
+	"atlas-inventory/compartment"
+	"atlas-inventory/data/equipment/slot"
+	statistics2 "atlas-inventory/data/equipment/statistics"
+	"atlas-inventory/equipable"
+	"atlas-inventory/kafka/message"
+	compartment2 "atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/loadout"
+	"atlas-inventory/outbox"
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// equipData is the equipment data served for an item: the slot it is worn in and the level it requires. Pet equipment
+// is worn in whichever pet's slot is requested.
+type equipData struct {
+	slot     int16
+	reqLevel byte
+	petEquip bool
+}
+
+// equipStandIn serves the characters, equipment data and equipables read when equipping.
+type equipStandIn struct {
+	characters map[uint32]character.RestModel
+	items      map[uint32]equipData
+}
+
+func (s *equipStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var data interface{}
+	switch {
+	case len(segments) >= 2 && segments[len(segments)-2] == "characters":
+		id, _ := strconv.Atoi(segments[len(segments)-1])
+		c, ok := s.characters[uint32(id)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		c.Id = uint32(id)
+		data = c
+	case len(segments) >= 4 && segments[len(segments)-1] == "slots":
+		id, _ := strconv.Atoi(segments[len(segments)-2])
+		d, ok := s.items[uint32(id)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		name := "Slot"
+		if d.petEquip {
+			name = "PET_EQUIP"
+		}
+		data = []slot.RestModel{{Id: strconv.Itoa(int(d.slot)), Name: name, Slot: d.slot}}
+	case len(segments) >= 3 && segments[len(segments)-2] == "equipment":
+		id, _ := strconv.Atoi(segments[len(segments)-1])
+		d, ok := s.items[uint32(id)]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data = statistics2.RestModel{Id: uint32(id), ReqLevel: d.reqLevel, Cash: d.petEquip}
+	case segments[len(segments)-1] == "equipables":
+		var rms []equipable.RestModel
+		for _, str := range strings.Split(r.URL.Query().Get("filter[ids]"), ",") {
+			id, _ := strconv.Atoi(str)
+			rms = append(rms, equipable.RestModel{Id: uint32(id)})
+		}
+		data = rms
+	case len(segments) >= 2 && segments[len(segments)-2] == "equipables":
+		id, _ := strconv.Atoi(segments[len(segments)-1])
+		data = equipable.RestModel{Id: uint32(id)}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	body, err := jsonapi.Marshal(data)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	_, _ = w.Write(body)
+}
+
+// redirect routes every outbound request to the stand-in server, whatever service it was addressed to.
+type redirect struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+func (r redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = r.target.Scheme
+	req.URL.Host = r.target.Host
+	req.Host = r.target.Host
+	if !strings.HasPrefix(req.URL.Path, "/") {
+		req.URL.Path = "/" + req.URL.Path
+	}
+	return r.next.RoundTrip(req)
+}
+
+func withEquipStandIn(t *testing.T, s *equipStandIn) {
+	srv := httptest.NewServer(s)
+	target, _ := url.Parse(srv.URL)
+	previous := http.DefaultTransport
+	http.DefaultTransport = redirect{target: target, next: previous}
+	t.Cleanup(func() {
+		http.DefaultTransport = previous
+		srv.Close()
+	})
+}
+
+// createEquipment places an equipable asset of templateId in slot of the compartment.
+func createEquipment(t *testing.T, db *gorm.DB, te tenant.Model, compartmentId uuid.UUID, slot int16, templateId uint32) uint32 {
+	e := asset.Entity{TenantId: te.Id(), CompartmentId: compartmentId, Slot: slot, TemplateId: templateId, Expiration: time.Time{}, ReferenceId: templateId, ReferenceType: string(asset.ReferenceTypeEquipable)}
+	if err := db.Create(&e).Error; err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	return e.Id
+}
+
+// equipmentSlots maps the slots of a character's equipment compartment to the templates in them.
+func equipmentSlots(t *testing.T, cp *compartment.Processor, characterId uint32) map[int16]uint32 {
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	slots := make(map[int16]uint32)
+	for _, a := range c.Assets() {
+		slots[a.Slot()] = a.TemplateId()
+	}
+	return slots
+}
+
+// TestEquipItemChecksRequirements verifies an item is worn when the character meets its requirements, and is left in
+// place with the reason otherwise, including when the character service does not know the character.
+func TestEquipItemChecksRequirements(t *testing.T) {
+	characterId := uint32(1)
+	unknownId := uint32(2)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	withEquipStandIn(t, &equipStandIn{
+		characters: map[uint32]character.RestModel{characterId: {Level: 10}},
+		items:      map[uint32]equipData{1302000: {slot: -11}, 1040002: {slot: -5, reqLevel: 30}},
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	for _, id := range []uint32{characterId, unknownId} {
+		c, err := cp.Create(mb)(uuid.New(), id, inventory.TypeValueEquip, 24)
+		if err != nil {
+			t.Fatalf("Failed to create compartment: %v", err)
+		}
+		createEquipment(t, db, te, c.Id(), 1, 1302000)
+		createEquipment(t, db, te, c.Id(), 2, 1040002)
+	}
+
+	err := cp.EquipItem(mb)(uuid.New(), characterId, 1, -11)
+	if err != nil {
+		t.Fatalf("Failed to equip item: %v", err)
+	}
+	err = cp.EquipItem(mb)(uuid.New(), characterId, 2, -5)
+	var ce compartment.Error
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonRequirementsNotMet || ce.Slot != 2 {
+		t.Fatalf("Expected equipping an item above the character's level to fail with [%s], got [%v].", compartment2.ErrorReasonRequirementsNotMet, err)
+	}
+	slots := equipmentSlots(t, cp, characterId)
+	if slots[-11] != 1302000 || slots[2] != 1040002 || len(slots) != 2 {
+		t.Fatalf("Unexpected slots after equipping [%+v].", slots)
+	}
+
+	err = cp.EquipItem(mb)(uuid.New(), unknownId, 1, -11)
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonCharacterNotFound {
+		t.Fatalf("Expected equipping for an unknown character to fail with [%s], got [%v].", compartment2.ErrorReasonCharacterNotFound, err)
+	}
+	slots = equipmentSlots(t, cp, unknownId)
+	if slots[1] != 1302000 || len(slots) != 2 {
+		t.Fatalf("Unexpected slots after a failed equip [%+v].", slots)
+	}
+}
+
+// loadoutApplied is the LOADOUT_APPLIED event staged for a transaction, failing the test unless exactly one was.
+func loadoutApplied(t *testing.T, l logrus.FieldLogger, ctx context.Context, db *gorm.DB, transactionId uuid.UUID) compartment2.LoadoutAppliedEventBody {
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(1000)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	var results []compartment2.LoadoutAppliedEventBody
+	for _, m := range ms {
+		var e compartment2.StatusEvent[compartment2.LoadoutAppliedEventBody]
+		if err = json.Unmarshal(m.Value(), &e); err != nil {
+			continue
+		}
+		if e.TransactionId == transactionId && e.Type == compartment2.StatusEventTypeLoadoutApplied {
+			results = append(results, e.Body)
+		}
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected a single LOADOUT_APPLIED event, got [%d].", len(results))
+	}
+	return results[0]
+}
+
+// TestApplyLoadoutSwapsWornItems verifies a loadout can exchange the slots of two items already worn.
+func TestApplyLoadoutSwapsWornItems(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	withEquipStandIn(t, &equipStandIn{
+		characters: map[uint32]character.RestModel{characterId: {Level: 10}},
+		items:      map[uint32]equipData{1802000: {slot: -114, petEquip: true}, 1802001: {slot: -114, petEquip: true}},
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueEquip, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	first := createEquipment(t, db, te, c.Id(), -114, 1802000)
+	second := createEquipment(t, db, te, c.Id(), -122, 1802001)
+	lo, err := loadout.NewProcessor(l, ctx, db).Create(characterId, "Swapped", []loadout.Slot{loadout.NewSlot(-122, first), loadout.NewSlot(-114, second)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+
+	transactionId := uuid.New()
+	err = cp.ApplyLoadout(mb)(transactionId, characterId, lo.Id(), false)
+	if err != nil {
+		t.Fatalf("Failed to apply loadout: %v", err)
+	}
+	slots := equipmentSlots(t, cp, characterId)
+	if slots[-122] != 1802000 || slots[-114] != 1802001 || len(slots) != 2 {
+		t.Fatalf("Unexpected slots after applying loadout [%+v].", slots)
+	}
+	e := loadoutApplied(t, l, ctx, db, transactionId)
+	if e.LoadoutId != lo.Id() || len(e.Equipped) != 2 || len(e.Unequipped) != 0 || len(e.Skipped) != 0 {
+		t.Fatalf("Unexpected LOADOUT_APPLIED event [%+v].", e)
+	}
+}
+
+// TestApplyLoadoutReplacesWornItem verifies an item put on from the inventory sends the item worn in its slot back to
+// the inventory slot it came from, announced by a single LOADOUT_APPLIED event.
+func TestApplyLoadoutReplacesWornItem(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	withEquipStandIn(t, &equipStandIn{
+		characters: map[uint32]character.RestModel{characterId: {Level: 10}},
+		items:      map[uint32]equipData{1302000: {slot: -11}, 1302001: {slot: -11}},
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueEquip, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	worn := createEquipment(t, db, te, c.Id(), -11, 1302000)
+	carried := createEquipment(t, db, te, c.Id(), 3, 1302001)
+	lo, err := loadout.NewProcessor(l, ctx, db).Create(characterId, "Replaced", []loadout.Slot{loadout.NewSlot(-11, carried)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+
+	transactionId := uuid.New()
+	err = cp.ApplyLoadout(mb)(transactionId, characterId, lo.Id(), false)
+	if err != nil {
+		t.Fatalf("Failed to apply loadout: %v", err)
+	}
+	slots := equipmentSlots(t, cp, characterId)
+	if slots[-11] != 1302001 || slots[3] != 1302000 || len(slots) != 2 {
+		t.Fatalf("Unexpected slots after applying loadout [%+v].", slots)
+	}
+	e := loadoutApplied(t, l, ctx, db, transactionId)
+	if len(e.Equipped) != 1 || e.Equipped[0].AssetId != carried || e.Equipped[0].Slot != -11 {
+		t.Fatalf("Unexpected equipped items [%+v].", e.Equipped)
+	}
+	if len(e.Unequipped) != 1 || e.Unequipped[0].AssetId != worn || e.Unequipped[0].Slot != 3 {
+		t.Fatalf("Unexpected unequipped items [%+v].", e.Unequipped)
+	}
+}
+
+// TestApplyLoadoutMissingAssets verifies a loadout naming an asset the character no longer has fails unless missing
+// assets are skipped, in which case the rest is put on and the slot is reported as skipped.
+func TestApplyLoadoutMissingAssets(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	withEquipStandIn(t, &equipStandIn{
+		characters: map[uint32]character.RestModel{characterId: {Level: 10}},
+		items:      map[uint32]equipData{1302000: {slot: -11}, 1002000: {slot: -1}},
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueEquip, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	weapon := createEquipment(t, db, te, c.Id(), 1, 1302000)
+	hat := createEquipment(t, db, te, c.Id(), 2, 1002000)
+	lo, err := loadout.NewProcessor(l, ctx, db).Create(characterId, "Incomplete", []loadout.Slot{loadout.NewSlot(-11, weapon), loadout.NewSlot(-1, hat)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+	if err = db.Delete(&asset.Entity{}, hat).Error; err != nil {
+		t.Fatalf("Failed to delete asset: %v", err)
+	}
+
+	err = cp.ApplyLoadout(mb)(uuid.New(), characterId, lo.Id(), false)
+	var ce compartment.Error
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonAssetNotFound || ce.Slot != -1 {
+		t.Fatalf("Expected the loadout to fail with [%s] for slot -1, got [%v].", compartment2.ErrorReasonAssetNotFound, err)
+	}
+	slots := equipmentSlots(t, cp, characterId)
+	if slots[1] != 1302000 || len(slots) != 1 {
+		t.Fatalf("Unexpected slots after a failed loadout [%+v].", slots)
+	}
+
+	transactionId := uuid.New()
+	err = cp.ApplyLoadout(mb)(transactionId, characterId, lo.Id(), true)
+	if err != nil {
+		t.Fatalf("Failed to apply loadout: %v", err)
+	}
+	slots = equipmentSlots(t, cp, characterId)
+	if slots[-11] != 1302000 || len(slots) != 1 {
+		t.Fatalf("Unexpected slots after applying loadout [%+v].", slots)
+	}
+	e := loadoutApplied(t, l, ctx, db, transactionId)
+	if len(e.Equipped) != 1 || e.Equipped[0].AssetId != weapon || len(e.Skipped) != 1 || e.Skipped[0].AssetId != hat || e.Skipped[0].Slot != -1 {
+		t.Fatalf("Unexpected LOADOUT_APPLIED event [%+v].", e)
+	}
+}
+
+// TestApplyLoadoutRollsBack verifies a loadout failing part way through leaves the items it already moved where they
+// were, and announces nothing.
+func TestApplyLoadoutRollsBack(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	withEquipStandIn(t, &equipStandIn{
+		characters: map[uint32]character.RestModel{characterId: {Level: 10}},
+		items:      map[uint32]equipData{1040002: {slot: -5}, 1302000: {slot: -11}, 1302001: {slot: -11}},
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueEquip, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	top := createEquipment(t, db, te, c.Id(), 1, 1040002)
+	weapon := createEquipment(t, db, te, c.Id(), 2, 1302001)
+	createEquipment(t, db, te, c.Id(), -11, 1302000)
+	lo, err := loadout.NewProcessor(l, ctx, db).Create(characterId, "Interrupted", []loadout.Slot{loadout.NewSlot(-5, top), loadout.NewSlot(-11, weapon)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+	// An asset stuck in the temporary slot makes swapping out the worn weapon fail after the top is put on.
+	createEquipment(t, db, te, c.Id(), math.MinInt16, 1302000)
+
+	transactionId := uuid.New()
+	err = cp.ApplyLoadout(mb)(transactionId, characterId, lo.Id(), false)
+	var ce compartment.Error
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonSlotOccupied {
+		t.Fatalf("Expected the loadout to fail with [%s], got [%v].", compartment2.ErrorReasonSlotOccupied, err)
+	}
+	slots := equipmentSlots(t, cp, characterId)
+	if slots[1] != 1040002 || slots[2] != 1302001 || slots[-11] != 1302000 || slots[-5] != 0 {
+		t.Fatalf("Expected the loadout to be rolled back, got [%+v].", slots)
+	}
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(1000)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	for _, m := range ms {
+		var e compartment2.StatusEvent[json.RawMessage]
+		if json.Unmarshal(m.Value(), &e) == nil && e.TransactionId == transactionId {
+			t.Fatalf("Expected nothing to be announced for a failed loadout, got [%s].", e.Type)
+		}
+	}
+}
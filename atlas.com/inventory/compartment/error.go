@@ -0,0 +1,64 @@
+package compartment
+
+import (
+	"atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/lock"
+	"errors"
+	"fmt"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"gorm.io/gorm"
+)
+
+// Error classifies why a command against a compartment failed, identifying the slot involved where there is one.
+type Error struct {
+	Reason        string
+	InventoryType inventory.Type
+	Slot          int16
+	cause         error
+}
+
+func (e Error) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("inventory [%d] slot [%d]: %s: %s", e.InventoryType, e.Slot, e.Reason, e.cause.Error())
+	}
+	return fmt.Sprintf("inventory [%d] slot [%d]: %s", e.InventoryType, e.Slot, e.Reason)
+}
+
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
+func newError(reason string, inventoryType inventory.Type, slot int16) error {
+	return Error{Reason: reason, InventoryType: inventoryType, Slot: slot}
+}
+
+// notFound classifies a missing record as reason, passing any other error through unchanged.
+func notFound(err error, reason string, inventoryType inventory.Type, slot int16) error {
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return Error{Reason: reason, InventoryType: inventoryType, Slot: slot, cause: err}
+}
+
+// duplicate classifies a unique constraint violation as reason, passing any other error through unchanged. The
+// connection must translate driver errors for the violation to be recognised.
+func duplicate(err error, reason string, inventoryType inventory.Type, slot int16) error {
+	if !errors.Is(err, gorm.ErrDuplicatedKey) {
+		return err
+	}
+	return Error{Reason: reason, InventoryType: inventoryType, Slot: slot, cause: err}
+}
+
+// Classify describes why err caused a command to fail. Errors outside the taxonomy are reported as unknown.
+func Classify(err error) Error {
+	var e Error
+	if errors.As(err, &e) {
+		return e
+	}
+	var te lock.TimeoutError
+	if errors.As(err, &te) {
+		return Error{Reason: compartment.ErrorReasonLockTimeout, InventoryType: te.Key.InventoryType, cause: err}
+	}
+	return Error{Reason: compartment.ErrorReasonUnknown, cause: err}
+}
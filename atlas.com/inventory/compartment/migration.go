@@ -0,0 +1,84 @@
+package compartment
+
+import (
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+// entityV1 is the compartments table as the service created it before migrations were versioned.
+type entityV1 struct {
+	TenantId      uuid.UUID      `gorm:"not null"`
+	Id            uuid.UUID      `gorm:"primaryKey;type:uuid;"`
+	CharacterId   uint32         `gorm:"not null"`
+	InventoryType inventory.Type `gorm:"not null"`
+	Capacity      uint32         `gorm:"capacity"`
+}
+
+func (e entityV1) TableName() string {
+	return "compartments"
+}
+
+// CreateTable creates the compartments table, or leaves it as it is where it predates versioned migrations.
+func CreateTable(db *gorm.DB) error {
+	return db.AutoMigrate(&entityV1{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("compartments")
+}
+
+// AddUniqueIndex lets a character hold a single compartment of each type. Compartments created twice before the index
+// existed are merged first.
+func AddUniqueIndex(db *gorm.DB) error {
+	err := mergeDuplicates(db)
+	if err != nil {
+		return err
+	}
+	return db.Exec("CREATE UNIQUE INDEX idx_compartments_tenant_character_type ON compartments (tenant_id, character_id, inventory_type)").Error
+}
+
+// mergeDuplicates keeps the largest of the compartments a character holds of one type, moving the assets and
+// stackables of the others into it before deleting them. Slots the moved assets share with those already there are
+// resolved by the asset index migration which follows.
+func mergeDuplicates(db *gorm.DB) error {
+	var groups []entityV1
+	err := db.Model(&entityV1{}).
+		Select("tenant_id, character_id, inventory_type").
+		Group("tenant_id, character_id, inventory_type").
+		Having("COUNT(*) > 1").
+		Scan(&groups).Error
+	if err != nil {
+		return err
+	}
+	for _, g := range groups {
+		var es []entityV1
+		err = db.Where("tenant_id = ? AND character_id = ? AND inventory_type = ?", g.TenantId, g.CharacterId, g.InventoryType).
+			Order("capacity desc, id").
+			Find(&es).Error
+		if err != nil {
+			return err
+		}
+		kept := es[0]
+		for _, e := range es[1:] {
+			for _, table := range []string{"assets", "stackables"} {
+				err = db.Table(table).Where("compartment_id = ?", e.Id).Update("compartment_id", kept.Id).Error
+				if err != nil {
+					return err
+				}
+			}
+			err = db.Where("id = ?", e.Id).Delete(&entityV1{}).Error
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func DropUniqueIndex(db *gorm.DB) error {
+	return db.Exec("DROP INDEX idx_compartments_tenant_character_type").Error
+}
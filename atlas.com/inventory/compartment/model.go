@@ -116,3 +116,25 @@ func (b *ModelBuilder) Build() Model {
 		assets:        b.assets,
 	}
 }
+
+type ReservationRequest struct {
+	InventoryType inventory.Type
+	Slot          int16
+	ItemId        uint32
+	Quantity      int16
+}
+
+// LoadoutItem is an asset applying a loadout moved, and the slot it moved to. For a skipped slot, it is the asset the
+// loadout names and the slot it was meant for.
+type LoadoutItem struct {
+	AssetId    uint32
+	TemplateId uint32
+	Slot       int16
+}
+
+// LoadoutResult summarises what applying a loadout put on, took off, and skipped for want of the asset.
+type LoadoutResult struct {
+	Equipped   []LoadoutItem
+	Unequipped []LoadoutItem
+	Skipped    []LoadoutItem
+}
@@ -2,80 +2,158 @@ package compartment
 
 import (
 	"atlas-inventory/asset"
+	"atlas-inventory/character"
+	"atlas-inventory/configuration"
 	"atlas-inventory/data/equipment"
+	"atlas-inventory/data/set"
 	"atlas-inventory/database"
 	"atlas-inventory/drop"
 	"atlas-inventory/kafka/message"
 	"atlas-inventory/kafka/message/compartment"
 	"atlas-inventory/kafka/producer"
+	"atlas-inventory/ledger"
+	"atlas-inventory/loadout"
+	"atlas-inventory/lock"
+	"atlas-inventory/outbox"
+	"atlas-inventory/reservation"
+	"atlas-inventory/statistics"
+	"atlas-inventory/warning"
 	"context"
 	"errors"
 	"github.com/Chronicle20/atlas-constants/inventory"
-	"github.com/Chronicle20/atlas-constants/inventory/slot"
 	"github.com/Chronicle20/atlas-constants/item"
 	_map "github.com/Chronicle20/atlas-constants/map"
 	"github.com/google/uuid"
 	"math"
+	"slices"
 	"sort"
 	"time"
 
 	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type Processor struct {
-	l                  logrus.FieldLogger
-	ctx                context.Context
-	db                 *gorm.DB
-	t                  tenant.Model
-	assetProcessor     *asset.Processor
-	dropProcessor      *drop.Processor
-	equipmentProcessor *equipment.Processor
-	producer           producer.Provider
+	l                    logrus.FieldLogger
+	ctx                  context.Context
+	db                   *gorm.DB
+	t                    tenant.Model
+	assetProcessor       *asset.Processor
+	dropProcessor        *drop.Processor
+	equipmentProcessor   *equipment.Processor
+	characterProcessor   *character.Processor
+	setProcessor         *set.Processor
+	statisticsProcessor  *statistics.Processor
+	loadoutProcessor     *loadout.Processor
+	reservationProcessor *reservation.Processor
+	lockProvider         lock.Provider
+	outboxProcessor      *outbox.Processor
+	ledgerProcessor      *ledger.Processor
+	warningProcessor     *warning.Processor
+	producer             producer.Provider
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	op := outbox.NewProcessor(l, ctx, db)
 	p := &Processor{
-		l:                  l,
-		ctx:                ctx,
-		db:                 db,
-		t:                  tenant.MustFromContext(ctx),
-		assetProcessor:     asset.NewProcessor(l, ctx, db),
-		dropProcessor:      drop.NewProcessor(l, ctx),
-		equipmentProcessor: equipment.NewProcessor(l, ctx),
-		producer:           producer.ProviderImpl(l)(ctx),
+		l:                    l,
+		ctx:                  ctx,
+		db:                   db,
+		t:                    tenant.MustFromContext(ctx),
+		assetProcessor:       asset.NewProcessor(l, ctx, db),
+		dropProcessor:        drop.NewProcessor(l, ctx),
+		equipmentProcessor:   equipment.NewProcessor(l, ctx),
+		characterProcessor:   character.NewProcessor(l, ctx),
+		setProcessor:         set.NewProcessor(l, ctx),
+		statisticsProcessor:  statistics.NewProcessor(l, ctx, db),
+		loadoutProcessor:     loadout.NewProcessor(l, ctx, db),
+		reservationProcessor: reservation.NewProcessor(l, ctx, db),
+		lockProvider:         lock.GetProvider(),
+		outboxProcessor:      op,
+		ledgerProcessor:      ledger.NewProcessor(l, ctx, db),
+		warningProcessor:     warning.NewProcessor(l, ctx, db),
+		producer:             op.Producer(),
 	}
 	return p
 }
 
 func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
 	return &Processor{
-		l:                  p.l,
-		ctx:                p.ctx,
-		db:                 db,
-		t:                  p.t,
-		assetProcessor:     p.assetProcessor,
-		dropProcessor:      p.dropProcessor,
-		equipmentProcessor: p.equipmentProcessor,
-		producer:           p.producer,
+		l:                    p.l,
+		ctx:                  p.ctx,
+		db:                   db,
+		t:                    p.t,
+		assetProcessor:       p.assetProcessor,
+		dropProcessor:        p.dropProcessor,
+		equipmentProcessor:   p.equipmentProcessor,
+		characterProcessor:   p.characterProcessor,
+		setProcessor:         p.setProcessor,
+		statisticsProcessor:  p.statisticsProcessor,
+		loadoutProcessor:     p.loadoutProcessor,
+		reservationProcessor: p.reservationProcessor,
+		lockProvider:         p.lockProvider,
+		outboxProcessor:      p.outboxProcessor,
+		ledgerProcessor:      p.ledgerProcessor,
+		warningProcessor:     p.warningProcessor,
+		producer:             p.producer,
 	}
 }
 
 func (p *Processor) WithAssetProcessor(ap *asset.Processor) *Processor {
 	return &Processor{
-		l:                  p.l,
-		ctx:                p.ctx,
-		db:                 p.db,
-		t:                  p.t,
-		assetProcessor:     ap,
-		dropProcessor:      p.dropProcessor,
-		equipmentProcessor: p.equipmentProcessor,
-		producer:           p.producer,
+		l:                    p.l,
+		ctx:                  p.ctx,
+		db:                   p.db,
+		t:                    p.t,
+		assetProcessor:       ap,
+		dropProcessor:        p.dropProcessor,
+		equipmentProcessor:   p.equipmentProcessor,
+		characterProcessor:   p.characterProcessor,
+		setProcessor:         p.setProcessor,
+		statisticsProcessor:  p.statisticsProcessor,
+		loadoutProcessor:     p.loadoutProcessor,
+		reservationProcessor: p.reservationProcessor,
+		lockProvider:         p.lockProvider,
+		outboxProcessor:      p.outboxProcessor,
+		ledgerProcessor:      p.ledgerProcessor,
+		warningProcessor:     p.warningProcessor,
+		producer:             p.producer,
 	}
 }
 
+// lockKeys identifies the character's compartments of the given types for the lock provider.
+func (p *Processor) lockKeys(characterId uint32, inventoryTypes ...inventory.Type) lock.Set {
+	keys := make([]lock.Key, 0, len(inventoryTypes))
+	for _, inventoryType := range inventoryTypes {
+		keys = append(keys, lock.CompartmentKey(p.t.Id(), characterId, inventoryType))
+	}
+	return lock.NewSet(keys...)
+}
+
+// transaction runs f with the compartments locked, staging what f buffered in the outbox before it commits. When
+// running a command which was already applied, f is skipped, the events of the original execution are re-emitted
+// and ledger.ErrDuplicate is returned.
+func (p *Processor) transaction(mb *message.Buffer, keys lock.Set, f func(tx *gorm.DB) error) error {
+	err := p.lockProvider.Transaction(p.ctx, p.db, keys, p.outboxProcessor.Staged(mb, p.ledgerProcessor.Claimed(f)))
+	if !errors.Is(err, ledger.ErrDuplicate) {
+		return err
+	}
+	c, _ := ledger.FromContext(p.ctx)
+	p.l.Debugf("Command [%s] of transaction [%s] was already processed. Re-emitting its events.", c.Type, c.TransactionId.String())
+	original, lerr := p.ledgerProcessor.ProcessedProvider(c)()
+	if lerr != nil {
+		return lerr
+	}
+	lerr = p.outboxProcessor.Reemit(original.ExecutionId())
+	if lerr != nil {
+		return lerr
+	}
+	return err
+}
+
 func (p *Processor) ByIdProvider(id uuid.UUID) model.Provider[Model] {
 	cs, err := model.Map(Make)(getById(p.t.Id(), id)(p.db))()
 	if err != nil {
@@ -112,13 +190,21 @@ func (p *Processor) ByCharacterAndTypeProvider(characterId uint32) func(inventor
 
 func (p *Processor) GetByCharacterAndType(characterId uint32) func(inventoryType inventory.Type) (Model, error) {
 	return func(inventoryType inventory.Type) (Model, error) {
-		return p.ByCharacterAndTypeProvider(characterId)(inventoryType)()
+		c, err := p.ByCharacterAndTypeProvider(characterId)(inventoryType)()
+		if err != nil {
+			return Model{}, notFound(err, compartment.ErrorReasonCompartmentNotFound, inventoryType, 0)
+		}
+		return c, nil
 	}
 
 }
 
 func (p *Processor) DecorateAsset(m Model) (Model, error) {
-	as, err := p.assetProcessor.GetByCompartmentId(m.Id())
+	as, err := p.assetProcessor.WithTransaction(p.db).GetByCompartmentId(m.Id())
+	if err != nil {
+		return Model{}, err
+	}
+	as, err = p.assetProcessor.WithTransaction(p.db).DecorateReserved(m.CharacterId(), m.Type())(as)
 	if err != nil {
 		return Model{}, err
 	}
@@ -129,14 +215,14 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, capacity uint32) (Model, error) {
 		p.l.Debugf("Attempting to create compartment of type [%d] for character [%d] with capacity [%d].", inventoryType, characterId, capacity)
 		var c Model
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			var err error
 			c, err = create(tx, p.t.Id(), characterId, inventoryType, capacity)
 			if err != nil {
-				return err
+				return duplicate(err, compartment.ErrorReasonCompartmentExists, inventoryType, 0)
 			}
 			return mb.Put(compartment.EnvEventTopicStatus, CreatedEventStatusProvider(transactionId, c.Id(), characterId, c.Type(), c.Capacity()))
-		})
+		}))
 		if txErr != nil {
 			return Model{}, txErr
 		}
@@ -148,7 +234,7 @@ func (p *Processor) Create(mb *message.Buffer) func(transactionId uuid.UUID, cha
 func (p *Processor) DeleteByModel(mb *message.Buffer) func(transactionId uuid.UUID, c Model) error {
 	return func(transactionId uuid.UUID, c Model) error {
 		p.l.Debugf("Attempting to delete compartment [%s].", c.Id().String())
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			err := model.ForEachSlice(model.FixedProvider(c.Assets()), p.assetProcessor.WithTransaction(tx).Delete(mb)(transactionId, c.CharacterId(), c.Id()))
 			if err != nil {
 				return err
@@ -158,7 +244,7 @@ func (p *Processor) DeleteByModel(mb *message.Buffer) func(transactionId uuid.UU
 				return err
 			}
 			return mb.Put(compartment.EnvEventTopicStatus, DeletedEventStatusProvider(transactionId, c.Id(), c.CharacterId()))
-		})
+		}))
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Unable to delete compartment [%s].", c.Id().String())
 			return txErr
@@ -181,13 +267,13 @@ func (p *Processor) EquipItemAndEmit(transactionId uuid.UUID, characterId uint32
 func (p *Processor) EquipItem(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, source int16, destination int16) error {
 	return func(transactionId uuid.UUID, characterId uint32, source int16, destination int16) error {
 		p.l.Debugf("Attempting to equip item in slot [%d] to [%d] for character [%d].", source, destination, characterId)
-		invLock := LockRegistry().Get(characterId, inventory.TypeValueEquip)
-		invLock.Lock()
-		defer invLock.Unlock()
-
+		ec, err := p.checkEquip(characterId, source, destination)
+		if err != nil {
+			return err
+		}
 		var a1 asset.Model[any]
-		var actualDestination int16
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		actualDestination := ec.destination
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventory.TypeValueEquip), func(tx *gorm.DB) error {
 			var c Model
 			var err error
 			c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
@@ -200,90 +286,218 @@ func (p *Processor) EquipItem(mb *message.Buffer) func(transactionId uuid.UUID,
 			a1, err = assetProvider(source)()
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get asset in compartment [%d] by slot [%d].", c.Id(), source)
-				return err
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventory.TypeValueEquip, source)
+			}
+			if a1.TemplateId() != ec.templateId {
+				p.l.Errorf("Item [%d] in slot [%d] is not item [%d] checked for equipping.", a1.TemplateId(), source, ec.templateId)
+				return newError(compartment.ErrorReasonTemplateMismatch, inventory.TypeValueEquip, source)
 			}
 			p.l.Debugf("Character [%d] is attempting to equip item [%d].", characterId, a1.TemplateId())
-			actualDestination, err = p.equipmentProcessor.DestinationSlotProvider(destination)(a1.TemplateId())()
+			var unequip []asset.Model[any]
+			unequip, err = p.slotConflicts(c, a1, actualDestination)
 			if err != nil {
-				p.l.WithError(err).Errorf("Unable to determine actual destination for item being equipped.")
 				return err
 			}
+			moves := map[uint32]int16{a1.Id(): actualDestination}
+			// The item leaves its slot, which stays occupied only if something is swapped into it.
+			free := newFreeSlots(c)
+			if i := slices.IndexFunc(c.Assets(), func(a asset.Model[any]) bool { return a.Slot() == actualDestination }); i >= 0 {
+				moves[c.Assets()[i].Id()] = source
+			} else {
+				free.release(source)
+			}
 			p.l.Debugf("Character [%d] moving asset from [%d] to [%d] if present.", characterId, actualDestination, temporarySlot())
 			err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(actualDestination), model.FixedProvider(temporarySlot()))
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", actualDestination, temporarySlot(), characterId, c.Id())
-				return err
+				return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, temporarySlot())
 			}
 			p.l.Debugf("Character [%d] moving asset from source [%d] to destination [%d].", characterId, a1.Slot(), actualDestination)
 			err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(a1), model.FixedProvider(actualDestination))
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", a1.Slot(), actualDestination, characterId, c.Id())
-				return err
+				return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, actualDestination)
 			}
 			p.l.Debugf("Character [%d] moving asset from [%d] to [%d] if present.", characterId, temporarySlot(), source)
 			err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(temporarySlot()), model.FixedProvider(source))
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", temporarySlot(), source, characterId, c.Id())
-				return err
+				return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, source)
 			}
 
-			p.l.Debugf("Now verifying other inventory operations that may be necessary.")
-
-			if item.GetClassification(item.Id(a1.TemplateId())) == item.ClassificationOverall {
-				var ps slot.Slot
-				ps, err = slot.GetSlotByType("pants")
-				if err != nil {
-					p.l.WithError(err).Errorf("Unable to get slot by type [pants].")
-					return err
-				}
+			for _, ua := range unequip {
 				var nfs int16
-				nfs, err = c.NextFreeSlot()
-				if err != nil {
-					p.l.WithError(err).Errorf("No free slots for pants.")
-					return err
-				}
-				err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(int16(ps.Position)), model.FixedProvider(nfs))
+				nfs, err = free.next()
 				if err != nil {
-					p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", ps.Position, nfs, characterId, c.Id())
-					return err
+					p.l.WithError(err).Errorf("No free slot to unequip item [%d] in slot [%d] into.", ua.TemplateId(), ua.Slot())
+					return newError(compartment.ErrorReasonNoFreeSlotToUnequip, inventory.TypeValueEquip, ua.Slot())
 				}
-			}
-
-			if item.GetClassification(item.Id(a1.TemplateId())) == item.Classification(106) {
-				var ts slot.Slot
-				ts, err = slot.GetSlotByType("top")
+				p.l.Debugf("Character [%d] unequipping conflicting asset from [%d] to [%d].", characterId, ua.Slot(), nfs)
+				err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(ua), model.FixedProvider(nfs))
 				if err != nil {
-					p.l.WithError(err).Errorf("Unable to get slot by type [top].")
-					return err
-				}
-				var ta asset.Model[any]
-				ta, err = assetProvider(int16(ts.Position))()
-				if err == nil {
-					if item.GetClassification(item.Id(ta.TemplateId())) == item.ClassificationOverall {
-						var nfs int16
-						nfs, err = c.NextFreeSlot()
-						if err != nil {
-							p.l.WithError(err).Errorf("No free slots for top.")
-							return err
-						}
-						err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(ta), model.FixedProvider(nfs))
-						if err != nil {
-							p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", ts.Position, nfs, characterId, c.Id())
-							return err
-						}
-					}
+					p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", ua.Slot(), nfs, characterId, c.Id())
+					return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, nfs)
 				}
+				moves[ua.Id()] = nfs
 			}
-			return nil
+			return p.announceStatistics(mb, tx, transactionId, c, moves)
 		})
 		if txErr != nil {
-			p.l.Debugf("Unable to equip item in slot [%d] to [%d] for character [%d].", source, actualDestination, characterId)
+			p.l.WithError(txErr).Errorf("Unable to equip item in slot [%d] to [%d] for character [%d].", source, actualDestination, characterId)
+			return txErr
 		}
 		p.l.Debugf("Character [%d] equipped item [%d] in slot [%d].", characterId, a1.TemplateId(), actualDestination)
 		return nil
 	}
 }
 
+// EquipmentStatistics totals the statistics granted by the equipment a character wears, slot by slot. With setBonuses,
+// the bonuses of sets worn are included in the totals.
+func (p *Processor) EquipmentStatistics(characterId uint32, setBonuses bool) (statistics.Model, error) {
+	c, err := p.GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
+	if err != nil {
+		return statistics.Model{}, err
+	}
+	m := statistics.Sum(characterId, c.Assets())
+	if !setBonuses {
+		return m, nil
+	}
+	ss, err := p.setProcessor.GetAll()
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to retrieve set data for character [%d].", characterId)
+		return statistics.Model{}, err
+	}
+	return m.WithSetBonuses(ss), nil
+}
+
+func (p *Processor) RefreshEquipmentStatisticsAndEmit(transactionId uuid.UUID, characterId uint32) error {
+	return message.Emit(p.producer)(func(mb *message.Buffer) error {
+		return p.RefreshEquipmentStatistics(mb)(transactionId, characterId)
+	})
+}
+
+// RefreshEquipmentStatistics announces the statistics granted by a character's equipment if they changed since last
+// announced, as when the attributes of equipment worn are updated.
+func (p *Processor) RefreshEquipmentStatistics(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32) error {
+	return func(transactionId uuid.UUID, characterId uint32) error {
+		return p.transaction(mb, p.lockKeys(characterId, inventory.TypeValueEquip), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventory.TypeValueEquip, characterId)
+				return err
+			}
+			return p.announceStatistics(mb, tx, transactionId, c, nil)
+		})
+	}
+}
+
+// announceStatistics stages EQUIPMENT_STATS_CHANGED when the equipment in c, once the assets in moves are in their new
+// slots, grants different statistics than last announced.
+func (p *Processor) announceStatistics(mb *message.Buffer, tx *gorm.DB, transactionId uuid.UUID, c Model, moves map[uint32]int16) error {
+	as := make([]asset.Model[any], 0, len(c.Assets()))
+	for _, a := range c.Assets() {
+		if s, ok := moves[a.Id()]; ok {
+			a = asset.Clone(a).SetSlot(s).Build()
+		}
+		as = append(as, a)
+	}
+	t := statistics.Sum(c.CharacterId(), as).Totals()
+	changed, err := p.statisticsProcessor.WithTransaction(tx).Record(c.CharacterId(), t)
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to record equipment statistics for character [%d].", c.CharacterId())
+		return err
+	}
+	if !changed {
+		return nil
+	}
+	return mb.Put(compartment.EnvEventTopicStatus, EquipmentStatsChangedEventStatusProvider(transactionId, c.Id(), c.CharacterId(), t))
+}
+
+// slotConflicts evaluates the tenant's slot conflict rules for equipping a into destination, returning the worn assets
+// to unequip. An equip refused by a rule fails with SLOT_CONFLICT.
+func (p *Processor) slotConflicts(c Model, a asset.Model[any], destination int16) ([]asset.Model[any], error) {
+	rules := configuration.GetTenantConfig(p.t.Id()).Equip.SlotConflicts
+	unequip, rejectedBy := SlotConflicts(rules, a.TemplateId(), destination, c.Assets())
+	if rejectedBy != nil {
+		p.l.Infof("Equipping item [%d] into [%d] is rejected by slot conflict rule [%s].", a.TemplateId(), destination, rejectedBy.Name)
+		return nil, newError(compartment.ErrorReasonSlotConflict, inventory.TypeValueEquip, destination)
+	}
+	return unequip, nil
+}
+
+// equipCheck is an item checked for equipping before the compartment is locked.
+type equipCheck struct {
+	templateId  uint32
+	destination int16
+}
+
+// checkEquip resolves where the item in source is equipped and rejects it if the character does not meet its
+// requirements. The item and character data come from other services, so they are retrieved before the compartment is
+// locked; the command confirms under the lock that source still holds the item checked. An item which cannot be found
+// is left to the command to report, so a redelivered command is still recognised as such.
+func (p *Processor) checkEquip(characterId uint32, source int16, destination int16) (equipCheck, error) {
+	c, err := model.Map(Make)(getByCharacterAndType(p.t.Id(), characterId, inventory.TypeValueEquip)(p.db))()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return equipCheck{}, nil
+	}
+	if err != nil {
+		return equipCheck{}, err
+	}
+	a, err := p.assetProcessor.GetBySlot(c.Id(), source)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return equipCheck{}, nil
+	}
+	if err != nil {
+		return equipCheck{}, err
+	}
+	d, err := p.equipmentProcessor.DestinationSlotProvider(destination)(a.TemplateId())()
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to determine actual destination for item being equipped.")
+		return equipCheck{}, Error{Reason: compartment.ErrorReasonNotEquippable, InventoryType: inventory.TypeValueEquip, Slot: source, cause: err}
+	}
+	ch, err := p.getCharacter(characterId)
+	if err != nil {
+		return equipCheck{}, err
+	}
+	err = p.meetsRequirements(ch, a.TemplateId(), source)
+	if err != nil {
+		return equipCheck{}, err
+	}
+	return equipCheck{templateId: a.TemplateId(), destination: d}, nil
+}
+
+// getCharacter retrieves a character to check equip requirements against. A character the character service does not
+// know fails with CHARACTER_NOT_FOUND.
+func (p *Processor) getCharacter(characterId uint32) (character.Model, error) {
+	c, err := p.characterProcessor.GetById(characterId)
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to retrieve character [%d] to check equip requirements.", characterId)
+		if errors.Is(err, requests.ErrNotFound) {
+			return character.Model{}, Error{Reason: compartment.ErrorReasonCharacterNotFound, InventoryType: inventory.TypeValueEquip, cause: err}
+		}
+		return character.Model{}, err
+	}
+	return c, nil
+}
+
+// meetsRequirements rejects equipping an item whose level, job or stat requirements the character does not meet. GM
+// characters are not checked where the tenant is configured to skip them.
+func (p *Processor) meetsRequirements(c character.Model, templateId uint32, source int16) error {
+	if c.Gm() && configuration.GetTenantConfig(p.t.Id()).Equip.SkipsRequirementsForGm() {
+		p.l.Debugf("Skipping equip requirements of item [%d] for GM character [%d].", templateId, c.Id())
+		return nil
+	}
+	unmet, err := p.equipmentProcessor.UnmetRequirements(templateId, c)
+	if err != nil {
+		return err
+	}
+	if len(unmet) > 0 {
+		p.l.Infof("Character [%d] does not meet the [%v] requirements of item [%d].", c.Id(), unmet, templateId)
+		return newError(compartment.ErrorReasonRequirementsNotMet, inventory.TypeValueEquip, source)
+	}
+	return nil
+}
+
 func (p *Processor) RemoveEquipAndEmit(transactionId uuid.UUID, characterId uint32, source int16, destination int16) error {
 	return message.Emit(p.producer)(func(mb *message.Buffer) error {
 		return p.RemoveEquip(mb)(transactionId, characterId, source, destination)
@@ -293,11 +507,7 @@ func (p *Processor) RemoveEquipAndEmit(transactionId uuid.UUID, characterId uint
 func (p *Processor) RemoveEquip(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, source int16, destination int16) error {
 	return func(transactionId uuid.UUID, characterId uint32, source int16, destination int16) error {
 		p.l.Debugf("Attempting to remove equipment in slot [%d] to [%d] for character [%d].", source, destination, characterId)
-		invLock := LockRegistry().Get(characterId, inventory.TypeValueEquip)
-		invLock.Lock()
-		defer invLock.Unlock()
-
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventory.TypeValueEquip), func(tx *gorm.DB) error {
 			var c Model
 			var err error
 			c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
@@ -308,6 +518,11 @@ func (p *Processor) RemoveEquip(mb *message.Buffer) func(transactionId uuid.UUID
 
 			var fsp model.Provider[int16]
 			assetProvider := p.assetProcessor.WithTransaction(tx).BySlotProvider(c.Id())
+			var a asset.Model[any]
+			a, err = assetProvider(source)()
+			if err != nil {
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventory.TypeValueEquip, source)
+			}
 			if destination > 0 && uint32(destination) < c.Capacity() {
 				_, err = assetProvider(destination)()
 				if err != nil && errors.Is(err, gorm.ErrRecordNotFound) {
@@ -320,17 +535,17 @@ func (p *Processor) RemoveEquip(mb *message.Buffer) func(transactionId uuid.UUID
 				nfs, err = c.NextFreeSlot()
 				if err != nil {
 					p.l.WithError(err).Errorf("No free slots exist for equip. Cannot remove equipment.")
-					return err
+					return newError(compartment.ErrorReasonInventoryFull, inventory.TypeValueEquip, source)
 				}
 				fsp = model.FixedProvider(nfs)
 			}
 			err = p.assetProcessor.WithTransaction(tx).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(source), fsp)
+			ds, _ := fsp()
 			if err != nil {
-				ds, _ := fsp()
 				p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", source, ds, characterId, c.Id())
-				return err
+				return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, ds)
 			}
-			return nil
+			return p.announceStatistics(mb, tx, transactionId, c, map[uint32]int16{a.Id(): ds})
 		})
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Unable to remove equipment in slot [%d] for character [%d].", source, characterId)
@@ -340,6 +555,165 @@ func (p *Processor) RemoveEquip(mb *message.Buffer) func(transactionId uuid.UUID
 	}
 }
 
+func (p *Processor) ApplyLoadoutAndEmit(transactionId uuid.UUID, characterId uint32, loadoutId uuid.UUID, skipMissing bool) error {
+	return message.Emit(p.producer)(func(mb *message.Buffer) error {
+		return p.ApplyLoadout(mb)(transactionId, characterId, loadoutId, skipMissing)
+	})
+}
+
+// ApplyLoadout puts on a character's saved loadout in one transaction, so either every item is equipped or none are.
+// Each item is checked as EQUIP checks it. Worn equipment in the slots the loadout fills, or displaced by a slot
+// conflict rule, returns to the inventory. A slot whose asset the character no longer has fails the command with
+// ASSET_NOT_FOUND, unless skipMissing is set. A single LOADOUT_APPLIED event summarises the result. The character is
+// read before the compartment is locked, as EQUIP reads it.
+func (p *Processor) ApplyLoadout(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, loadoutId uuid.UUID, skipMissing bool) error {
+	return func(transactionId uuid.UUID, characterId uint32, loadoutId uuid.UUID, skipMissing bool) error {
+		p.l.Debugf("Attempting to apply loadout [%s] for character [%d].", loadoutId, characterId)
+		ch, err := p.getCharacter(characterId)
+		if err != nil {
+			return err
+		}
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventory.TypeValueEquip), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventory.TypeValueEquip)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventory.TypeValueEquip, characterId)
+				return err
+			}
+			var lo loadout.Model
+			lo, err = p.loadoutProcessor.WithTransaction(tx).GetById(characterId, loadoutId)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to retrieve loadout [%s] for character [%d].", loadoutId, characterId)
+				return notFound(err, compartment.ErrorReasonLoadoutNotFound, inventory.TypeValueEquip, 0)
+			}
+
+			var r LoadoutResult
+			targets := make(map[uint32]int16)
+			var wearing []asset.Model[any]
+			for _, s := range lo.Slots() {
+				i := slices.IndexFunc(c.Assets(), func(a asset.Model[any]) bool { return a.Id() == s.AssetId() })
+				if i < 0 {
+					if !skipMissing {
+						p.l.Errorf("Asset [%d] of loadout [%s] is not in the equipment compartment of character [%d].", s.AssetId(), loadoutId, characterId)
+						return newError(compartment.ErrorReasonAssetNotFound, inventory.TypeValueEquip, s.Slot())
+					}
+					p.l.Debugf("Skipping slot [%d] of loadout [%s], as asset [%d] is missing.", s.Slot(), loadoutId, s.AssetId())
+					r.Skipped = append(r.Skipped, LoadoutItem{AssetId: s.AssetId(), Slot: s.Slot()})
+					continue
+				}
+				a := c.Assets()[i]
+				targets[a.Id()] = s.Slot()
+				if a.Slot() == s.Slot() {
+					continue
+				}
+				var destination int16
+				destination, err = p.equipmentProcessor.DestinationSlotProvider(s.Slot())(a.TemplateId())()
+				if err != nil {
+					p.l.WithError(err).Errorf("Unable to determine actual destination for item [%d] of loadout [%s].", a.TemplateId(), loadoutId)
+					return Error{Reason: compartment.ErrorReasonNotEquippable, InventoryType: inventory.TypeValueEquip, Slot: s.Slot(), cause: err}
+				}
+				if destination != s.Slot() {
+					p.l.Errorf("Item [%d] of loadout [%s] is equipped in slot [%d], not [%d].", a.TemplateId(), loadoutId, destination, s.Slot())
+					return newError(compartment.ErrorReasonNotEquippable, inventory.TypeValueEquip, s.Slot())
+				}
+				err = p.meetsRequirements(ch, a.TemplateId(), s.Slot())
+				if err != nil {
+					return err
+				}
+				wearing = append(wearing, a)
+			}
+			var unequip []asset.Model[any]
+			unequip, err = LoadoutDisplaces(configuration.GetTenantConfig(p.t.Id()).Equip.SlotConflicts, c.Assets(), targets)
+			if err != nil {
+				p.l.WithError(err).Infof("Loadout [%s] of character [%d] is rejected by the slot conflict rules.", loadoutId, characterId)
+				return err
+			}
+
+			// Each item swaps with whatever holds its slot, as EQUIP does. moves tracks where assets end up.
+			ap := p.assetProcessor.WithTransaction(tx)
+			bySlot := make(map[int16]asset.Model[any])
+			for _, a := range c.Assets() {
+				bySlot[a.Slot()] = a
+			}
+			free := newFreeSlots(c)
+			moves := make(map[uint32]int16)
+			for _, a := range wearing {
+				target := targets[a.Id()]
+				source := a.Slot()
+				if s, ok := moves[a.Id()]; ok {
+					source = s
+				}
+				a = asset.Clone(a).SetSlot(source).Build()
+				o, occupied := bySlot[target]
+				if occupied {
+					err = ap.UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(o), model.FixedProvider(temporarySlot()))
+					if err != nil {
+						p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", target, temporarySlot(), characterId, c.Id())
+						return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, temporarySlot())
+					}
+				}
+				p.l.Debugf("Character [%d] moving asset [%d] of loadout [%s] from [%d] to [%d].", characterId, a.Id(), loadoutId, source, target)
+				err = ap.UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(a), model.FixedProvider(target))
+				if err != nil {
+					p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", source, target, characterId, c.Id())
+					return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, target)
+				}
+				moves[a.Id()] = target
+				bySlot[target] = asset.Clone(a).SetSlot(target).Build()
+				r.Equipped = append(r.Equipped, LoadoutItem{AssetId: a.Id(), TemplateId: a.TemplateId(), Slot: target})
+				if !occupied {
+					delete(bySlot, source)
+					free.release(source)
+					continue
+				}
+				err = ap.Reslot(mb)(transactionId, characterId, c.Id())(o, source)
+				if err != nil {
+					p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", temporarySlot(), source, characterId, c.Id())
+					return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, source)
+				}
+				moves[o.Id()] = source
+				bySlot[source] = asset.Clone(o).SetSlot(source).Build()
+			}
+
+			// Displaced equipment swapped into the inventory is already off. The rest goes to the lowest free slots.
+			for _, u := range unequip {
+				slot := u.Slot()
+				if s, ok := moves[u.Id()]; ok {
+					slot = s
+				}
+				if slot < 0 {
+					var nfs int16
+					nfs, err = free.next()
+					if err != nil {
+						p.l.WithError(err).Errorf("No free slot to unequip item [%d] in slot [%d] into.", u.TemplateId(), slot)
+						return newError(compartment.ErrorReasonNoFreeSlotToUnequip, inventory.TypeValueEquip, slot)
+					}
+					p.l.Debugf("Character [%d] unequipping displaced asset from [%d] to [%d].", characterId, slot, nfs)
+					err = ap.UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(asset.Clone(u).SetSlot(slot).Build()), model.FixedProvider(nfs))
+					if err != nil {
+						p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", slot, nfs, characterId, c.Id())
+						return duplicate(err, compartment.ErrorReasonSlotOccupied, inventory.TypeValueEquip, nfs)
+					}
+					moves[u.Id()] = nfs
+					slot = nfs
+				}
+				r.Unequipped = append(r.Unequipped, LoadoutItem{AssetId: u.Id(), TemplateId: u.TemplateId(), Slot: slot})
+			}
+
+			err = p.announceStatistics(mb, tx, transactionId, c, moves)
+			if err != nil {
+				return err
+			}
+			return mb.Put(compartment.EnvEventTopicStatus, LoadoutAppliedEventStatusProvider(transactionId, c.Id(), characterId, lo.Id(), lo.Name(), r))
+		})
+		if txErr != nil {
+			p.l.WithError(txErr).Errorf("Unable to apply loadout [%s] for character [%d].", loadoutId, characterId)
+			return txErr
+		}
+		p.l.Debugf("Character [%d] applied loadout [%s].", characterId, loadoutId)
+		return nil
+	}
+}
+
 func (p *Processor) MoveAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, source int16, destination int16) error {
 	return message.Emit(p.producer)(func(buf *message.Buffer) error {
 		return p.MoveAndLock(buf)(transactionId, characterId, inventoryType, source, destination)
@@ -348,10 +722,9 @@ func (p *Processor) MoveAndEmit(transactionId uuid.UUID, characterId uint32, inv
 
 func (p *Processor) MoveAndLock(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, source int16, destination int16) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, source int16, destination int16) error {
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-		return p.Move(mb)(transactionId, characterId, inventoryType, source, destination)
+		return p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			return p.WithTransaction(tx).Move(mb)(transactionId, characterId, inventoryType, source, destination)
+		})
 	}
 }
 
@@ -360,7 +733,7 @@ func (p *Processor) Move(mb *message.Buffer) func(transactionId uuid.UUID, chara
 		p.l.Debugf("Attempting to move asset in slot [%d] to [%d] for character [%d].", source, destination, characterId)
 
 		var a1 asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			// Get compartment
 			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
@@ -373,7 +746,7 @@ func (p *Processor) Move(mb *message.Buffer) func(transactionId uuid.UUID, chara
 			a1, err = assetProvider(source)()
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get asset in compartment [%d] by slot [%d].", c.Id(), source)
-				return err
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventoryType, source)
 			}
 			p.l.Debugf("Character [%d] is attempting to move asset [%d].", characterId, a1.TemplateId())
 
@@ -385,13 +758,13 @@ func (p *Processor) Move(mb *message.Buffer) func(transactionId uuid.UUID, chara
 			}
 
 			// Determine if we should merge or swap
-			if err == nil && p.canMergeAssets(inventoryType, a1, a2, characterId) {
+			if err == nil && p.WithTransaction(tx).canMergeAssets(inventoryType, a1, a2, characterId) {
 				return p.WithTransaction(tx).mergeAssets(mb)(transactionId, characterId, c, a1, a2, source, destination)
 			}
 
 			// Default to swap logic
 			return p.WithTransaction(tx).swapAssets(mb)(transactionId, characterId, c, assetProvider, a1, source, destination)
-		})
+		}))
 
 		if txErr != nil {
 			p.l.Debugf("Unable to move asset in slot [%d] to [%d] for character [%d].", source, destination, characterId)
@@ -410,24 +783,28 @@ func (p *Processor) swapAssets(mb *message.Buffer) func(transactionId uuid.UUID,
 		err := p.assetProcessor.WithTransaction(p.db).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(destination), model.FixedProvider(temporarySlot()))
 		if err != nil {
 			p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", destination, temporarySlot(), characterId, c.Id())
-			return err
+			return duplicate(err, compartment.ErrorReasonSlotOccupied, c.Type(), temporarySlot())
 		}
 
 		// Move source asset to destination
 		err = p.assetProcessor.WithTransaction(p.db).UpdateSlot(mb)(transactionId, characterId, c.Id(), model.FixedProvider(a1), model.FixedProvider(destination))
 		if err != nil {
 			p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", a1.Slot(), destination, characterId, c.Id())
-			return err
+			return duplicate(err, compartment.ErrorReasonSlotOccupied, c.Type(), destination)
 		}
 
 		// Move temporary asset to source
 		err = p.assetProcessor.WithTransaction(p.db).UpdateSlot(mb)(transactionId, characterId, c.Id(), assetProvider(temporarySlot()), model.FixedProvider(source))
 		if err != nil {
 			p.l.WithError(err).Errorf("Unable to update asset slot from [%d] to [%d]. Character [%d]. Compartment [%d].", temporarySlot(), source, characterId, c.Id())
-			return err
+			return duplicate(err, compartment.ErrorReasonSlotOccupied, c.Type(), source)
 		}
 
-		GetReservationRegistry().SwapReservation(p.t, characterId, c.Type(), source, destination)
+		err = p.reservationProcessor.WithTransaction(p.db).Swap(characterId, c.Type(), source, destination)
+		if err != nil {
+			p.l.WithError(err).Errorf("Unable to swap reservations between slot [%d] and [%d]. Character [%d]. Compartment [%d].", source, destination, characterId, c.Id())
+			return err
+		}
 		return nil
 	}
 }
@@ -517,8 +894,16 @@ func (p *Processor) canMergeAssets(inventoryType inventory.Type, sourceAsset ass
 	}
 
 	// Rule 4: Neither asset can have an active reservation
-	sourceReserved := GetReservationRegistry().GetReservedQuantity(p.t, characterId, inventoryType, sourceAsset.Slot())
-	destReserved := GetReservationRegistry().GetReservedQuantity(p.t, characterId, inventoryType, destAsset.Slot())
+	sourceReserved, err := p.reservationProcessor.WithTransaction(p.db).GetReservedQuantity(characterId, inventoryType, sourceAsset.Slot())
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to get reserved quantity for slot [%d].", sourceAsset.Slot())
+		return false
+	}
+	destReserved, err := p.reservationProcessor.WithTransaction(p.db).GetReservedQuantity(characterId, inventoryType, destAsset.Slot())
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to get reserved quantity for slot [%d].", destAsset.Slot())
+		return false
+	}
 	if sourceReserved > 0 || destReserved > 0 {
 		return false
 	}
@@ -553,13 +938,9 @@ func (p *Processor) IncreaseCapacityAndEmit(transactionId uuid.UUID, characterId
 func (p *Processor) IncreaseCapacity(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, amount uint32) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, amount uint32) error {
 		p.l.Debugf("Character [%d] attempting to change compartment capacity by [%d]. Type [%d].", characterId, amount, inventoryType)
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
 		var capacity uint32
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				return err
 			}
@@ -588,32 +969,31 @@ func (p *Processor) DropAndEmit(transactionId uuid.UUID, characterId uint32, inv
 func (p *Processor) Drop(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, m _map.Model, x int16, y int16, source int16, quantity int16) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, m _map.Model, x int16, y int16, source int16, quantity int16) error {
 		p.l.Debugf("Character [%d] attempting to drop [%d] asset from slot [%d].", characterId, quantity, source)
-		if quantity < 0 {
-			return errors.New("cannot drop negative quantity")
+		if quantity <= 0 {
+			return newError(compartment.ErrorReasonInvalidQuantity, inventoryType, source)
 		}
-		if quantity == 0 {
-			return errors.New("cannot drop nothing")
-		}
-
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
 
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				return err
 			}
 			a, err = p.assetProcessor.WithTransaction(tx).GetBySlot(c.Id(), source)
+			if err != nil {
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventoryType, source)
+			}
+			reservedQty, err := p.reservationProcessor.WithTransaction(tx).GetReservedQuantity(characterId, inventoryType, source)
 			if err != nil {
 				return err
 			}
-			reservedQty := GetReservationRegistry().GetReservedQuantity(p.t, characterId, inventoryType, source)
 			initialQty := a.Quantity() - reservedQty
 
 			if initialQty < uint32(quantity) {
-				return errors.New("cannot drop more than what is owned")
+				if a.Quantity() >= uint32(quantity) {
+					return newError(compartment.ErrorReasonReserved, inventoryType, source)
+				}
+				return newError(compartment.ErrorReasonInsufficientQuantity, inventoryType, source)
 			}
 			if initialQty == uint32(quantity) {
 				err = p.assetProcessor.WithTransaction(tx).Drop(mb)(transactionId, characterId, c.Id())(a)
@@ -642,53 +1022,139 @@ func (p *Processor) Drop(mb *message.Buffer) func(transactionId uuid.UUID, chara
 	}
 }
 
-func (p *Processor) RequestReserveAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, reservationRequests []ReservationRequest) error {
+func (p *Processor) RequestReserveAndEmit(transactionId uuid.UUID, characterId uint32, reservationRequests []ReservationRequest, ttl time.Duration) error {
 	return message.Emit(p.producer)(func(buf *message.Buffer) error {
-		return p.RequestReserve(buf)(transactionId, characterId, inventoryType, reservationRequests)
+		return p.RequestReserve(buf)(transactionId, characterId, reservationRequests, ttl)
 	})
 }
 
-func (p *Processor) RequestReserve(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, reservationRequests []ReservationRequest) error {
-	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, reservationRequests []ReservationRequest) error {
-		p.l.Debugf("Character [%d] attempting to reserve [%d] inventory [%d] reservation [%s].", characterId, len(reservationRequests), inventoryType, transactionId.String())
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
+// RequestReserve reserves every requested item, potentially across several compartments, or none of them.
+// A zero ttl requests the tenant default, and no hold may outlive the tenant maximum.
+func (p *Processor) RequestReserve(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, reservationRequests []ReservationRequest, ttl time.Duration) error {
+	return func(transactionId uuid.UUID, characterId uint32, reservationRequests []ReservationRequest, ttl time.Duration) error {
+		p.l.Debugf("Character [%d] attempting to reserve [%d] items via reservation request [%s].", characterId, len(reservationRequests), transactionId.String())
+		ttl = configuration.GetTenantConfig(p.t.Id()).Reservation.TimeToLive(ttl)
 
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
-			if err != nil {
-				return err
+		inventoryTypes := make([]inventory.Type, 0)
+		for _, request := range reservationRequests {
+			if !slices.Contains(inventoryTypes, request.InventoryType) {
+				inventoryTypes = append(inventoryTypes, request.InventoryType)
 			}
+		}
+
+		compartmentIds := make(map[inventory.Type]uuid.UUID)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryTypes...), func(tx *gorm.DB) error {
 			for _, request := range reservationRequests {
-				var a asset.Model[any]
-				a, err = p.assetProcessor.WithTransaction(tx).GetBySlot(c.Id(), request.Slot)
+				if request.Quantity <= 0 {
+					return newError(compartment.ErrorReasonInvalidQuantity, request.InventoryType, request.Slot)
+				}
+				compartmentId, ok := compartmentIds[request.InventoryType]
+				if !ok {
+					c, err := model.Map(Make)(getByCharacterAndType(p.t.Id(), characterId, request.InventoryType)(tx))()
+					if err != nil {
+						return notFound(err, compartment.ErrorReasonCompartmentNotFound, request.InventoryType, request.Slot)
+					}
+					compartmentId = c.Id()
+					compartmentIds[request.InventoryType] = compartmentId
+				}
+				a, err := p.assetProcessor.WithTransaction(tx).GetBySlot(compartmentId, request.Slot)
 				if err != nil {
-					return err
+					return notFound(err, compartment.ErrorReasonSlotEmpty, request.InventoryType, request.Slot)
 				}
 				if a.TemplateId() != request.ItemId {
-					return errors.New("cannot reserve non-existent item")
+					return newError(compartment.ErrorReasonTemplateMismatch, request.InventoryType, request.Slot)
+				}
+				currentReservedQty, err := p.reservationProcessor.WithTransaction(tx).GetReservedQuantity(characterId, request.InventoryType, request.Slot)
+				if err != nil {
+					return err
 				}
-				currentReservedQty := GetReservationRegistry().GetReservedQuantity(p.t, characterId, inventoryType, request.Slot)
-				if a.Quantity()-currentReservedQty < uint32(request.Quantity) {
-					return errors.New("cannot reserve more than what is owned")
+				if a.Quantity() < currentReservedQty || a.Quantity()-currentReservedQty < uint32(request.Quantity) {
+					return newError(compartment.ErrorReasonInsufficientQuantity, request.InventoryType, request.Slot)
 				}
-				_, err = GetReservationRegistry().AddReservation(p.t, transactionId, characterId, inventoryType, request.Slot, request.ItemId, uint32(request.Quantity), time.Second*time.Duration(30))
+				_, err = p.reservationProcessor.WithTransaction(tx).Add(transactionId, characterId, request.InventoryType, request.Slot, request.ItemId, uint32(request.Quantity), ttl)
 				if err != nil {
 					return err
 				}
-				return mb.Put(compartment.EnvEventTopicStatus, ReservedEventStatusProvider(transactionId, c.Id(), characterId, request.ItemId, request.Slot, uint32(request.Quantity)))
 			}
-			return nil
+
+			summaryCompartmentId := uuid.Nil
+			if len(compartmentIds) == 1 {
+				summaryCompartmentId = compartmentIds[inventoryTypes[0]]
+			}
+			for _, request := range reservationRequests {
+				err := mb.Put(compartment.EnvEventTopicStatus, ReservedEventStatusProvider(transactionId, compartmentIds[request.InventoryType], characterId, request.ItemId, request.Slot, uint32(request.Quantity)))
+				if err != nil {
+					return err
+				}
+			}
+			return mb.Put(compartment.EnvEventTopicStatus, ReservationCompleteEventStatusProvider(transactionId, summaryCompartmentId, characterId, reservationRequests))
 		})
 		if txErr != nil {
-			p.l.WithError(txErr).Errorf("Character [%d] unable to reserve [%d] inventory [%d] via reservation request [%s].", characterId, len(reservationRequests), inventoryType, transactionId.String())
+			p.l.WithError(txErr).Errorf("Character [%d] unable to reserve [%d] items via reservation request [%s].", characterId, len(reservationRequests), transactionId.String())
 			return txErr
 		}
+		p.l.Debugf("Character [%d] reserved [%d] items via reservation request [%s].", characterId, len(reservationRequests), transactionId.String())
 		return nil
 	}
 }
 
+func (p *Processor) RenewReservationAndEmit(transactionId uuid.UUID, characterId uint32, ttl time.Duration) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return p.RenewReservation(buf)(transactionId, characterId, ttl)
+	})
+}
+
+// RenewReservation extends the holds a transaction still owns, locking the compartments they are in. Holds which
+// already lapsed cannot be renewed, and no hold outlives the tenant's maximum time to live from when it was made.
+func (p *Processor) RenewReservation(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, ttl time.Duration) error {
+	return func(transactionId uuid.UUID, characterId uint32, ttl time.Duration) error {
+		p.l.Debugf("Character [%d] attempting to renew reservation [%s].", characterId, transactionId.String())
+		rc := configuration.GetTenantConfig(p.t.Id()).Reservation
+		ttl = rc.TimeToLive(ttl)
+
+		held, err := p.reservationProcessor.ActiveProvider(characterId, 0, transactionId)()
+		if err != nil {
+			return err
+		}
+		if len(held) == 0 {
+			return newError(compartment.ErrorReasonNotReserved, 0, 0)
+		}
+		var inventoryTypes []inventory.Type
+		for _, r := range held {
+			if !slices.Contains(inventoryTypes, r.InventoryType()) {
+				inventoryTypes = append(inventoryTypes, r.InventoryType())
+			}
+		}
+
+		return p.transaction(mb, p.lockKeys(characterId, inventoryTypes...), func(tx *gorm.DB) error {
+			rs, err := p.reservationProcessor.WithTransaction(tx).Renew(transactionId, characterId, ttl, time.Duration(rc.MaxTtl))
+			if err != nil {
+				return err
+			}
+			if len(rs) == 0 {
+				return newError(compartment.ErrorReasonNotReserved, 0, 0)
+			}
+			compartmentIds := make(map[inventory.Type]uuid.UUID)
+			for _, r := range rs {
+				compartmentId, ok := compartmentIds[r.InventoryType()]
+				if !ok {
+					c, err := model.Map(Make)(getByCharacterAndType(p.t.Id(), characterId, r.InventoryType())(tx))()
+					if err != nil {
+						return err
+					}
+					compartmentId = c.Id()
+					compartmentIds[r.InventoryType()] = compartmentId
+				}
+				err = mb.Put(compartment.EnvEventTopicStatus, ReservationRenewedEventStatusProvider(transactionId, compartmentId, characterId, r.ItemId(), r.Slot(), r.Quantity(), r.Expiry()))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
 func (p *Processor) CancelReservationAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
 	return message.Emit(p.producer)(func(buf *message.Buffer) error {
 		return p.CancelReservation(buf)(transactionId, characterId, inventoryType, slot)
@@ -698,53 +1164,465 @@ func (p *Processor) CancelReservationAndEmit(transactionId uuid.UUID, characterI
 func (p *Processor) CancelReservation(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
 		p.l.Debugf("Character [%d] attempting to cancel inventory [%d] reservation [%s].", characterId, inventoryType, transactionId.String())
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
+		return p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
+			if err != nil {
+				return err
+			}
+			res, err := p.reservationProcessor.WithTransaction(tx).Remove(transactionId, characterId, inventoryType, slot)
+			if err != nil {
+				return notFound(err, compartment.ErrorReasonNotReserved, inventoryType, slot)
+			}
+			return mb.Put(compartment.EnvEventTopicStatus, ReservationCancelledEventStatusProvider(transactionId, c.Id(), characterId, res.ItemId(), slot, res.Quantity()))
+		})
+	}
+}
+
+// ReportFailureAndEmit emits an ERROR status event carrying errorCode when a command failed, so the requester is not
+// left waiting on a result which will never come. The event names the compartment of the inventory type the failure
+// concerns, where the character has one. A duplicate command has already been answered.
+func (p *Processor) ReportFailureAndEmit(transactionId uuid.UUID, characterId uint32, errorCode string, err error) error {
+	if err == nil || errors.Is(err, ledger.ErrDuplicate) {
+		return nil
+	}
+	cause := Classify(err)
+	p.l.WithError(err).Warnf("Character [%d] command failed with [%s]. Transaction [%s].", characterId, cause.Reason, transactionId.String())
+	compartmentId := uuid.Nil
+	if cause.InventoryType != 0 {
+		c, cErr := model.Map(Make)(getByCharacterAndType(p.t.Id(), characterId, cause.InventoryType)(p.db))()
+		if cErr == nil {
+			compartmentId = c.Id()
+		}
+	}
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return buf.Put(compartment.EnvEventTopicStatus, ErrorEventStatusProvider(transactionId, compartmentId, characterId, errorCode, cause))
+	})
+}
+
+func (p *Processor) ExpireReservationsAndEmit(now time.Time) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return p.ExpireReservations(buf)(now)
+	})
+}
 
-		c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+// ExpireReservations releases every reservation held in the tenant which has lapsed as of now, so requesting services can roll back their side.
+func (p *Processor) ExpireReservations(mb *message.Buffer) func(now time.Time) error {
+	return func(now time.Time) error {
+		rs, err := p.reservationProcessor.ExpiredProvider(now)()
 		if err != nil {
+			p.l.WithError(err).Errorf("Unable to retrieve expired reservations.")
 			return err
 		}
+		for _, r := range rs {
+			err = p.expireReservation(mb)(r, now)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to expire reservation [%s] for character [%d] inventory [%d] slot [%d].", r.TransactionId().String(), r.CharacterId(), r.InventoryType(), r.Slot())
+			}
+		}
+		return nil
+	}
+}
+
+func (p *Processor) expireReservation(mb *message.Buffer) func(r reservation.Model, now time.Time) error {
+	return func(r reservation.Model, now time.Time) error {
+		return p.transaction(mb, p.lockKeys(r.CharacterId(), r.InventoryType()), func(tx *gorm.DB) error {
+			expired, err := p.reservationProcessor.WithTransaction(tx).Expire(r.Id(), now)
+			if err != nil {
+				return err
+			}
+			if !expired {
+				return nil
+			}
+
+			compartmentId := uuid.Nil
+			c, err := model.Map(Make)(getByCharacterAndType(p.t.Id(), r.CharacterId(), r.InventoryType())(tx))()
+			if err == nil {
+				compartmentId = c.Id()
+			}
+			p.l.Debugf("Reservation [%s] of [%d] item [%d] in slot [%d] expired for character [%d].", r.TransactionId().String(), r.Quantity(), r.ItemId(), r.Slot(), r.CharacterId())
+			return mb.Put(compartment.EnvEventTopicStatus, ReservationExpiredEventStatusProvider(r.TransactionId(), compartmentId, r.CharacterId(), r.ItemId(), r.Slot(), r.Quantity()))
+		})
+	}
+}
+
+func (p *Processor) ExpireAssetsAndEmit(now time.Time) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return p.ExpireAssets(buf)(now)
+	})
+}
+
+// ExpireAssets removes, or converts to their replacement, every asset whose expiration has passed as of now.
+func (p *Processor) ExpireAssets(mb *message.Buffer) func(now time.Time) error {
+	return func(now time.Time) error {
+		as, err := p.assetProcessor.ExpiredProvider(now)()
+		if err != nil {
+			p.l.WithError(err).Errorf("Unable to retrieve expired assets.")
+			return err
+		}
+		for _, a := range as {
+			err = p.expireAsset(mb)(a, now)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to expire asset [%d] of item [%d] in compartment [%s].", a.Id(), a.TemplateId(), a.CompartmentId())
+			}
+		}
+		return nil
+	}
+}
+
+func (p *Processor) expireAsset(mb *message.Buffer) func(a asset.Model[any], now time.Time) error {
+	return func(a asset.Model[any], now time.Time) error {
+		c, err := model.Map(Make)(getById(p.t.Id(), a.CompartmentId())(p.db))()
+		if err != nil {
+			return err
+		}
+		return p.transaction(mb, p.lockKeys(c.CharacterId(), c.Type()), func(tx *gorm.DB) error {
+			// The asset may have been removed, or its expiration extended, since it was found. Its reference is not
+			// resolved, so that no request to another service is made under the lock, apart from the local stackable
+			// whose quantity carries over to a replacement.
+			current, err := p.assetProcessor.WithTransaction(tx).UndecoratedByIdProvider(a.Id())()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if current.Expiration().IsZero() || current.Expiration().After(now) {
+				return nil
+			}
+			if current.IsConsumable() || current.IsSetup() || current.IsEtc() {
+				current, err = p.assetProcessor.WithTransaction(tx).DecorateStackable(current)
+				if err != nil {
+					return err
+				}
+			}
+			return p.assetProcessor.WithTransaction(tx).Expire(mb)(uuid.New(), c.CharacterId(), c.Id())(current)
+		})
+	}
+}
+
+func (p *Processor) WarnExpiringAssetsAndEmit(now time.Time) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return p.WarnExpiringAssets(buf)(now)
+	})
+}
 
-		res, err := GetReservationRegistry().RemoveReservation(p.t, transactionId, characterId, inventoryType, slot)
+// WarnExpiringAssets announces every asset which will expire within one of the tenant's warning thresholds. Each
+// threshold is announced at most once per asset and expiration.
+func (p *Processor) WarnExpiringAssets(mb *message.Buffer) func(now time.Time) error {
+	return func(now time.Time) error {
+		_, err := p.warningProcessor.PurgeLapsed(now)
 		if err != nil {
+			p.l.WithError(err).Errorf("Unable to purge lapsed expiry warnings.")
+			return err
+		}
+		thresholds := configuration.GetTenantConfig(p.t.Id()).Expiry.Thresholds()
+		if len(thresholds) == 0 {
 			return nil
 		}
-		return mb.Put(compartment.EnvEventTopicStatus, ReservationCancelledEventStatusProvider(transactionId, c.Id(), characterId, res.ItemId(), slot, res.Quantity()))
+		as, err := p.assetProcessor.ExpiringProvider(now, now.Add(thresholds[0]))()
+		if err != nil {
+			p.l.WithError(err).Errorf("Unable to retrieve expiring assets.")
+			return err
+		}
+		for _, a := range as {
+			err = p.warnExpiringAsset(mb)(a, now, thresholds)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to warn of expiring asset [%d] of item [%d] in compartment [%s].", a.Id(), a.TemplateId(), a.CompartmentId())
+			}
+		}
+		return nil
 	}
 }
 
-func (p *Processor) ConsumeAssetAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
+// warnExpiringAsset announces the tightest threshold an asset has crossed which it was not yet warned of. Looser
+// thresholds crossed at the same time, as when the asset was created close to expiring, are recorded without being
+// announced.
+func (p *Processor) warnExpiringAsset(mb *message.Buffer) func(a asset.Model[any], now time.Time, thresholds []time.Duration) error {
+	return func(a asset.Model[any], now time.Time, thresholds []time.Duration) error {
+		c, err := model.Map(Make)(getById(p.t.Id(), a.CompartmentId())(p.db))()
+		if err != nil {
+			return err
+		}
+		return p.transaction(mb, p.lockKeys(c.CharacterId(), c.Type()), func(tx *gorm.DB) error {
+			current, err := p.assetProcessor.WithTransaction(tx).GetById(a.Id())
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if current.Expiration().IsZero() || !current.Expiration().After(now) {
+				return nil
+			}
+			sent, err := p.warningProcessor.WithTransaction(tx).SentThresholds(current.Id(), current.Expiration())
+			if err != nil {
+				return err
+			}
+			remaining := current.Expiration().Sub(now)
+			var due time.Duration
+			for _, threshold := range thresholds {
+				if remaining > threshold || sent[threshold] {
+					continue
+				}
+				err = p.warningProcessor.WithTransaction(tx).Record(current.Id(), current.Expiration(), threshold, now)
+				if err != nil {
+					return err
+				}
+				due = threshold
+			}
+			if due == 0 {
+				return nil
+			}
+			return p.assetProcessor.WarnExpiring(mb)(uuid.New(), c.CharacterId())(current, due)
+		})
+	}
+}
+
+func (p *Processor) DiscardDanglingAssetAndEmit(a asset.Model[any]) error {
 	return message.Emit(p.producer)(func(buf *message.Buffer) error {
-		return p.ConsumeAsset(buf)(transactionId, characterId, inventoryType, slot)
+		return p.DiscardDanglingAsset(buf)(a)
 	})
 }
 
-func (p *Processor) ConsumeAsset(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
-	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
-		p.l.Debugf("Character [%d] attempting to consume asset in inventory [%d] slot [%d]. Transaction [%s].", characterId, inventoryType, slot, transactionId.String())
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
+// DiscardDanglingAsset removes an asset whose reference no longer exists from its compartment.
+func (p *Processor) DiscardDanglingAsset(mb *message.Buffer) func(a asset.Model[any]) error {
+	return func(a asset.Model[any]) error {
+		c, err := model.Map(Make)(getById(p.t.Id(), a.CompartmentId())(p.db))()
+		if err != nil {
+			return err
+		}
+		return p.transaction(mb, p.lockKeys(c.CharacterId(), c.Type()), func(tx *gorm.DB) error {
+			current, err := p.assetProcessor.WithTransaction(tx).UndecoratedByIdProvider(a.Id())()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			p.l.Infof("Discarding asset [%d] of item [%d] for character [%d], as reference [%d] no longer exists.", current.Id(), current.TemplateId(), c.CharacterId(), current.ReferenceId())
+			return p.assetProcessor.WithTransaction(tx).Discard(mb)(uuid.New(), c.CharacterId(), c.Id())(current)
+		})
+	}
+}
 
-		res, err := GetReservationRegistry().RemoveReservation(p.t, transactionId, characterId, inventoryType, slot)
+func (p *Processor) CheckConsistencyAndEmit(characterId uint32, fix bool) ([]Violation, error) {
+	var vs []Violation
+	err := message.Emit(p.producer)(func(buf *message.Buffer) error {
+		var err error
+		vs, err = p.CheckConsistency(buf)(characterId, fix)
+		return err
+	})
+	return vs, err
+}
+
+// CheckConsistency finds the assets in a character's compartments which collide, sit in the temporary slot, or lie
+// outside their compartment's capacity. When fix is set, each is moved into a free slot where that is safe.
+func (p *Processor) CheckConsistency(mb *message.Buffer) func(characterId uint32, fix bool) ([]Violation, error) {
+	return func(characterId uint32, fix bool) ([]Violation, error) {
+		cs, err := model.SliceMap(Make)(getByCharacter(p.t.Id(), characterId)(p.db))(model.ParallelMap())()
 		if err != nil {
+			return nil, err
+		}
+		return p.checkCompartments(mb)(cs, fix)
+	}
+}
+
+func (p *Processor) CheckTenantConsistencyAndEmit(fix bool) ([]Violation, error) {
+	var vs []Violation
+	err := message.Emit(p.producer)(func(buf *message.Buffer) error {
+		var err error
+		vs, err = p.CheckTenantConsistency(buf)(fix)
+		return err
+	})
+	return vs, err
+}
+
+// CheckTenantConsistency checks every compartment of the tenant, as CheckConsistency does for one character.
+func (p *Processor) CheckTenantConsistency(mb *message.Buffer) func(fix bool) ([]Violation, error) {
+	return func(fix bool) ([]Violation, error) {
+		cs, err := model.SliceMap(Make)(getAll(p.t.Id())(p.db))(model.ParallelMap())()
+		if err != nil {
+			return nil, err
+		}
+		return p.checkCompartments(mb)(cs, fix)
+	}
+}
+
+func (p *Processor) checkCompartments(mb *message.Buffer) func(cs []Model, fix bool) ([]Violation, error) {
+	return func(cs []Model, fix bool) ([]Violation, error) {
+		transactionId := uuid.New()
+		results := make([]Violation, 0)
+		for _, c := range cs {
+			vs, err := p.checkCompartment(mb)(transactionId, c, fix)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to check consistency of compartment [%s] for character [%d].", c.Id(), c.CharacterId())
+				return nil, err
+			}
+			results = append(results, vs...)
+		}
+		return results, nil
+	}
+}
+
+// checkCompartment inspects one compartment under its lock. The oldest asset in a slot keeps it. An asset is only
+// moved into a free slot, and never out of a slot held by a reservation, as the reservation could not tell which
+// asset it meant.
+func (p *Processor) checkCompartment(mb *message.Buffer) func(transactionId uuid.UUID, c Model, fix bool) ([]Violation, error) {
+	return func(transactionId uuid.UUID, c Model, fix bool) ([]Violation, error) {
+		var vs []Violation
+		err := p.transaction(mb, p.lockKeys(c.CharacterId(), c.Type()), func(tx *gorm.DB) error {
+			vs = nil
+			as, err := p.assetProcessor.WithTransaction(tx).UndecoratedByCompartmentIdProvider(c.Id())()
+			if err != nil {
+				return err
+			}
+			sort.Slice(as, func(i, j int) bool { return as[i].Id() < as[j].Id() })
+
+			occupied := make(map[int16]bool)
+			var misplaced []Violation
+			var misplacedAssets []asset.Model[any]
+			for _, a := range as {
+				kind := ""
+				if a.Slot() == int16(math.MinInt16) {
+					kind = ViolationTemporarySlot
+				} else if occupied[a.Slot()] {
+					kind = ViolationSlotCollision
+				} else if a.Slot() > int16(c.Capacity()) || (a.Slot() <= 0 && c.Type() != inventory.TypeValueEquip) {
+					kind = ViolationBeyondCapacity
+				}
+				if kind == "" {
+					occupied[a.Slot()] = true
+					continue
+				}
+				misplaced = append(misplaced, Violation{kind: kind, characterId: c.CharacterId(), inventoryType: c.Type(), compartmentId: c.Id(), assetId: a.Id(), templateId: a.TemplateId(), slot: a.Slot()})
+				misplacedAssets = append(misplacedAssets, a)
+			}
+
+			next := int16(1)
+			for i, v := range misplaced {
+				if fix && v.kind != ViolationTemporarySlot {
+					reserved, err := p.reservationProcessor.WithTransaction(tx).GetReservedQuantity(c.CharacterId(), c.Type(), v.slot)
+					if err != nil {
+						return err
+					}
+					if reserved > 0 {
+						p.l.Warnf("Unable to fix [%s] of asset [%d] for character [%d], as slot [%d] is reserved.", v.kind, v.assetId, v.characterId, v.slot)
+						vs = append(vs, v)
+						continue
+					}
+				}
+				if fix {
+					for next <= int16(c.Capacity()) && occupied[next] {
+						next++
+					}
+					if next <= int16(c.Capacity()) {
+						err = p.assetProcessor.WithTransaction(tx).Reslot(mb)(transactionId, c.CharacterId(), c.Id())(misplacedAssets[i], next)
+						if err != nil {
+							return err
+						}
+						occupied[next] = true
+						v.fixed = true
+						v.newSlot = next
+					}
+				}
+				p.l.Warnf("Character [%d] asset [%d] in slot [%d] of inventory [%d] violates [%s]. Fixed [%t].", v.characterId, v.assetId, v.slot, v.inventoryType, v.kind, v.fixed)
+				vs = append(vs, v)
+			}
 			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
+		return vs, nil
+	}
+}
+
+func (p *Processor) CancelReservationsAndEmit(transactionId uuid.UUID, characterId uint32) ([]reservation.Model, error) {
+	var rs []reservation.Model
+	err := message.Emit(p.producer)(func(buf *message.Buffer) error {
+		var err error
+		rs, err = p.CancelReservations(buf)(transactionId, characterId)
+		return err
+	})
+	return rs, err
+}
 
+// CancelReservations releases every hold a transaction owns for the character, across all compartments.
+func (p *Processor) CancelReservations(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32) ([]reservation.Model, error) {
+	return func(transactionId uuid.UUID, characterId uint32) ([]reservation.Model, error) {
+		p.l.Debugf("Character [%d] attempting to cancel all reservations of [%s].", characterId, transactionId.String())
+		held, err := p.reservationProcessor.ActiveProvider(characterId, 0, transactionId)()
+		if err != nil {
+			return nil, err
+		}
+		inventoryTypes := make([]inventory.Type, 0)
+		for _, r := range held {
+			if !slices.Contains(inventoryTypes, r.InventoryType()) {
+				inventoryTypes = append(inventoryTypes, r.InventoryType())
+			}
+		}
+
+		var rs []reservation.Model
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryTypes...), func(tx *gorm.DB) error {
+			rs, err = p.reservationProcessor.WithTransaction(tx).RemoveByTransaction(transactionId, characterId)
+			if err != nil {
+				return err
+			}
+			compartmentIds := make(map[inventory.Type]uuid.UUID)
+			for _, r := range rs {
+				compartmentId, ok := compartmentIds[r.InventoryType()]
+				if !ok {
+					c, err := model.Map(Make)(getByCharacterAndType(p.t.Id(), characterId, r.InventoryType())(tx))()
+					if err != nil {
+						return err
+					}
+					compartmentId = c.Id()
+					compartmentIds[r.InventoryType()] = compartmentId
+				}
+				err = mb.Put(compartment.EnvEventTopicStatus, ReservationCancelledEventStatusProvider(transactionId, compartmentId, characterId, r.ItemId(), r.Slot(), r.Quantity()))
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if txErr != nil {
+			p.l.WithError(txErr).Errorf("Character [%d] unable to cancel reservations of [%s].", characterId, transactionId.String())
+			return nil, txErr
+		}
+		return rs, nil
+	}
+}
+
+func (p *Processor) ConsumeAssetAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
+		return p.ConsumeAsset(buf)(transactionId, characterId, inventoryType, slot)
+	})
+}
+
+func (p *Processor) ConsumeAsset(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
+	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) error {
+		p.l.Debugf("Character [%d] attempting to consume asset in inventory [%d] slot [%d]. Transaction [%s].", characterId, inventoryType, slot, transactionId.String())
+		var res reservation.Model
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			var c Model
-			c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				return err
 			}
+			res, err = p.reservationProcessor.WithTransaction(tx).Remove(transactionId, characterId, inventoryType, slot)
+			if err != nil {
+				return notFound(err, compartment.ErrorReasonNotReserved, inventoryType, slot)
+			}
 			a, err = p.assetProcessor.WithTransaction(tx).GetBySlot(c.Id(), slot)
+			if err != nil {
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventoryType, slot)
+			}
+			var reservedQty uint32
+			reservedQty, err = p.reservationProcessor.WithTransaction(tx).GetReservedQuantity(characterId, inventoryType, slot)
 			if err != nil {
 				return err
 			}
-			reservedQty := GetReservationRegistry().GetReservedQuantity(p.t, characterId, inventoryType, slot)
 			initialQty := a.Quantity() - reservedQty
 			if initialQty <= 1 {
 				err = p.assetProcessor.WithTransaction(tx).Delete(mb)(transactionId, characterId, c.Id())(a)
@@ -761,7 +1639,7 @@ func (p *Processor) ConsumeAsset(mb *message.Buffer) func(transactionId uuid.UUI
 			return nil
 		})
 		if txErr != nil {
-			p.l.WithError(err).Errorf("Character [%d] unable to consume asset in inventory [%d] slot [%d]. Transaction [%d].", characterId, inventoryType, transactionId, slot)
+			p.l.WithError(txErr).Errorf("Character [%d] unable to consume asset in inventory [%d] slot [%d]. Transaction [%s].", characterId, inventoryType, slot, transactionId.String())
 			return txErr
 		}
 		p.l.Debugf("Character [%d] consumed [%d] of item [%d].", characterId, res.Quantity(), a.TemplateId())
@@ -778,19 +1656,15 @@ func (p *Processor) DestroyAssetAndEmit(transactionId uuid.UUID, characterId uin
 func (p *Processor) DestroyAsset(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, quantity uint32) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, quantity uint32) error {
 		p.l.Debugf("Character [%d] attempting to destroy [%d] asset in inventory [%d] slot [%d].", characterId, quantity, inventoryType, slot)
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
 			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				return err
 			}
 			a, err = p.assetProcessor.WithTransaction(tx).GetBySlot(c.Id(), slot)
 			if err != nil {
-				return err
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventoryType, slot)
 			}
 
 			// If the asset doesn't have quantity, or if the asset's quantity is less than or equal to the quantity provided, delete it
@@ -826,10 +1700,9 @@ func (p *Processor) CreateAssetAndEmit(transactionId uuid.UUID, characterId uint
 
 func (p *Processor) CreateAssetAndLock(mb *message.Buffer) func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error {
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, templateId uint32, quantity uint32, expiration time.Time, ownerId uint32, flag uint16, rechargeable uint64) error {
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-		return p.CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, quantity, expiration, ownerId, flag, rechargeable)
+		return p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			return p.WithTransaction(tx).CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, quantity, expiration, ownerId, flag, rechargeable)
+		})
 	}
 }
 
@@ -838,21 +1711,21 @@ func (p *Processor) CreateAsset(mb *message.Buffer) func(transactionId uuid.UUID
 		p.l.Debugf("Character [%d] attempting to create asset in inventory [%d].", characterId, inventoryType)
 
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				return err
 			}
 			nfs, err := c.NextFreeSlot()
 			if err != nil {
-				return err
+				return newError(compartment.ErrorReasonInventoryFull, inventoryType, 0)
 			}
 			a, err = p.assetProcessor.WithTransaction(tx).Create(mb)(transactionId, characterId, c.Id(), templateId, nfs, quantity, expiration, ownerId, flag, rechargeable)
 			if err != nil {
 				return err
 			}
 			return nil
-		})
+		}))
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Character [%d] unable to create asset in inventory [%d].", characterId, inventoryType)
 			return txErr
@@ -863,7 +1736,7 @@ func (p *Processor) CreateAsset(mb *message.Buffer) func(transactionId uuid.UUID
 }
 
 func (p *Processor) AttemptEquipmentPickUpAndEmit(transactionId uuid.UUID, m _map.Model, characterId uint32, dropId uint32, templateId uint32, referenceId uint32) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
 		return p.AttemptEquipmentPickUp(buf)(transactionId, m, characterId, dropId, templateId, referenceId)
 	})
 }
@@ -882,12 +1755,8 @@ func (p *Processor) AttemptEquipmentPickUp(mb *message.Buffer) func(transactionI
 		}
 
 		p.l.Debugf("Gaining [%d] item [%d] for character [%d] in inventory [%d].", 1, templateId, characterId, inventoryType)
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to locate inventory [%d] for character [%d].", inventoryType, characterId)
 				return err
@@ -914,7 +1783,7 @@ func (p *Processor) AttemptEquipmentPickUp(mb *message.Buffer) func(transactionI
 }
 
 func (p *Processor) AttemptItemPickUpAndEmit(transactionId uuid.UUID, m _map.Model, characterId uint32, dropId uint32, templateId uint32, quantity uint32) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	return message.Emit(p.producer)(func(buf *message.Buffer) error {
 		return p.AttemptItemPickUp(buf)(transactionId, m, characterId, dropId, templateId, quantity)
 	})
 }
@@ -926,11 +1795,7 @@ func (p *Processor) AttemptItemPickUp(mb *message.Buffer) func(transactionId uui
 			return errors.New("invalid inventory item")
 		}
 
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
 			// Get the compartment for the character and inventory type
 			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
@@ -979,7 +1844,7 @@ func (p *Processor) AttemptItemPickUp(mb *message.Buffer) func(transactionId uui
 					p.l.Debugf("Character [%d] increased quantity of asset [%d] to max [%d].", characterId, assetToUpdate.Id(), slotMax)
 
 					// Create a new asset with the remaining quantity
-					err = p.CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, remainingQuantity, time.Time{}, 0, 0, 0)
+					err = p.WithTransaction(tx).CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, remainingQuantity, time.Time{}, 0, 0, 0)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to create asset [%d] for character [%d] with remaining quantity [%d].", templateId, characterId, remainingQuantity)
 						return err
@@ -995,7 +1860,7 @@ func (p *Processor) AttemptItemPickUp(mb *message.Buffer) func(transactionId uui
 				}
 			} else {
 				// Create a new asset
-				err = p.CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, quantity, time.Time{}, 0, 0, 0)
+				err = p.WithTransaction(tx).CreateAsset(mb)(transactionId, characterId, inventoryType, templateId, quantity, time.Time{}, 0, 0, 0)
 				if err != nil {
 					p.l.WithError(err).Errorf("Unable to create asset [%d] for character [%d].", templateId, characterId)
 					return err
@@ -1025,15 +1890,11 @@ func (p *Processor) RechargeAsset(mb *message.Buffer) func(transactionId uuid.UU
 		// Only TypeValueUse compartment type should support this functionality
 		if inventoryType != inventory.TypeValueUse {
 			p.l.Errorf("Recharge operation not supported for inventory type [%d]. Only TypeValueUse is supported.", inventoryType)
-			return errors.New("recharge operation not supported for this inventory type")
+			return newError(compartment.ErrorReasonNotRechargeable, inventoryType, slot)
 		}
 
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
 			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
@@ -1044,7 +1905,7 @@ func (p *Processor) RechargeAsset(mb *message.Buffer) func(transactionId uuid.UU
 			a, err = p.assetProcessor.WithTransaction(tx).GetBySlot(c.Id(), slot)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get asset in compartment [%s] by slot [%d].", c.Id(), slot)
-				return err
+				return notFound(err, compartment.ErrorReasonSlotEmpty, inventoryType, slot)
 			}
 
 			// Update the quantity with the provided quantity
@@ -1084,18 +1945,12 @@ func (p *Processor) MergeAndCompact(mb *message.Buffer) func(transactionId uuid.
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type) error {
 		p.l.Debugf("Character [%d] attempting to merge and compact assets in inventory [%d].", characterId, inventoryType)
 
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
-		var compartmentId uuid.UUID
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
 				return err
 			}
-			compartmentId = c.Id()
 			as := c.Assets()
 			sort.Slice(as, func(i, j int) bool {
 				return as[i].Slot() < as[j].Slot()
@@ -1112,15 +1967,16 @@ func (p *Processor) MergeAndCompact(mb *message.Buffer) func(transactionId uuid.
 			// Merge combinable assets.
 			for i := 0; i < len(positiveSlotAssets); i++ {
 				for j := i + 1; j < len(positiveSlotAssets); j++ {
-					if p.canMergeAssets(c.Type(), positiveSlotAssets[j], positiveSlotAssets[i], characterId) {
-						err = p.Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[j].Slot(), positiveSlotAssets[i].Slot())
+					if p.WithTransaction(tx).canMergeAssets(c.Type(), positiveSlotAssets[j], positiveSlotAssets[i], characterId) {
+						err = p.WithTransaction(tx).Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[j].Slot(), positiveSlotAssets[i].Slot())
 						if err != nil {
 							p.l.WithError(err).Errorf("Unable to move assets [%d] and [%d] in compartment [%s].", positiveSlotAssets[i].Id(), positiveSlotAssets[j].Id(), c.Id())
 							return err
 						}
-						c, err = p.GetByCharacterAndType(characterId)(inventoryType)
+						c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 						if err != nil {
 							p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
+							return err
 						}
 						as = c.Assets()
 
@@ -1148,14 +2004,15 @@ func (p *Processor) MergeAndCompact(mb *message.Buffer) func(transactionId uuid.
 					continue
 				}
 				if positiveSlotAssets[i].Slot() >= nextFree {
-					err = p.Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[i].Slot(), nextFree)
+					err = p.WithTransaction(tx).Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[i].Slot(), nextFree)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to move assets [%d] in compartment [%s].", positiveSlotAssets[i].Id(), c.Id())
 						return err
 					}
-					c, err = p.GetByCharacterAndType(characterId)(inventoryType)
+					c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
+						return err
 					}
 					as = c.Assets()
 
@@ -1173,7 +2030,8 @@ func (p *Processor) MergeAndCompact(mb *message.Buffer) func(transactionId uuid.
 				}
 			}
 
-			return nil
+			// Emit the status event for successful completion
+			return mb.Put(compartment.EnvEventTopicStatus, MergeCompleteEventStatusProvider(transactionId, c.Id(), characterId, inventoryType))
 		})
 
 		if txErr != nil {
@@ -1181,20 +2039,13 @@ func (p *Processor) MergeAndCompact(mb *message.Buffer) func(transactionId uuid.
 			return txErr
 		}
 
-		// Emit the status event for successful completion
-		err := mb.Put(compartment.EnvEventTopicStatus, MergeCompleteEventStatusProvider(transactionId, compartmentId, characterId, inventoryType))
-		if err != nil {
-			p.l.WithError(err).Errorf("Unable to emit merge and compact complete event for character [%d], inventory [%d].", characterId, inventoryType)
-			return err
-		}
-
 		p.l.Debugf("Character [%d] successfully merged and compacted assets in inventory [%d].", characterId, inventoryType)
 		return nil
 	}
 }
 
 func (p *Processor) AcceptAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, referenceId uint32) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(mb *message.Buffer) error {
+	return message.Emit(p.producer)(func(mb *message.Buffer) error {
 		return p.Accept(mb)(transactionId, characterId, inventoryType, referenceId)
 	})
 }
@@ -1203,14 +2054,9 @@ func (p *Processor) Accept(mb *message.Buffer) func(transactionId uuid.UUID, cha
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, referenceId uint32) error {
 		p.l.Debugf("Character [%d] attempting to accept asset referred to by [%d] in inventory [%d].", characterId, referenceId, inventoryType)
 
-		// Lock the inventory to prevent concurrent modifications
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
 		var c Model
 		var a asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
 			// Get the compartment for the character and inventory type
 			var err error
 			c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
@@ -1223,11 +2069,11 @@ func (p *Processor) Accept(mb *message.Buffer) func(transactionId uuid.UUID, cha
 			targetSlot, err := c.NextFreeSlot()
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to find next free slot in compartment [%s] for character [%d].", c.Id(), characterId)
-				return err
+				return newError(compartment.ErrorReasonInventoryFull, inventoryType, 0)
 			}
 
 			// Create the asset for the cash item
-			a, err = p.assetProcessor.WithTransaction(tx).Accept(mb)(characterId, c.Id(), c.Type(), targetSlot, referenceId)
+			a, err = p.assetProcessor.WithTransaction(tx).Accept(mb)(transactionId, characterId, c.Id(), c.Type(), targetSlot, referenceId)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to acquire cash item [%d] for character [%d].", referenceId, characterId)
 				return err
@@ -1239,8 +2085,7 @@ func (p *Processor) Accept(mb *message.Buffer) func(transactionId uuid.UUID, cha
 
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Character [%d] unable to move cash item [%d] to inventory [%d].", characterId, referenceId, inventoryType)
-			_ = mb.Put(compartment.EnvEventTopicStatus, ErrorEventStatusProvider(transactionId, c.Id(), characterId, compartment.AcceptCommandFailed))
-			return nil
+			return txErr
 		}
 
 		p.l.Debugf("Character [%d] successfully moved cash item [%d] to slot [%d] in inventory [%d].", characterId, referenceId, a.Slot(), inventoryType)
@@ -1249,7 +2094,7 @@ func (p *Processor) Accept(mb *message.Buffer) func(transactionId uuid.UUID, cha
 }
 
 func (p *Processor) ReleaseAndEmit(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, assetId uint32) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(mb *message.Buffer) error {
+	return message.Emit(p.producer)(func(mb *message.Buffer) error {
 		return p.Release(mb)(transactionId, characterId, inventoryType, assetId)
 	})
 }
@@ -1258,15 +2103,10 @@ func (p *Processor) Release(mb *message.Buffer) func(transactionId uuid.UUID, ch
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, assetId uint32) error {
 		p.l.Debugf("Character [%d] attempting to release asset [%d] from inventory [%d].", characterId, assetId, inventoryType)
 
-		// Lock the inventory to prevent concurrent modifications
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
 		var c Model
 		var foundAsset bool
 		var assetToRemove asset.Model[any]
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
 			// Get the compartment for the character and inventory type
 			var err error
 			c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
@@ -1294,7 +2134,7 @@ func (p *Processor) Release(mb *message.Buffer) func(transactionId uuid.UUID, ch
 
 			if !foundAsset {
 				p.l.Errorf("Unable to find asset [%d] in compartment [%s] for character [%d].", assetId, c.Id(), characterId)
-				return errors.New("unable to find asset in compartment")
+				return newError(compartment.ErrorReasonAssetNotFound, inventoryType, 0)
 			}
 
 			// Delete the asset silently (without emitting delete messages)
@@ -1312,8 +2152,7 @@ func (p *Processor) Release(mb *message.Buffer) func(transactionId uuid.UUID, ch
 
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Character [%d] unable to release asset [%d] from inventory [%d].", characterId, assetId, inventoryType)
-			_ = mb.Put(compartment.EnvEventTopicStatus, ErrorEventStatusProvider(transactionId, c.Id(), characterId, compartment.ReleaseCommandFailed))
-			return nil
+			return txErr
 		}
 
 		p.l.Debugf("Character [%d] successfully released asset [%d] from inventory [%d].", characterId, assetId, inventoryType)
@@ -1325,18 +2164,12 @@ func (p *Processor) CompactAndSort(mb *message.Buffer) func(transactionId uuid.U
 	return func(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type) error {
 		p.l.Debugf("Character [%d] attempting to compact and sort assets in inventory [%d].", characterId, inventoryType)
 
-		invLock := LockRegistry().Get(characterId, inventoryType)
-		invLock.Lock()
-		defer invLock.Unlock()
-
-		var compartmentId uuid.UUID
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
-			c, err := p.GetByCharacterAndType(characterId)(inventoryType)
+		txErr := p.transaction(mb, p.lockKeys(characterId, inventoryType), func(tx *gorm.DB) error {
+			c, err := p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 			if err != nil {
 				p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
 				return err
 			}
-			compartmentId = c.Id()
 			as := c.Assets()
 
 			// Filter out assets with negative slot values
@@ -1355,14 +2188,15 @@ func (p *Processor) CompactAndSort(mb *message.Buffer) func(transactionId uuid.U
 					continue
 				}
 				if positiveSlotAssets[i].Slot() >= nextFree {
-					err = p.Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[i].Slot(), nextFree)
+					err = p.WithTransaction(tx).Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[i].Slot(), nextFree)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to move assets [%d] in compartment [%s].", positiveSlotAssets[i].Id(), c.Id())
 						return err
 					}
-					c, err = p.GetByCharacterAndType(characterId)(inventoryType)
+					c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
+						return err
 					}
 					as = c.Assets()
 
@@ -1389,14 +2223,15 @@ func (p *Processor) CompactAndSort(mb *message.Buffer) func(transactionId uuid.U
 					}
 				}
 				if minIdx != i {
-					err = p.Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[minIdx].Slot(), positiveSlotAssets[i].Slot())
+					err = p.WithTransaction(tx).Move(mb)(transactionId, characterId, inventoryType, positiveSlotAssets[minIdx].Slot(), positiveSlotAssets[i].Slot())
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to move assets [%d] and [%d] in compartment [%s].", positiveSlotAssets[i].Id(), positiveSlotAssets[minIdx].Id(), c.Id())
 						return err
 					}
-					c, err = p.GetByCharacterAndType(characterId)(inventoryType)
+					c, err = p.WithTransaction(tx).GetByCharacterAndType(characterId)(inventoryType)
 					if err != nil {
 						p.l.WithError(err).Errorf("Unable to get compartment by type [%d] for character [%d].", inventoryType, characterId)
+						return err
 					}
 					as = c.Assets()
 
@@ -1414,7 +2249,8 @@ func (p *Processor) CompactAndSort(mb *message.Buffer) func(transactionId uuid.U
 				}
 			}
 
-			return nil
+			// Emit the status event for successful completion
+			return mb.Put(compartment.EnvEventTopicStatus, SortCompleteEventStatusProvider(transactionId, c.Id(), characterId, inventoryType))
 		})
 
 		if txErr != nil {
@@ -1422,13 +2258,6 @@ func (p *Processor) CompactAndSort(mb *message.Buffer) func(transactionId uuid.U
 			return txErr
 		}
 
-		// Emit the status event for successful completion
-		err := mb.Put(compartment.EnvEventTopicStatus, SortCompleteEventStatusProvider(transactionId, compartmentId, characterId, inventoryType))
-		if err != nil {
-			p.l.WithError(err).Errorf("Unable to emit compact and sort complete event for character [%d], inventory [%d].", characterId, inventoryType)
-			return err
-		}
-
 		p.l.Debugf("Character [%d] successfully compacted and sorted assets in inventory [%d].", characterId, inventoryType)
 		return nil
 	}
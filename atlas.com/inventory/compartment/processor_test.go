@@ -3,35 +3,46 @@ package compartment_test
 import (
 	"atlas-inventory/asset"
 	"atlas-inventory/compartment"
+	"atlas-inventory/configuration"
 	"atlas-inventory/data/consumable"
 	dcp "atlas-inventory/data/consumable/mock"
+	"atlas-inventory/database"
 	"atlas-inventory/kafka/message"
-	"atlas-inventory/stackable"
+	asset2 "atlas-inventory/kafka/message/asset"
+	compartment2 "atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/ledger"
+	"atlas-inventory/outbox"
+	"atlas-inventory/reservation"
+	"atlas-inventory/schema"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/Chronicle20/atlas-constants/inventory"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-model/model"
 	tenant "github.com/Chronicle20/atlas-tenant"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 )
 
 func testDatabase(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
-
-	var migrators []func(db *gorm.DB) error
-	migrators = append(migrators, stackable.Migration, asset.Migration, compartment.Migration)
-
-	for _, migrator := range migrators {
-		if err := migrator(db); err != nil {
-			t.Fatalf("Failed to migrate database: %v", err)
-		}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
 	}
 	return db
 }
@@ -360,3 +371,1252 @@ func TestMergeAndCompactGood(t *testing.T) {
 		}
 	}
 }
+
+// TestCompactAndSortRollsBack verifies that a sort failing part way leaves nothing behind: the moves made before the
+// failure are undone, none of their events are staged and the command is not recorded as processed, so a redelivery
+// applies it in full.
+func TestCompactAndSortRollsBack(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	processor := func(ctx context.Context) *compartment.Processor {
+		ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+		return compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+	}
+
+	mb := message.NewBuffer()
+	c, err := processor(ctx).Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	for _, templateId := range []uint32{2120000, 2000000, 2070000} {
+		err = processor(ctx).CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, templateId, 1, time.Time{}, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to create asset: %v", err)
+		}
+	}
+	ap := asset.NewProcessor(l, ctx, db)
+	gap, err := ap.GetBySlot(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = db.Delete(&asset.Entity{}, gap.Id()).Error; err != nil {
+		t.Fatalf("Failed to delete asset: %v", err)
+	}
+
+	// Compacting moves [2070000] from slot 3 into the empty slot 2. Sorting then swaps it with [2120000] in slot 1,
+	// which fails as [2120000] is parked in the temporary slot.
+	trigger := fmt.Sprintf(`CREATE TRIGGER fail_sort BEFORE UPDATE OF slot ON assets
+		WHEN NEW.compartment_id = '%s' AND NEW.slot = %d
+		BEGIN SELECT RAISE(ABORT, 'injected failure'); END`, c.Id().String(), math.MinInt16)
+	if err = db.Exec(trigger).Error; err != nil {
+		t.Fatalf("Failed to create trigger: %v", err)
+	}
+	t.Cleanup(func() { db.Exec("DROP TRIGGER IF EXISTS fail_sort") })
+	before, err := outbox.NewProcessor(l, ctx, db).PendingProvider(1000)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+
+	transactionId := uuid.New()
+	cctx := ledger.WithCommand(ctx, "SORT", transactionId)
+	err = processor(cctx).CompactAndSortAndEmit(transactionId, characterId, inventory.TypeValueUse)
+	if err == nil {
+		t.Fatalf("Expected the sort to fail.")
+	}
+	a, err := ap.GetBySlot(c.Id(), 3)
+	if err != nil || a.TemplateId() != 2070000 {
+		t.Fatalf("Expected the compaction to be rolled back, leaving [2070000] in slot [3].")
+	}
+	after, err := outbox.NewProcessor(l, ctx, db).PendingProvider(1000)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("Expected nothing to be staged for a failed sort, got [%d] new messages.", len(after)-len(before))
+	}
+
+	if err = db.Exec("DROP TRIGGER fail_sort").Error; err != nil {
+		t.Fatalf("Failed to drop trigger: %v", err)
+	}
+	err = processor(cctx).CompactAndSortAndEmit(transactionId, characterId, inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Expected the redelivered sort to be applied, got [%v].", err)
+	}
+	c, err = processor(ctx).GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	want := map[int16]uint32{1: 2070000, 2: 2120000}
+	if len(c.Assets()) != len(want) {
+		t.Fatalf("Expected [%d] assets, got [%d].", len(want), len(c.Assets()))
+	}
+	for _, a := range c.Assets() {
+		if want[a.Slot()] != a.TemplateId() {
+			t.Fatalf("Expected [%d] in slot [%d], got [%d].", want[a.Slot()], a.Slot(), a.TemplateId())
+		}
+	}
+}
+
+// TestExpireReservations tests the behavior of the ExpireReservations function
+// This test verifies that lapsed reservations no longer hold quantity in their slot
+func TestExpireReservations(t *testing.T) {
+	// Create a character ID
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	var err error
+	_, err = cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 1: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 5}}
+	err = cp.RequestReserve(mb)(transactionId, characterId, requests, 0)
+	if err != nil {
+		t.Fatalf("Failed to reserve asset: %v", err)
+	}
+
+	rp := reservation.NewProcessor(l, ctx, db)
+	reserved, err := rp.GetReservedQuantity(characterId, inventory.TypeValueUse, 1)
+	if err != nil {
+		t.Fatalf("Failed to get reserved quantity: %v", err)
+	}
+	if reserved != 5 {
+		t.Fatalf("Expected [5] reserved, got [%d]", reserved)
+	}
+
+	err = cp.ExpireReservations(mb)(time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to expire reservations: %v", err)
+	}
+
+	// The requester is told the hold lapsed
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	var expired []compartment2.StatusEvent[compartment2.ReservationExpiredEventBody]
+	for _, m := range ms {
+		var e compartment2.StatusEvent[compartment2.ReservationExpiredEventBody]
+		if err = json.Unmarshal(m.Value(), &e); err != nil {
+			t.Fatalf("Failed to decode event: %v", err)
+		}
+		if e.Type == compartment2.StatusEventTypeReservationExpired && e.TransactionId == transactionId {
+			expired = append(expired, e)
+		}
+	}
+	if len(expired) != 1 {
+		t.Fatalf("Expected a single RESERVATION_EXPIRED event, got [%d].", len(expired))
+	}
+	e := expired[0]
+	if e.TransactionId != transactionId || e.CharacterId != characterId || e.CompartmentId != c.Id() || e.Body.ItemId != 2120000 || e.Body.Slot != 1 || e.Body.Quantity != 5 {
+		t.Fatalf("Unexpected RESERVATION_EXPIRED event [%+v].", e)
+	}
+
+	// The lapsed hold was released, so nothing remains reserved in the slot
+	reserved, err = rp.GetReservedQuantity(characterId, inventory.TypeValueUse, 1)
+	if err != nil {
+		t.Fatalf("Failed to get reserved quantity: %v", err)
+	}
+	if reserved != 0 {
+		t.Fatalf("Expected [0] reserved after expiry, got [%d]", reserved)
+	}
+}
+
+// TestRequestReserveAllOrNothing tests the behavior of the RequestReserve function
+// This test verifies that a failing item leaves no holds on the items requested before it
+func TestRequestReserveAllOrNothing(t *testing.T) {
+	// Create a character ID
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	var err error
+	_, err = cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 1: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2070000, 1, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 2: %v", err)
+	}
+
+	// The second item asks for more than what is owned, so neither should be reserved
+	requests := []compartment.ReservationRequest{
+		{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 5},
+		{InventoryType: inventory.TypeValueUse, Slot: 2, ItemId: 2070000, Quantity: 2},
+	}
+	err = cp.RequestReserve(mb)(uuid.New(), characterId, requests, 0)
+	var ce compartment.Error
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonInsufficientQuantity || ce.Slot != 2 {
+		t.Fatalf("Expected the reservation to fail with [%s] in slot 2, got [%v].", compartment2.ErrorReasonInsufficientQuantity, err)
+	}
+
+	rp := reservation.NewProcessor(l, ctx, db)
+	for _, slot := range []int16{1, 2} {
+		reserved, err := rp.GetReservedQuantity(characterId, inventory.TypeValueUse, slot)
+		if err != nil {
+			t.Fatalf("Failed to get reserved quantity: %v", err)
+		}
+		if reserved != 0 {
+			t.Fatalf("Expected nothing reserved in slot [%d], got [%d]", slot, reserved)
+		}
+	}
+}
+
+// TestRenewReservation tests the behavior of the RenewReservation function
+// This test verifies that a hold is extended, but never beyond the tenant maximum
+func TestRenewReservation(t *testing.T) {
+	// Create a character ID
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	var err error
+	_, err = cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 1: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 1}}
+	err = cp.RequestReserve(mb)(transactionId, characterId, requests, time.Second*10)
+	if err != nil {
+		t.Fatalf("Failed to reserve asset: %v", err)
+	}
+
+	// Ask for far longer than the default tenant maximum allows
+	err = cp.RenewReservation(mb)(transactionId, characterId, time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to renew reservation: %v", err)
+	}
+
+	rs, err := reservation.NewProcessor(l, ctx, db).ActiveBySlotProvider(characterId, inventory.TypeValueUse, 1)()
+	if err != nil {
+		t.Fatalf("Failed to get reservations: %v", err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("Expected [1] reservation, got [%d]", len(rs))
+	}
+	if rs[0].Expiry().Before(time.Now().Add(time.Minute)) {
+		t.Fatalf("Reservation was not renewed")
+	}
+	if rs[0].Expiry().After(time.Now().Add(time.Minute * 5)) {
+		t.Fatalf("Reservation was renewed beyond the tenant maximum")
+	}
+}
+
+// TestRenewReservationIsBoundedFromCreation verifies that renewing a hold never keeps it for longer than the tenant
+// maximum after it was made, and that renewing a transaction holding nothing fails without emitting an event itself.
+func TestRenewReservationIsBoundedFromCreation(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		return consumable.Extract(consumable.RestModel{SlotMax: 100})
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 1}}
+	err = cp.RequestReserve(mb)(transactionId, characterId, requests, time.Second*10)
+	if err != nil {
+		t.Fatalf("Failed to reserve asset: %v", err)
+	}
+
+	// The hold was made four minutes ago, so the five minute default maximum leaves a minute.
+	createdAt := time.Now().Add(-time.Minute * 4)
+	err = db.Model(&reservation.Entity{}).Where("transaction_id = ?", transactionId).Update("created_at", createdAt).Error
+	if err != nil {
+		t.Fatalf("Failed to backdate reservation: %v", err)
+	}
+	err = cp.RenewReservation(mb)(transactionId, characterId, time.Minute*5)
+	if err != nil {
+		t.Fatalf("Failed to renew reservation: %v", err)
+	}
+	rs, err := reservation.NewProcessor(l, ctx, db).ActiveBySlotProvider(characterId, inventory.TypeValueUse, 1)()
+	if err != nil {
+		t.Fatalf("Failed to get reservations: %v", err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("Expected [1] reservation, got [%d]", len(rs))
+	}
+	if rs[0].Expiry().After(createdAt.Add(time.Minute * 5)) {
+		t.Fatalf("Reservation was renewed to [%s], beyond the tenant maximum from when it was made.", rs[0].Expiry())
+	}
+
+	_ = mb.Take()
+	err = cp.RenewReservation(mb)(uuid.New(), characterId, time.Minute)
+	var ce compartment.Error
+	if !errors.As(err, &ce) || ce.Reason != compartment2.ErrorReasonNotReserved {
+		t.Fatalf("Expected renewing nothing to fail with [%s], got [%v].", compartment2.ErrorReasonNotReserved, err)
+	}
+	if ms := mb.GetAll(); len(ms) != 0 {
+		t.Fatalf("Expected no events from a failed renewal, got [%v].", ms)
+	}
+}
+
+// TestCancelReservations tests the behavior of the CancelReservations function
+// This test verifies that reserved quantities are reported on assets and released by transaction
+func TestCancelReservations(t *testing.T) {
+	// Create a character ID
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	var err error
+	_, err = cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 1: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 2}}
+	err = cp.RequestReserve(mb)(transactionId, characterId, requests, 0)
+	if err != nil {
+		t.Fatalf("Failed to reserve asset: %v", err)
+	}
+
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != 1 || c.Assets()[0].Reserved() != 2 {
+		t.Fatalf("Expected asset to report [2] reserved")
+	}
+
+	rs, err := cp.CancelReservations(mb)(transactionId, characterId)
+	if err != nil {
+		t.Fatalf("Failed to cancel reservations: %v", err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("Expected [1] cancelled reservation, got [%d]", len(rs))
+	}
+
+	c, err = cp.GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if c.Assets()[0].Reserved() != 0 {
+		t.Fatalf("Expected asset to report nothing reserved after cancellation")
+	}
+}
+
+// TestDuplicateCommandIsNotReapplied verifies a redelivered command re-emits the events of its first execution
+// instead of applying the change again.
+func TestDuplicateCommandIsNotReapplied(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	processor := func(ctx context.Context) *compartment.Processor {
+		ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+		return compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+	}
+
+	_, err := processor(ctx).Create(message.NewBuffer())(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+
+	transactionId := uuid.New()
+	for i := 0; i < 2; i++ {
+		cctx := ledger.WithCommand(ctx, "CREATE_ASSET", transactionId)
+		err = processor(cctx).CreateAssetAndEmit(transactionId, characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+		if i == 0 && err != nil {
+			t.Fatalf("Failed to create asset: %v", err)
+		}
+		if i == 1 && !errors.Is(err, ledger.ErrDuplicate) {
+			t.Fatalf("Expected redelivery to be reported as a duplicate, got [%v].", err)
+		}
+	}
+
+	c, err := processor(ctx).GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != 1 || c.Assets()[0].Quantity() != 5 {
+		t.Fatalf("Expected a single asset of quantity [5], the command was applied more than once")
+	}
+
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	// The compartment CREATED event, then the asset events of the first execution followed by their re-emission.
+	if len(ms) < 3 || (len(ms)-1)%2 != 0 {
+		t.Fatalf("Expected the original events to be re-emitted, got [%d] pending messages", len(ms))
+	}
+	for i := 1; i <= (len(ms)-1)/2; i++ {
+		if string(ms[i].Value()) != string(ms[i+(len(ms)-1)/2].Value()) {
+			t.Fatalf("Expected re-emitted message [%d] to match the original", i)
+		}
+	}
+}
+
+// TestRenewReservationRepeatedly verifies that each renewal under a reservation's transaction extends the hold again,
+// as renewals are repeated for as long as the hold is wanted and are not recorded in the ledger.
+func TestRenewReservationRepeatedly(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	mb := message.NewBuffer()
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 1}}
+	err = cp.RequestReserve(mb)(transactionId, characterId, requests, time.Second*10)
+	if err != nil {
+		t.Fatalf("Failed to reserve asset: %v", err)
+	}
+
+	expiry := func() time.Time {
+		rs, err := reservation.NewProcessor(l, ctx, db).ActiveBySlotProvider(characterId, inventory.TypeValueUse, 1)()
+		if err != nil || len(rs) != 1 {
+			t.Fatalf("Expected [1] reservation, got [%d]: %v", len(rs), err)
+		}
+		return rs[0].Expiry()
+	}
+	previous := expiry()
+	for _, ttl := range []time.Duration{time.Second * 30, time.Second * 90} {
+		err = cp.RenewReservationAndEmit(transactionId, characterId, ttl)
+		if err != nil {
+			t.Fatalf("Failed to renew reservation: %v", err)
+		}
+		current := expiry()
+		if !current.After(previous) {
+			t.Fatalf("Expected renewing for [%s] to extend the hold past [%s], got [%s].", ttl, previous, current)
+		}
+		previous = current
+	}
+}
+
+// TestCommandsSharingATransaction verifies that commands of one type under one transaction are each applied when they
+// differ in character or body, while a redelivery of one of them is still a duplicate.
+func TestCommandsSharingATransaction(t *testing.T) {
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	processor := func(ctx context.Context) *compartment.Processor {
+		ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+		return compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+	}
+
+	buyer := uint32(1)
+	seller := uint32(2)
+	for _, characterId := range []uint32{buyer, seller} {
+		_, err := processor(ctx).Create(message.NewBuffer())(uuid.New(), characterId, inventory.TypeValueUse, 40)
+		if err != nil {
+			t.Fatalf("Failed to create compartment: %v", err)
+		}
+	}
+
+	transactionId := uuid.New()
+	create := func(body compartment2.CreateAssetCommandBody) error {
+		cctx := ledger.WithSubjectCommand(ctx, "CREATE_ASSET", transactionId, ledger.Subject(buyer, inventory.TypeValueUse, body))
+		return processor(cctx).CreateAssetAndEmit(transactionId, buyer, inventory.TypeValueUse, body.TemplateId, body.Quantity, body.Expiration, body.OwnerId, body.Flag, body.Rechargeable)
+	}
+	awards := []compartment2.CreateAssetCommandBody{{TemplateId: 2120000, Quantity: 5}, {TemplateId: 2070000, Quantity: 1, Rechargeable: 1}}
+	for _, award := range awards {
+		if err := create(award); err != nil {
+			t.Fatalf("Failed to award [%d]: %v", award.TemplateId, err)
+		}
+	}
+	if err := create(awards[0]); !errors.Is(err, ledger.ErrDuplicate) {
+		t.Fatalf("Expected redelivery of the first award to be reported as a duplicate, got [%v].", err)
+	}
+	c, err := processor(ctx).GetByCharacterAndType(buyer)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != len(awards) {
+		t.Fatalf("Expected each award to be applied once, got [%d] assets.", len(c.Assets()))
+	}
+
+	// Each side of a trade reserves what it offers under the trade's transaction.
+	err = processor(ctx).CreateAsset(message.NewBuffer())(uuid.New(), seller, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 1}}
+	for _, characterId := range []uint32{seller, buyer} {
+		cctx := ledger.WithSubjectCommand(ctx, "REQUEST_RESERVE", transactionId, ledger.Subject(characterId, inventory.TypeValueUse, requests))
+		err = processor(cctx).RequestReserveAndEmit(transactionId, characterId, requests, 0)
+		if err != nil {
+			t.Fatalf("Failed to reserve for character [%d]: %v", characterId, err)
+		}
+		rs, err := reservation.NewProcessor(l, ctx, db).ActiveBySlotProvider(characterId, inventory.TypeValueUse, 1)()
+		if err != nil || len(rs) != 1 {
+			t.Fatalf("Expected character [%d] to hold [1] reservation, got [%d]: %v", characterId, len(rs), err)
+		}
+	}
+}
+
+// TestConsumeReservedSlotsOfOneTransaction verifies consuming each slot of a multi-slot reservation is applied, as
+// every slot is its own command under the shared transaction, while a redelivery of one slot is still a duplicate.
+func TestConsumeReservedSlotsOfOneTransaction(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	processor := func(ctx context.Context) *compartment.Processor {
+		ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+		return compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+	}
+
+	mb := message.NewBuffer()
+	cp := processor(ctx)
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 1: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2070000, 3, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset 2: %v", err)
+	}
+
+	transactionId := uuid.New()
+	requests := []compartment.ReservationRequest{
+		{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 2},
+		{InventoryType: inventory.TypeValueUse, Slot: 2, ItemId: 2070000, Quantity: 1},
+	}
+	err = processor(ledger.WithSubjectCommand(ctx, "REQUEST_RESERVE", transactionId, ledger.Subject(characterId, inventory.TypeValueUse, requests))).RequestReserve(mb)(transactionId, characterId, requests, 0)
+	if err != nil {
+		t.Fatalf("Failed to request reservation: %v", err)
+	}
+
+	consume := func(slot int16) error {
+		body := compartment2.ConsumeCommandBody{TransactionId: transactionId, Slot: slot}
+		cctx := ledger.WithSubjectCommand(ctx, "CONSUME", transactionId, ledger.Subject(characterId, inventory.TypeValueUse, body))
+		return processor(cctx).ConsumeAssetAndEmit(transactionId, characterId, inventory.TypeValueUse, slot)
+	}
+	for _, slot := range []int16{1, 2} {
+		if err = consume(slot); err != nil {
+			t.Fatalf("Failed to consume slot [%d]: %v", slot, err)
+		}
+	}
+	if err = consume(1); !errors.Is(err, ledger.ErrDuplicate) {
+		t.Fatalf("Expected redelivery of slot [1] to be reported as a duplicate, got [%v].", err)
+	}
+
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	expected := map[int16]uint32{1: 3, 2: 2}
+	for _, a := range c.Assets() {
+		if a.Quantity() != expected[a.Slot()] {
+			t.Fatalf("Expected slot [%d] to hold [%d], got [%d].", a.Slot(), expected[a.Slot()], a.Quantity())
+		}
+	}
+}
+
+// TestDropFailureIsClassified verifies a failed command reports why it failed, and that the failure is published as an
+// ERROR status event carrying the command's error code.
+func TestDropFailureIsClassified(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		m, err := consumable.Extract(rm)
+		if err != nil {
+			return consumable.Model{}, err
+		}
+		return m, nil
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 40)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	requests := []compartment.ReservationRequest{{InventoryType: inventory.TypeValueUse, Slot: 1, ItemId: 2120000, Quantity: 3}}
+	err = cp.RequestReserve(mb)(uuid.New(), characterId, requests, 0)
+	if err != nil {
+		t.Fatalf("Failed to request reservation: %v", err)
+	}
+
+	tests := []struct {
+		slot     int16
+		quantity int16
+		reason   string
+	}{
+		{1, 0, compartment2.ErrorReasonInvalidQuantity},
+		{2, 1, compartment2.ErrorReasonSlotEmpty},
+		{1, 4, compartment2.ErrorReasonReserved},
+		{1, 6, compartment2.ErrorReasonInsufficientQuantity},
+	}
+	var dropErr error
+	for _, tt := range tests {
+		dropErr = cp.Drop(mb)(uuid.New(), characterId, inventory.TypeValueUse, _map.Model{}, 0, 0, tt.slot, tt.quantity)
+		var ce compartment.Error
+		if !errors.As(dropErr, &ce) || ce.Reason != tt.reason || ce.Slot != tt.slot {
+			t.Fatalf("Expected dropping [%d] from slot [%d] to fail with [%s], got [%v].", tt.quantity, tt.slot, tt.reason, dropErr)
+		}
+	}
+
+	op := outbox.NewProcessor(l, ctx, db)
+	before, err := op.PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	transactionId := uuid.New()
+	err = cp.ReportFailureAndEmit(transactionId, characterId, compartment2.DropCommandFailed, dropErr)
+	if err != nil {
+		t.Fatalf("Failed to report failure: %v", err)
+	}
+	ms, err := op.PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	if len(ms) != len(before)+1 {
+		t.Fatalf("Expected a single ERROR event to be staged, got [%d].", len(ms)-len(before))
+	}
+	var e compartment2.StatusEvent[compartment2.ErrorEventBody]
+	if err = json.Unmarshal(ms[len(ms)-1].Value(), &e); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if e.Type != compartment2.StatusEventTypeError || e.TransactionId != transactionId || e.Body.ErrorCode != compartment2.DropCommandFailed || e.Body.Reason != compartment2.ErrorReasonInsufficientQuantity || e.Body.Slot != 1 || e.CompartmentId != c.Id() {
+		t.Fatalf("Unexpected ERROR event [%+v].", e)
+	}
+}
+
+// TestAssetExpiryTask verifies the expiry worker leaves assets alone until the clock passes their expiration, then
+// deletes them with the EXPIRED reason.
+func TestAssetExpiryTask(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	now := time.Now()
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4000000, 1, now.Add(time.Hour), 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	clock := now
+	task := compartment.NewAssetExpiryTask(l, context.Background(), db, model.FixedProvider([]tenant.Model{te}), time.Minute, func() time.Time { return clock })
+
+	task.Run()
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueETC)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != 1 {
+		t.Fatalf("Expected the asset to survive before its expiration.")
+	}
+
+	clock = now.Add(2 * time.Hour)
+	task.Run()
+	c, err = cp.GetByCharacterAndType(characterId)(inventory.TypeValueETC)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != 0 {
+		t.Fatalf("Expected the asset to be deleted after its expiration.")
+	}
+
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	var e asset2.StatusEvent[asset2.DeletedStatusEventBody]
+	if err = json.Unmarshal(ms[len(ms)-1].Value(), &e); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if e.Type != asset2.StatusEventTypeDeleted || e.Body.Reason != asset2.DeletedReasonExpired || e.TemplateId != 4000000 {
+		t.Fatalf("Unexpected event [%+v].", e)
+	}
+}
+
+// TestExpireAssetsReplaces verifies an expired item whose data names a replacement is converted in place.
+func TestExpireAssetsReplaces(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+
+	dcpi := &dcp.ProcessorImpl{}
+	dcpi.GetByIdFn = func(itemId uint32) (consumable.Model, error) {
+		rm := consumable.RestModel{SlotMax: 100}
+		if itemId == 2120000 {
+			rm.ReplaceItemId = 2120001
+		}
+		return consumable.Extract(rm)
+	}
+
+	ap := asset.NewProcessor(l, ctx, db).WithConsumableProcessor(dcpi)
+	cp := compartment.NewProcessor(l, ctx, db).WithAssetProcessor(ap)
+
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueUse, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	now := time.Now()
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueUse, 2120000, 5, now.Add(-time.Minute), 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	err = cp.ExpireAssets(mb)(now)
+	if err != nil {
+		t.Fatalf("Failed to expire assets: %v", err)
+	}
+
+	c, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueUse)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(c.Assets()) != 1 {
+		t.Fatalf("Expected the expired asset to be replaced, got [%d] assets.", len(c.Assets()))
+	}
+	a := c.Assets()[0]
+	if a.TemplateId() != 2120001 || a.Slot() != 1 || a.Quantity() != 5 || !a.Expiration().IsZero() {
+		t.Fatalf("Unexpected replacement [%d] in slot [%d] of quantity [%d].", a.TemplateId(), a.Slot(), a.Quantity())
+	}
+}
+
+// TestExpireAssetsWithoutReference verifies an expired asset held by another service is removed without resolving
+// its reference, so that service is never asked for it under the lock.
+func TestExpireAssetsWithoutReference(t *testing.T) {
+	characterId := uint32(1)
+
+	var lookups atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lookups.Add(1)
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("CASHSHOP_SERVICE_URL", srv.URL+"/")
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueCash, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	now := time.Now()
+	e := asset.Entity{TenantId: te.Id(), CompartmentId: c.Id(), Slot: 1, TemplateId: 5040000, Expiration: now.Add(-time.Minute), ReferenceId: 7, ReferenceType: string(asset.ReferenceTypeCash)}
+	if err = db.Create(&e).Error; err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	err = cp.ExpireAssets(mb)(now)
+	if err != nil {
+		t.Fatalf("Failed to expire assets: %v", err)
+	}
+	as, err := asset.NewProcessor(l, ctx, db).UndecoratedByCompartmentIdProvider(c.Id())()
+	if err != nil {
+		t.Fatalf("Failed to get assets: %v", err)
+	}
+	if len(as) != 0 {
+		t.Fatalf("Expected the expired asset to be removed, got [%d] assets.", len(as))
+	}
+	if lookups.Load() != 0 {
+		t.Fatalf("Expected the reference not to be looked up, got [%d] lookups.", lookups.Load())
+	}
+}
+
+// TestExpiringSoonWarnings verifies each of the tenant's thresholds is announced once per asset, including across a
+// fresh worker.
+func TestExpiringSoonWarnings(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	configuration.Set(configuration.File{Tenants: map[string]configuration.TenantConfig{
+		te.Id().String(): {Expiry: configuration.ExpiryConfig{WarningThresholds: []configuration.Duration{configuration.Duration(time.Hour), configuration.Duration(time.Hour * 24)}}},
+	}})
+	t.Cleanup(func() { configuration.Set(configuration.File{Tenants: make(map[string]configuration.TenantConfig)}) })
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	_, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	now := time.Now()
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4000000, 1, now.Add(time.Hour*30), 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+
+	warnings := func() []int64 {
+		ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(1000)()
+		if err != nil {
+			t.Fatalf("Failed to get pending messages: %v", err)
+		}
+		var results []int64
+		for _, m := range ms {
+			var e asset2.StatusEvent[asset2.ExpiringSoonStatusEventBody]
+			if err = json.Unmarshal(m.Value(), &e); err != nil {
+				continue
+			}
+			if e.Type == asset2.StatusEventTypeExpiringSoon {
+				results = append(results, e.Body.Threshold)
+			}
+		}
+		return results
+	}
+
+	clock := now
+	run := func() {
+		compartment.NewAssetExpiryTask(l, context.Background(), db, model.FixedProvider([]tenant.Model{te}), time.Minute, func() time.Time { return clock }).Run()
+	}
+
+	run()
+	if ws := warnings(); len(ws) != 0 {
+		t.Fatalf("Expected no warnings 30 hours out, got [%v].", ws)
+	}
+
+	clock = now.Add(time.Hour * 7)
+	run()
+	run()
+	if ws := warnings(); len(ws) != 1 || ws[0] != int64((time.Hour*24).Seconds()) {
+		t.Fatalf("Expected a single 24 hour warning, got [%v].", ws)
+	}
+
+	clock = now.Add(time.Hour*29 + time.Minute*30)
+	run()
+	run()
+	if ws := warnings(); len(ws) != 2 || ws[1] != int64(time.Hour.Seconds()) {
+		t.Fatalf("Expected a single 1 hour warning to follow, got [%v].", ws)
+	}
+}
+
+// TestCheckConsistency verifies stuck and out of range assets are reported, and a fixing run moves them into the
+// lowest free slots and announces each move.
+func TestCheckConsistency(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	for _, templateId := range []uint32{4000000, 4000001, 4000002} {
+		err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, templateId, 1, time.Time{}, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to create asset: %v", err)
+		}
+	}
+	ap := asset.NewProcessor(l, ctx, db)
+	stuck, err := ap.GetBySlot(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	beyond, err := ap.GetBySlot(c.Id(), 3)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = db.Model(&asset.Entity{}).Where("id = ?", stuck.Id()).Update("slot", math.MinInt16).Error; err != nil {
+		t.Fatalf("Failed to move asset: %v", err)
+	}
+	if err = db.Model(&asset.Entity{}).Where("id = ?", beyond.Id()).Update("slot", 10).Error; err != nil {
+		t.Fatalf("Failed to move asset: %v", err)
+	}
+
+	vs, err := cp.CheckConsistency(mb)(characterId, false)
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(vs) != 2 || vs[0].Kind() != compartment.ViolationTemporarySlot || vs[1].Kind() != compartment.ViolationBeyondCapacity || vs[0].Fixed() || vs[1].Fixed() {
+		t.Fatalf("Expected an unfixed temporary slot and capacity violation, got [%+v].", vs)
+	}
+
+	vs, err = cp.CheckConsistency(mb)(characterId, true)
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(vs) != 2 || !vs[0].Fixed() || vs[0].NewSlot() != 2 || !vs[1].Fixed() || vs[1].NewSlot() != 3 {
+		t.Fatalf("Expected both violations to be fixed into slots 2 and 3, got [%+v].", vs)
+	}
+	cm, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueETC)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	slots := make(map[int16]uint32)
+	for _, a := range cm.Assets() {
+		slots[a.Slot()] = a.TemplateId()
+	}
+	if slots[1] != 4000000 || slots[2] != 4000001 || slots[3] != 4000002 {
+		t.Fatalf("Unexpected slots after fixing [%+v].", slots)
+	}
+
+	ms, err := outbox.NewProcessor(l, ctx, db).PendingProvider(100)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	var e asset2.StatusEvent[asset2.MovedStatusEventBody]
+	if err = json.Unmarshal(ms[len(ms)-1].Value(), &e); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if e.Type != asset2.StatusEventTypeMoved || e.Slot != 3 || e.Body.OldSlot != 10 {
+		t.Fatalf("Unexpected event [%+v].", e)
+	}
+
+	vs, err = cp.CheckConsistency(mb)(characterId, false)
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(vs) != 0 {
+		t.Fatalf("Expected no violations after fixing, got [%d].", len(vs))
+	}
+}
+
+// TestConstraintViolationsAreClassified verifies writes rejected by the database's unique constraints surface as
+// domain errors.
+func TestConstraintViolationsAreClassified(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	_, err = cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 24)
+	if reason := compartment.Classify(err).Reason; reason != compartment2.ErrorReasonCompartmentExists {
+		t.Fatalf("Expected [%s] creating a second compartment, got [%s]: %v", compartment2.ErrorReasonCompartmentExists, reason, err)
+	}
+
+	for _, templateId := range []uint32{4000000, 4000001, 4000002} {
+		err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, templateId, 1, time.Time{}, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to create asset: %v", err)
+		}
+	}
+	ap := asset.NewProcessor(l, ctx, db)
+	second, err := ap.GetBySlot(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = db.Model(&asset.Entity{}).Where("id = ?", second.Id()).Update("slot", 1).Error; err == nil {
+		t.Fatalf("Expected the database to reject a second asset in a slot.")
+	}
+	stuck, err := ap.GetBySlot(c.Id(), 3)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = db.Model(&asset.Entity{}).Where("id = ?", stuck.Id()).Update("slot", math.MinInt16).Error; err != nil {
+		t.Fatalf("Failed to move asset: %v", err)
+	}
+
+	err = cp.Move(mb)(uuid.New(), characterId, inventory.TypeValueETC, 1, 2)
+	if reason := compartment.Classify(err).Reason; reason != compartment2.ErrorReasonSlotOccupied {
+		t.Fatalf("Expected [%s] swapping through an occupied temporary slot, got [%s]: %v", compartment2.ErrorReasonSlotOccupied, reason, err)
+	}
+}
+
+// TestCheckConsistencyFindsSlotCollisions verifies assets sharing a slot, as data from before the unique slot index
+// may, are reported and a fixing run moves the younger one into the lowest free slot.
+func TestCheckConsistencyFindsSlotCollisions(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	if err := db.Migrator().DropIndex(&asset.Entity{}, "idx_assets_compartment_slot"); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Migrator().CreateIndex(&asset.Entity{}, "idx_assets_compartment_slot"); err != nil {
+			t.Fatalf("Failed to restore index: %v", err)
+		}
+	})
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	for _, templateId := range []uint32{4000000, 4000001} {
+		err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, templateId, 1, time.Time{}, 0, 0, 0)
+		if err != nil {
+			t.Fatalf("Failed to create asset: %v", err)
+		}
+	}
+	colliding, err := asset.NewProcessor(l, ctx, db).GetBySlot(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = db.Model(&asset.Entity{}).Where("id = ?", colliding.Id()).Update("slot", 1).Error; err != nil {
+		t.Fatalf("Failed to move asset: %v", err)
+	}
+
+	vs, err := cp.CheckConsistency(mb)(characterId, false)
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(vs) != 1 || vs[0].Kind() != compartment.ViolationSlotCollision || vs[0].Slot() != 1 || vs[0].Fixed() {
+		t.Fatalf("Expected an unfixed slot collision, got [%+v].", vs)
+	}
+
+	vs, err = cp.CheckConsistency(mb)(characterId, true)
+	if err != nil {
+		t.Fatalf("Failed to check consistency: %v", err)
+	}
+	if len(vs) != 1 || !vs[0].Fixed() || vs[0].NewSlot() != 2 {
+		t.Fatalf("Expected the collision to be fixed into slot 2, got [%+v].", vs)
+	}
+	cm, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueETC)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	slots := make(map[int16]uint32)
+	for _, a := range cm.Assets() {
+		slots[a.Slot()] = a.TemplateId()
+	}
+	if slots[1] != 4000000 || slots[2] != 4000001 {
+		t.Fatalf("Unexpected slots after fixing [%+v].", slots)
+	}
+}
@@ -2,11 +2,13 @@ package compartment
 
 import (
 	"atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/statistics"
 	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-kafka/producer"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
+	"time"
 )
 
 func CreatedEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, inventoryType inventory.Type, capacity uint32) model.Provider[[]kafka.Message] {
@@ -84,6 +86,62 @@ func ReservationCancelledEventStatusProvider(transactionId uuid.UUID, id uuid.UU
 	return producer.SingleMessageProvider(key, value)
 }
 
+func ReservationCompleteEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, reservationRequests []ReservationRequest) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	items := make([]compartment.ReservedItemBody, 0, len(reservationRequests))
+	for _, r := range reservationRequests {
+		items = append(items, compartment.ReservedItemBody{
+			InventoryType: byte(r.InventoryType),
+			ItemId:        r.ItemId,
+			Slot:          r.Slot,
+			Quantity:      uint32(r.Quantity),
+		})
+	}
+	value := &compartment.StatusEvent[compartment.ReservationCompleteEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: id,
+		Type:          compartment.StatusEventTypeReservationComplete,
+		Body: compartment.ReservationCompleteEventBody{
+			Items: items,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ReservationRenewedEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, itemId uint32, slot int16, quantity uint32, expiry time.Time) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.StatusEvent[compartment.ReservationRenewedEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: id,
+		Type:          compartment.StatusEventTypeReservationRenewed,
+		Body: compartment.ReservationRenewedEventBody{
+			ItemId:   itemId,
+			Slot:     slot,
+			Quantity: quantity,
+			Expiry:   expiry,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func ReservationExpiredEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, itemId uint32, slot int16, quantity uint32) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.StatusEvent[compartment.ReservationExpiredEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: id,
+		Type:          compartment.StatusEventTypeReservationExpired,
+		Body: compartment.ReservationExpiredEventBody{
+			ItemId:   itemId,
+			Slot:     slot,
+			Quantity: quantity,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
 func MergeCompleteEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, inventoryType inventory.Type) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &compartment.StatusEvent[compartment.MergeCompleteEventBody]{
@@ -140,7 +198,35 @@ func ReleasedEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characte
 	return producer.SingleMessageProvider(key, value)
 }
 
-func ErrorEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, errorCode string) model.Provider[[]kafka.Message] {
+func EquipmentStatsChangedEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, t statistics.Totals) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.StatusEvent[compartment.EquipmentStatsChangedEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: id,
+		Type:          compartment.StatusEventTypeStatsChanged,
+		Body: compartment.EquipmentStatsChangedEventBody{
+			Strength:      t.Strength,
+			Dexterity:     t.Dexterity,
+			Intelligence:  t.Intelligence,
+			Luck:          t.Luck,
+			HP:            t.HP,
+			MP:            t.MP,
+			WeaponAttack:  t.WeaponAttack,
+			MagicAttack:   t.MagicAttack,
+			WeaponDefense: t.WeaponDefense,
+			MagicDefense:  t.MagicDefense,
+			Accuracy:      t.Accuracy,
+			Avoidability:  t.Avoidability,
+			Speed:         t.Speed,
+			Jump:          t.Jump,
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+// ErrorEventStatusProvider reports a failed command, classified by the reason and slot carried in cause.
+func ErrorEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, errorCode string, cause Error) model.Provider[[]kafka.Message] {
 	key := producer.CreateKey(int(characterId))
 	value := &compartment.StatusEvent[compartment.ErrorEventBody]{
 		TransactionId: transactionId,
@@ -150,7 +236,40 @@ func ErrorEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId
 		Body: compartment.ErrorEventBody{
 			ErrorCode:     errorCode,
 			TransactionId: transactionId, // TODO this needs removal from dependent services
+			InventoryType: byte(cause.InventoryType),
+			Slot:          cause.Slot,
+			Reason:        cause.Reason,
 		},
 	}
 	return producer.SingleMessageProvider(key, value)
 }
+
+func LoadoutAppliedEventStatusProvider(transactionId uuid.UUID, id uuid.UUID, characterId uint32, loadoutId uuid.UUID, name string, r LoadoutResult) model.Provider[[]kafka.Message] {
+	key := producer.CreateKey(int(characterId))
+	value := &compartment.StatusEvent[compartment.LoadoutAppliedEventBody]{
+		TransactionId: transactionId,
+		CharacterId:   characterId,
+		CompartmentId: id,
+		Type:          compartment.StatusEventTypeLoadoutApplied,
+		Body: compartment.LoadoutAppliedEventBody{
+			LoadoutId:  loadoutId,
+			Name:       name,
+			Equipped:   loadoutItemBodies(r.Equipped),
+			Unequipped: loadoutItemBodies(r.Unequipped),
+			Skipped:    loadoutItemBodies(r.Skipped),
+		},
+	}
+	return producer.SingleMessageProvider(key, value)
+}
+
+func loadoutItemBodies(is []LoadoutItem) []compartment.LoadoutItemBody {
+	results := make([]compartment.LoadoutItemBody, 0, len(is))
+	for _, i := range is {
+		results = append(results, compartment.LoadoutItemBody{
+			AssetId:    i.AssetId,
+			TemplateId: i.TemplateId,
+			Slot:       i.Slot,
+		})
+	}
+	return results
+}
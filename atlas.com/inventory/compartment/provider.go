@@ -15,6 +15,12 @@ func getById(tenantId uuid.UUID, id uuid.UUID) database.EntityProvider[Entity] {
 	}
 }
 
+func getAll(tenantId uuid.UUID) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db, &Entity{TenantId: tenantId})
+	}
+}
+
 func getByCharacter(tenantId uuid.UUID, characterId uint32) database.EntityProvider[[]Entity] {
 	return func(db *gorm.DB) model.Provider[[]Entity] {
 		return database.SliceQuery[Entity](db, &Entity{TenantId: tenantId, CharacterId: characterId})
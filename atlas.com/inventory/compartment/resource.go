@@ -1,9 +1,17 @@
 package compartment
 
 import (
+	"atlas-inventory/asset"
+	"atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/ledger"
+	"atlas-inventory/reservation"
 	"atlas-inventory/rest"
+	"atlas-inventory/statistics"
 	"errors"
+	"github.com/Chronicle20/atlas-constants/channel"
 	"github.com/Chronicle20/atlas-constants/inventory"
+	_map "github.com/Chronicle20/atlas-constants/map"
+	"github.com/Chronicle20/atlas-constants/world"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/server"
 	"github.com/google/uuid"
@@ -21,11 +29,73 @@ func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteIn
 			registerGet := rest.RegisterHandler(l)(si)
 			r := router.PathPrefix("/characters/{characterId}/inventory/compartments").Subrouter()
 			r.HandleFunc("/{compartmentId}", registerGet("get_compartment", handleGetCompartment(db))).Methods(http.MethodGet)
+			r.HandleFunc("/{compartmentId}/assets", registerGet("get_assets", handleGetAssets(db))).Methods(http.MethodGet)
 			r.HandleFunc("", registerGet("get_compartment_by_type", handleGetCompartmentByType(db))).Methods(http.MethodGet)
+
+			rt := r.PathPrefix("/{inventoryType:[0-9]+}").Subrouter()
+			rt.HandleFunc("/move", rest.RegisterInputHandler[MoveRestModel](l)(si)("move_asset", handleMove(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/equip", rest.RegisterInputHandler[MoveRestModel](l)(si)("equip_asset", handleEquip(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/unequip", rest.RegisterInputHandler[MoveRestModel](l)(si)("unequip_asset", handleUnequip(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/drop", rest.RegisterInputHandler[DropRestModel](l)(si)("drop_asset", handleDrop(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/destroy", rest.RegisterInputHandler[QuantityRestModel](l)(si)("destroy_asset", handleDestroy(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/assets", rest.RegisterInputHandler[CreateAssetRestModel](l)(si)("create_asset", handleCreateAsset(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/recharge", rest.RegisterInputHandler[QuantityRestModel](l)(si)("recharge_asset", handleRecharge(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/merge", rest.RegisterInputHandler[ArrangeRestModel](l)(si)("merge_compartment", handleMerge(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/sort", rest.RegisterInputHandler[ArrangeRestModel](l)(si)("sort_compartment", handleSort(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/capacity", rest.RegisterInputHandler[CapacityRestModel](l)(si)("increase_capacity", handleIncreaseCapacity(db))).Methods(http.MethodPost)
+			rt.HandleFunc("/loadout", rest.RegisterInputHandler[ApplyLoadoutRestModel](l)(si)("apply_loadout", handleApplyLoadout(db))).Methods(http.MethodPost)
+
+			rr := router.PathPrefix("/characters/{characterId}/inventory/reservations").Subrouter()
+			rr.HandleFunc("", registerGet("get_reservations", handleGetReservations(db))).Methods(http.MethodGet)
+			rr.HandleFunc("/{transactionId}", registerGet("cancel_reservations", handleCancelReservations(db))).Methods(http.MethodDelete)
+
+			router.HandleFunc("/characters/{characterId}/inventory/equipment/statistics", registerGet("get_equipment_statistics", handleGetEquipmentStatistics(db))).Methods(http.MethodGet)
+
+			rc := router.PathPrefix("/characters/{characterId}/inventory/consistency").Subrouter()
+			rc.HandleFunc("", registerGet("check_consistency", handleCheckConsistency(db, false))).Methods(http.MethodGet)
+			rc.HandleFunc("", registerGet("fix_consistency", handleCheckConsistency(db, true))).Methods(http.MethodPost)
+
+			ra := router.PathPrefix("/inventory/consistency").Subrouter()
+			ra.HandleFunc("", registerGet("check_tenant_consistency", handleCheckTenantConsistency(db, false))).Methods(http.MethodGet)
+			ra.HandleFunc("", registerGet("fix_tenant_consistency", handleCheckTenantConsistency(db, true))).Methods(http.MethodPost)
 		}
 	}
 }
 
+// handleGetAssets lists a compartment's assets, each showing the quantity reserved in its slot. Reservations are
+// matched by the compartment's own inventory type.
+func handleGetAssets(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseCompartmentId(d.Logger(), func(compartmentId uuid.UUID) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					m, err := NewProcessor(d.Logger(), d.Context(), db).GetById(compartmentId)
+					if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && m.CharacterId() != characterId) {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to retrieve assets of compartment [%s].", compartmentId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					rm, err := model.SliceMap(asset.Transform)(model.FixedProvider(m.Assets()))(model.ParallelMap())()
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Creating REST model.")
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+
+					query := r.URL.Query()
+					queryParams := jsonapi.ParseQueryFields(&query)
+					server.MarshalResponse[[]asset.BaseRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+				}
+			})
+		})
+	}
+}
+
 func handleGetCompartment(db *gorm.DB) rest.GetHandler {
 	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
 		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
@@ -101,3 +171,381 @@ func handleGetCompartmentByType(db *gorm.DB) rest.GetHandler {
 		})
 	}
 }
+
+func handleGetReservations(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				var inventoryType inventory.Type
+				if typeStr := r.URL.Query().Get("inventoryType"); typeStr != "" {
+					typeInt, err := strconv.Atoi(typeStr)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Invalid inventoryType parameter: %s", typeStr)
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					inventoryType = inventory.Type(typeInt)
+				}
+
+				transactionId := uuid.Nil
+				if idStr := r.URL.Query().Get("transactionId"); idStr != "" {
+					var err error
+					transactionId, err = uuid.Parse(idStr)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Invalid transactionId parameter: %s", idStr)
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+				}
+
+				ms, err := reservation.NewProcessor(d.Logger(), d.Context(), db).ActiveProvider(characterId, inventoryType, transactionId)()
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to retrieve reservations for character [%d].", characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				rm, err := model.SliceMap(reservation.Transform)(model.FixedProvider(ms))(model.ParallelMap())()
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Creating REST model.")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				query := r.URL.Query()
+				queryParams := jsonapi.ParseQueryFields(&query)
+				server.MarshalResponse[[]reservation.RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+			}
+		})
+	}
+}
+
+func handleCancelReservations(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseTransactionId(d.Logger(), func(transactionId uuid.UUID) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					rs, err := NewProcessor(d.Logger(), d.Context(), db).CancelReservationsAndEmit(transactionId, characterId)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to cancel reservations of [%s] for character [%d].", transactionId.String(), characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					if len(rs) == 0 {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+			})
+		})
+	}
+}
+
+// command applies a command to a compartment under the given transaction.
+type command func(p *Processor, transactionId uuid.UUID) error
+
+// handleCommand applies a command synchronously and responds with the resulting compartment. The command emits the
+// same status events as its Kafka counterpart, including the ERROR event on failure, which is also answered with a
+// JSON:API error carrying the error code and reason. A request repeating the transactionId, character, compartment and
+// body of an applied one is not applied again.
+func handleCommand(d *rest.HandlerDependency, c *rest.HandlerContext, db *gorm.DB) func(characterId uint32, inventoryType inventory.Type, commandType string, errorCode string, transactionId uuid.UUID, body any, f command) http.HandlerFunc {
+	return func(characterId uint32, inventoryType inventory.Type, commandType string, errorCode string, transactionId uuid.UUID, body any, f command) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ctx := ledger.WithSubjectCommand(d.Context(), commandType, transactionId, ledger.Subject(characterId, inventoryType, body))
+			if transactionId == uuid.Nil {
+				transactionId = uuid.New()
+			}
+			p := NewProcessor(d.Logger(), ctx, db)
+			err := f(p, transactionId)
+			if err != nil && !errors.Is(err, ledger.ErrDuplicate) {
+				_ = p.ReportFailureAndEmit(transactionId, characterId, errorCode, err)
+				cause := Classify(err)
+				rest.MarshalError(d.Logger())(w)(errorStatus(cause.Reason))(jsonapi.Error{
+					Code:   errorCode,
+					Title:  cause.Reason,
+					Detail: err.Error(),
+					Meta: map[string]interface{}{
+						"transactionId": transactionId.String(),
+						"inventoryType": cause.InventoryType,
+						"slot":          cause.Slot,
+					},
+				})
+				return
+			}
+
+			m, err := p.GetByCharacterAndType(characterId)(inventoryType)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Error retrieving compartment by type: %d", inventoryType)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			rm, err := model.Map(Transform)(model.FixedProvider(m))()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+		}
+	}
+}
+
+// errorStatus maps the reason a command failed to the HTTP status reported for it.
+func errorStatus(reason string) int {
+	switch reason {
+	case compartment.ErrorReasonCompartmentNotFound, compartment.ErrorReasonSlotEmpty, compartment.ErrorReasonAssetNotFound, compartment.ErrorReasonLoadoutNotFound, compartment.ErrorReasonCharacterNotFound:
+		return http.StatusNotFound
+	case compartment.ErrorReasonInvalidQuantity, compartment.ErrorReasonTemplateMismatch, compartment.ErrorReasonNotEquippable, compartment.ErrorReasonNotRechargeable:
+		return http.StatusBadRequest
+	case compartment.ErrorReasonInventoryFull, compartment.ErrorReasonInsufficientQuantity, compartment.ErrorReasonReserved, compartment.ErrorReasonNotReserved, compartment.ErrorReasonCompartmentExists, compartment.ErrorReasonSlotOccupied, compartment.ErrorReasonSlotConflict, compartment.ErrorReasonNoFreeSlotToUnequip:
+		return http.StatusConflict
+	case compartment.ErrorReasonRequirementsNotMet:
+		return http.StatusForbidden
+	case compartment.ErrorReasonLockTimeout:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// requireEquip rejects requests which name a compartment other than equipment, for commands only it supports.
+func requireEquip(l logrus.FieldLogger, inventoryType inventory.Type, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if inventoryType != inventory.TypeValueEquip {
+			l.Errorf("Inventory type [%d] does not support equipping.", inventoryType)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func handleMove(db *gorm.DB) rest.InputHandler[MoveRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i MoveRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandMove, compartment.MoveCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.MoveAndEmit(transactionId, characterId, inventoryType, i.Source, i.Destination)
+				})
+			})
+		})
+	}
+}
+
+func handleEquip(db *gorm.DB) rest.InputHandler[MoveRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i MoveRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return requireEquip(d.Logger(), inventoryType, handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandEquip, compartment.EquipCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.EquipItemAndEmit(transactionId, characterId, i.Source, i.Destination)
+				}))
+			})
+		})
+	}
+}
+
+func handleUnequip(db *gorm.DB) rest.InputHandler[MoveRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i MoveRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return requireEquip(d.Logger(), inventoryType, handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandUnequip, compartment.UnequipCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.RemoveEquipAndEmit(transactionId, characterId, i.Source, i.Destination)
+				}))
+			})
+		})
+	}
+}
+
+func handleApplyLoadout(db *gorm.DB) rest.InputHandler[ApplyLoadoutRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i ApplyLoadoutRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return requireEquip(d.Logger(), inventoryType, handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandApplyLoadout, compartment.ApplyLoadoutCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.ApplyLoadoutAndEmit(transactionId, characterId, i.LoadoutId, i.SkipMissing)
+				}))
+			})
+		})
+	}
+}
+
+func handleDrop(db *gorm.DB) rest.InputHandler[DropRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i DropRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandDrop, compartment.DropCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					m := _map.NewModel(world.Id(i.WorldId))(channel.Id(i.ChannelId))(_map.Id(i.MapId))
+					return p.DropAndEmit(transactionId, characterId, inventoryType, m, i.X, i.Y, i.Source, i.Quantity)
+				})
+			})
+		})
+	}
+}
+
+func handleDestroy(db *gorm.DB) rest.InputHandler[QuantityRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i QuantityRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandDestroy, compartment.DestroyCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.DestroyAssetAndEmit(transactionId, characterId, inventoryType, i.Slot, i.Quantity)
+				})
+			})
+		})
+	}
+}
+
+func handleCreateAsset(db *gorm.DB) rest.InputHandler[CreateAssetRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i CreateAssetRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandCreateAsset, compartment.CreateAssetCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.CreateAssetAndEmit(transactionId, characterId, inventoryType, i.TemplateId, i.Quantity, i.Expiration, i.OwnerId, i.Flag, i.Rechargeable)
+				})
+			})
+		})
+	}
+}
+
+func handleRecharge(db *gorm.DB) rest.InputHandler[QuantityRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i QuantityRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandRecharge, compartment.RechargeCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.RechargeAssetAndEmit(transactionId, characterId, inventoryType, i.Slot, i.Quantity)
+				})
+			})
+		})
+	}
+}
+
+func handleMerge(db *gorm.DB) rest.InputHandler[ArrangeRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i ArrangeRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandMerge, compartment.MergeCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.MergeAndCompactAndEmit(transactionId, characterId, inventoryType)
+				})
+			})
+		})
+	}
+}
+
+func handleSort(db *gorm.DB) rest.InputHandler[ArrangeRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i ArrangeRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandSort, compartment.SortCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.CompactAndSortAndEmit(transactionId, characterId, inventoryType)
+				})
+			})
+		})
+	}
+}
+
+func handleIncreaseCapacity(db *gorm.DB) rest.InputHandler[CapacityRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i CapacityRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseInventoryType(d.Logger(), func(inventoryType inventory.Type) http.HandlerFunc {
+				return handleCommand(d, c, db)(characterId, inventoryType, compartment.CommandIncreaseCapacity, compartment.IncreaseCapacityCommandFailed, i.TransactionId, i, func(p *Processor, transactionId uuid.UUID) error {
+					return p.IncreaseCapacityAndEmit(transactionId, characterId, inventoryType, i.Amount)
+				})
+			})
+		})
+	}
+}
+
+// handleCheckConsistency reports the character's consistency violations, fixing those it safely can when fix is set.
+func handleCheckConsistency(db *gorm.DB, fix bool) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				vs, err := NewProcessor(d.Logger(), d.Context(), db).CheckConsistencyAndEmit(characterId, fix)
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to check consistency of inventory for character [%d].", characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+				marshalViolations(d, c)(w, r)(vs)
+			}
+		})
+	}
+}
+
+// handleCheckTenantConsistency reports the consistency violations across the tenant, fixing those it safely can when
+// fix is set.
+func handleCheckTenantConsistency(db *gorm.DB, fix bool) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			vs, err := NewProcessor(d.Logger(), d.Context(), db).CheckTenantConsistencyAndEmit(fix)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to check consistency of inventories.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			marshalViolations(d, c)(w, r)(vs)
+		}
+	}
+}
+
+func marshalViolations(d *rest.HandlerDependency, c *rest.HandlerContext) func(w http.ResponseWriter, r *http.Request) func(vs []Violation) {
+	return func(w http.ResponseWriter, r *http.Request) func(vs []Violation) {
+		return func(vs []Violation) {
+			rm, err := model.SliceMap(TransformViolation)(model.FixedProvider(vs))(model.ParallelMap())()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[[]ViolationRestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+		}
+	}
+}
+
+// handleGetEquipmentStatistics reports the statistics granted by the character's equipment, including set bonuses
+// when setBonuses=true is requested.
+func handleGetEquipmentStatistics(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				setBonuses := false
+				if v := r.URL.Query().Get("setBonuses"); v != "" {
+					var err error
+					setBonuses, err = strconv.ParseBool(v)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Invalid setBonuses parameter: %s", v)
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+				}
+
+				m, err := NewProcessor(d.Logger(), d.Context(), db).EquipmentStatistics(characterId, setBonuses)
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to total equipment statistics for character [%d].", characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				rm, err := model.Map(statistics.Transform)(model.FixedProvider(m))()
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Creating REST model.")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				query := r.URL.Query()
+				queryParams := jsonapi.ParseQueryFields(&query)
+				server.MarshalResponse[statistics.RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package compartment
 import (
 	"atlas-inventory/asset"
 	"strconv"
+	"time"
 
 	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-model/model"
@@ -126,3 +127,199 @@ func Extract(rm RestModel) (Model, error) {
 		assets:        as,
 	}, nil
 }
+
+// MoveRestModel requests an asset be moved between two slots. It serves the move, equip and unequip endpoints. A
+// transactionId makes the request idempotent, and correlates the status events it emits.
+type MoveRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	Source        int16     `json:"source"`
+	Destination   int16     `json:"destination"`
+}
+
+func (r MoveRestModel) GetName() string {
+	return "moves"
+}
+
+func (r MoveRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *MoveRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+type DropRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	WorldId       byte      `json:"worldId"`
+	ChannelId     byte      `json:"channelId"`
+	MapId         uint32    `json:"mapId"`
+	Source        int16     `json:"source"`
+	Quantity      int16     `json:"quantity"`
+	X             int16     `json:"x"`
+	Y             int16     `json:"y"`
+}
+
+func (r DropRestModel) GetName() string {
+	return "drops"
+}
+
+func (r DropRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *DropRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+// QuantityRestModel requests the quantity of the asset in a slot be changed. It serves the destroy and recharge endpoints.
+type QuantityRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	Slot          int16     `json:"slot"`
+	Quantity      uint32    `json:"quantity"`
+}
+
+func (r QuantityRestModel) GetName() string {
+	return "quantities"
+}
+
+func (r QuantityRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *QuantityRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+type CreateAssetRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	TemplateId    uint32    `json:"templateId"`
+	Quantity      uint32    `json:"quantity"`
+	Expiration    time.Time `json:"expiration"`
+	OwnerId       uint32    `json:"ownerId"`
+	Flag          uint16    `json:"flag"`
+	Rechargeable  uint64    `json:"rechargeable"`
+}
+
+func (r CreateAssetRestModel) GetName() string {
+	return "assets"
+}
+
+func (r CreateAssetRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *CreateAssetRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+type CapacityRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	Amount        uint32    `json:"amount"`
+}
+
+func (r CapacityRestModel) GetName() string {
+	return "capacities"
+}
+
+func (r CapacityRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *CapacityRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+// ApplyLoadoutRestModel requests a saved loadout be put on. With skipMissing, slots whose asset the character no longer
+// has are skipped rather than failing the request.
+type ApplyLoadoutRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+	LoadoutId     uuid.UUID `json:"loadoutId"`
+	SkipMissing   bool      `json:"skipMissing"`
+}
+
+func (r ApplyLoadoutRestModel) GetName() string {
+	return "loadout-applications"
+}
+
+func (r ApplyLoadoutRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *ApplyLoadoutRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+// ArrangeRestModel requests a compartment be merged or sorted.
+type ArrangeRestModel struct {
+	Id            string    `json:"-"`
+	TransactionId uuid.UUID `json:"transactionId"`
+}
+
+func (r ArrangeRestModel) GetName() string {
+	return "arrangements"
+}
+
+func (r ArrangeRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *ArrangeRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+// ViolationRestModel reports an asset which breaks an invariant of its compartment. It is identified by the asset.
+type ViolationRestModel struct {
+	Id            uint32         `json:"-"`
+	Kind          string         `json:"kind"`
+	CharacterId   uint32         `json:"characterId"`
+	InventoryType inventory.Type `json:"inventoryType"`
+	CompartmentId uuid.UUID      `json:"compartmentId"`
+	TemplateId    uint32         `json:"templateId"`
+	Slot          int16          `json:"slot"`
+	Fixed         bool           `json:"fixed"`
+	NewSlot       int16          `json:"newSlot"`
+}
+
+func (r ViolationRestModel) GetName() string {
+	return "violations"
+}
+
+func (r ViolationRestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *ViolationRestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func TransformViolation(v Violation) (ViolationRestModel, error) {
+	return ViolationRestModel{
+		Id:            v.AssetId(),
+		Kind:          v.Kind(),
+		CharacterId:   v.CharacterId(),
+		InventoryType: v.InventoryType(),
+		CompartmentId: v.CompartmentId(),
+		TemplateId:    v.TemplateId(),
+		Slot:          v.Slot(),
+		Fixed:         v.Fixed(),
+		NewSlot:       v.NewSlot(),
+	}, nil
+}
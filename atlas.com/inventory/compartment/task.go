@@ -0,0 +1,105 @@
+package compartment
+
+import (
+	"atlas-inventory/reservation"
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ReservationExpiryTask struct {
+	l        logrus.FieldLogger
+	ctx      context.Context
+	db       *gorm.DB
+	interval time.Duration
+}
+
+func NewReservationExpiryTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, interval time.Duration) *ReservationExpiryTask {
+	return &ReservationExpiryTask{
+		l:        l,
+		ctx:      ctx,
+		db:       db,
+		interval: interval,
+	}
+}
+
+func (t *ReservationExpiryTask) Run() {
+	now := time.Now()
+	ts, err := reservation.ExpiredTenantsProvider(t.db.WithContext(t.ctx))(now)()
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to retrieve tenants with expired reservations.")
+		return
+	}
+	for _, te := range ts {
+		tctx := tenant.WithContext(t.ctx, te)
+		_ = NewProcessor(t.l, tctx, t.db).ExpireReservationsAndEmit(now)
+	}
+}
+
+func (t *ReservationExpiryTask) SleepTime() time.Duration {
+	return t.interval
+}
+
+// AssetExpiryTask removes expired assets, and warns of those about to expire, across every tenant. The clock and the
+// tenants walked are injectable so tests control what has expired.
+type AssetExpiryTask struct {
+	l        logrus.FieldLogger
+	ctx      context.Context
+	db       *gorm.DB
+	tenants  model.Provider[[]tenant.Model]
+	interval time.Duration
+	clock    func() time.Time
+}
+
+func NewAssetExpiryTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, tenants model.Provider[[]tenant.Model], interval time.Duration, clock func() time.Time) *AssetExpiryTask {
+	return &AssetExpiryTask{
+		l:        l,
+		ctx:      ctx,
+		db:       db,
+		tenants:  tenants,
+		interval: interval,
+		clock:    clock,
+	}
+}
+
+func (t *AssetExpiryTask) Run() {
+	now := t.clock()
+	ts, err := t.tenants()
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to retrieve tenants to expire assets of.")
+		return
+	}
+	for _, te := range ts {
+		tctx := tenant.WithContext(t.ctx, te)
+		p := NewProcessor(t.l, tctx, t.db)
+		_ = p.ExpireAssetsAndEmit(now)
+		_ = p.WarnExpiringAssetsAndEmit(now)
+	}
+}
+
+func (t *AssetExpiryTask) SleepTime() time.Duration {
+	return t.interval
+}
+
+// CheckConsistency checks the compartments of every tenant, fixing what it safely can when fix is set. It is meant to
+// run once at startup, before commands are consumed.
+func CheckConsistency(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, tenants model.Provider[[]tenant.Model], fix bool) {
+	ts, err := tenants()
+	if err != nil {
+		l.WithError(err).Errorf("Unable to retrieve tenants to check consistency of.")
+		return
+	}
+	for _, te := range ts {
+		tctx := tenant.WithContext(ctx, te)
+		vs, err := NewProcessor(l, tctx, db).CheckTenantConsistencyAndEmit(fix)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to check consistency of tenant [%s].", te.Id())
+			continue
+		}
+		l.Infof("Found [%d] consistency violations for tenant [%s].", len(vs), te.Id())
+	}
+}
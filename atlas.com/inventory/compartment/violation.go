@@ -0,0 +1,66 @@
+package compartment
+
+import (
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+const (
+	// ViolationSlotCollision is an asset sharing its slot with an older asset.
+	ViolationSlotCollision = "SLOT_COLLISION"
+	// ViolationTemporarySlot is an asset left in the temporary slot used while swapping equipment.
+	ViolationTemporarySlot = "TEMPORARY_SLOT"
+	// ViolationBeyondCapacity is an asset in a slot outside the compartment's capacity.
+	ViolationBeyondCapacity = "BEYOND_CAPACITY"
+)
+
+// Violation describes an asset which breaks an invariant of its compartment, and whether it was fixed.
+type Violation struct {
+	kind          string
+	characterId   uint32
+	inventoryType inventory.Type
+	compartmentId uuid.UUID
+	assetId       uint32
+	templateId    uint32
+	slot          int16
+	fixed         bool
+	newSlot       int16
+}
+
+func (v Violation) Kind() string {
+	return v.kind
+}
+
+func (v Violation) CharacterId() uint32 {
+	return v.characterId
+}
+
+func (v Violation) InventoryType() inventory.Type {
+	return v.inventoryType
+}
+
+func (v Violation) CompartmentId() uuid.UUID {
+	return v.compartmentId
+}
+
+func (v Violation) AssetId() uint32 {
+	return v.assetId
+}
+
+func (v Violation) TemplateId() uint32 {
+	return v.templateId
+}
+
+// Slot is the slot the asset occupied when it was found.
+func (v Violation) Slot() int16 {
+	return v.slot
+}
+
+func (v Violation) Fixed() bool {
+	return v.fixed
+}
+
+// NewSlot is the slot the asset was moved to, when it was fixed.
+func (v Violation) NewSlot() int16 {
+	return v.newSlot
+}
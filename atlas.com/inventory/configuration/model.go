@@ -0,0 +1,151 @@
+package configuration
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Duration is a time.Duration which is written in configuration as a Go duration string, such as "30s" or "5m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// File is the layout of the tenant configuration file. Tenant entries only need to name the values they override.
+type File struct {
+	Defaults TenantConfig            `json:"defaults"`
+	Tenants  map[string]TenantConfig `json:"tenants"`
+}
+
+type TenantConfig struct {
+	Reservation ReservationConfig `json:"reservation"`
+	Expiry      ExpiryConfig      `json:"expiry"`
+	Equip       EquipConfig       `json:"equip"`
+}
+
+func (c TenantConfig) merge(o TenantConfig) TenantConfig {
+	return TenantConfig{
+		Reservation: c.Reservation.merge(o.Reservation),
+		Expiry:      c.Expiry.merge(o.Expiry),
+		Equip:       c.Equip.merge(o.Equip),
+	}
+}
+
+type ReservationConfig struct {
+	DefaultTtl Duration `json:"defaultTtl"`
+	MaxTtl     Duration `json:"maxTtl"`
+}
+
+func (c ReservationConfig) merge(o ReservationConfig) ReservationConfig {
+	r := c
+	if o.DefaultTtl > 0 {
+		r.DefaultTtl = o.DefaultTtl
+	}
+	if o.MaxTtl > 0 {
+		r.MaxTtl = o.MaxTtl
+	}
+	return r
+}
+
+// TimeToLive bounds a requested reservation lifetime. Zero requests the default, and nothing may exceed the maximum.
+func (c ReservationConfig) TimeToLive(requested time.Duration) time.Duration {
+	ttl := requested
+	if ttl <= 0 {
+		ttl = time.Duration(c.DefaultTtl)
+	}
+	if ttl > time.Duration(c.MaxTtl) {
+		ttl = time.Duration(c.MaxTtl)
+	}
+	return ttl
+}
+
+type ExpiryConfig struct {
+	WarningThresholds []Duration `json:"warningThresholds"`
+}
+
+func (c ExpiryConfig) merge(o ExpiryConfig) ExpiryConfig {
+	r := c
+	if len(o.WarningThresholds) > 0 {
+		r.WarningThresholds = o.WarningThresholds
+	}
+	return r
+}
+
+// Thresholds yields how long before expiring an asset is warned, longest first. Non-positive thresholds are ignored.
+func (c ExpiryConfig) Thresholds() []time.Duration {
+	results := make([]time.Duration, 0, len(c.WarningThresholds))
+	for _, t := range c.WarningThresholds {
+		if t > 0 {
+			results = append(results, time.Duration(t))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i] > results[j] })
+	return results
+}
+
+type EquipConfig struct {
+	SkipRequirementsForGm *bool              `json:"skipRequirementsForGm"`
+	SlotConflicts         []SlotConflictRule `json:"slotConflicts"`
+}
+
+func (c EquipConfig) merge(o EquipConfig) EquipConfig {
+	r := c
+	if o.SkipRequirementsForGm != nil {
+		r.SkipRequirementsForGm = o.SkipRequirementsForGm
+	}
+	if len(o.SlotConflicts) > 0 {
+		r.SlotConflicts = o.SlotConflicts
+	}
+	return r
+}
+
+// SkipsRequirementsForGm reports whether GM characters may equip items whose requirements they do not meet.
+func (c EquipConfig) SkipsRequirementsForGm() bool {
+	return c.SkipRequirementsForGm != nil && *c.SkipRequirementsForGm
+}
+
+const (
+	SlotConflictActionUnequip = "UNEQUIP"
+	SlotConflictActionReject  = "REJECT"
+)
+
+// SlotConflictMatch selects equipment by item classification and equipped slot. An empty list matches anything.
+type SlotConflictMatch struct {
+	Classifications []uint32 `json:"classifications"`
+	Slots           []int16  `json:"slots"`
+}
+
+func (m SlotConflictMatch) Matches(classification uint32, slot int16) bool {
+	return (len(m.Classifications) == 0 || slices.Contains(m.Classifications, classification)) && (len(m.Slots) == 0 || slices.Contains(m.Slots, slot))
+}
+
+// SlotConflictRule declares that equipping an item matched by Equipping conflicts with equipment already worn which is
+// matched by Equipped. Once more than Limit worn items conflict, the action is applied: UNEQUIP moves them back into
+// the inventory, and REJECT refuses the equip. Only equipment on the same layer (cash or regular) conflicts unless
+// AnyLayer is set, and SameTemplate restricts the conflict to equipment of the item's own template.
+type SlotConflictRule struct {
+	Name         string            `json:"name"`
+	Equipping    SlotConflictMatch `json:"equipping"`
+	Equipped     SlotConflictMatch `json:"equipped"`
+	SameTemplate bool              `json:"sameTemplate"`
+	AnyLayer     bool              `json:"anyLayer"`
+	Limit        uint32            `json:"limit"`
+	Action       string            `json:"action"`
+}
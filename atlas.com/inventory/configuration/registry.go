@@ -0,0 +1,83 @@
+package configuration
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const EnvTenantConfigurationPath = "TENANT_CONFIGURATION_PATH"
+
+var builtIn = TenantConfig{
+	Reservation: ReservationConfig{
+		DefaultTtl: Duration(time.Second * 30),
+		MaxTtl:     Duration(time.Minute * 5),
+	},
+	Expiry: ExpiryConfig{
+		WarningThresholds: []Duration{Duration(time.Hour * 24), Duration(time.Hour)},
+	},
+	Equip: EquipConfig{
+		SlotConflicts: []SlotConflictRule{
+			{Name: "overall-pants", Equipping: SlotConflictMatch{Classifications: []uint32{105}}, Equipped: SlotConflictMatch{Classifications: []uint32{106}}, Action: SlotConflictActionUnequip},
+			{Name: "pants-overall", Equipping: SlotConflictMatch{Classifications: []uint32{106}}, Equipped: SlotConflictMatch{Classifications: []uint32{105}}, Action: SlotConflictActionUnequip},
+			{Name: "two-handed-shield", Equipping: SlotConflictMatch{Classifications: twoHanded}, Equipped: SlotConflictMatch{Classifications: []uint32{109}}, Action: SlotConflictActionUnequip},
+			{Name: "shield-two-handed", Equipping: SlotConflictMatch{Classifications: []uint32{109}}, Equipped: SlotConflictMatch{Classifications: twoHanded}, Action: SlotConflictActionUnequip},
+			{Name: "duplicate-ring", Equipping: SlotConflictMatch{Classifications: []uint32{111}}, Equipped: SlotConflictMatch{Classifications: []uint32{111}}, SameTemplate: true, Action: SlotConflictActionReject},
+		},
+	},
+}
+
+// twoHanded are the weapon classifications which cannot be used with a shield: two-handed swords, axes and blunt
+// weapons, spears, pole arms, bows, crossbows, claws, knuckles and guns.
+var twoHanded = []uint32{140, 141, 142, 143, 144, 145, 146, 147, 148, 149}
+
+var (
+	lock   sync.RWMutex
+	loaded = File{Tenants: make(map[string]TenantConfig)}
+)
+
+// Load reads the tenant configuration file named by TENANT_CONFIGURATION_PATH. Without one, built-in defaults apply to every tenant.
+func Load(l logrus.FieldLogger) {
+	path, ok := os.LookupEnv(EnvTenantConfigurationPath)
+	if !ok || path == "" {
+		l.Infof("No tenant configuration provided. Using defaults.")
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.WithError(err).Errorf("Unable to read tenant configuration [%s]. Using defaults.", path)
+		return
+	}
+	var f File
+	err = json.Unmarshal(data, &f)
+	if err != nil {
+		l.WithError(err).Errorf("Unable to parse tenant configuration [%s]. Using defaults.", path)
+		return
+	}
+	if f.Tenants == nil {
+		f.Tenants = make(map[string]TenantConfig)
+	}
+	Set(f)
+	l.Infof("Loaded tenant configuration [%s] with [%d] tenant overrides.", path, len(f.Tenants))
+}
+
+func Set(f File) {
+	lock.Lock()
+	defer lock.Unlock()
+	loaded = f
+}
+
+// GetTenantConfig resolves the configuration for a tenant, layering its overrides on the file and built-in defaults.
+func GetTenantConfig(tenantId uuid.UUID) TenantConfig {
+	lock.RLock()
+	defer lock.RUnlock()
+	c := builtIn.merge(loaded.Defaults)
+	if tc, ok := loaded.Tenants[tenantId.String()]; ok {
+		c = c.merge(tc)
+	}
+	return c
+}
@@ -0,0 +1,30 @@
+package cache
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// advanceGeneration counts one more invalidation of the tenant's cached data, returning the generation it reached.
+func advanceGeneration(db *gorm.DB, tenantId uuid.UUID) (uint64, error) {
+	var generation uint64
+	err := database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+		result := tx.Model(&Entity{}).Where("tenant_id = ?", tenantId).Update("generation", gorm.Expr("generation + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			generation = 1
+			return tx.Create(&Entity{TenantId: tenantId, Generation: generation}).Error
+		}
+		e, err := getByTenantId(tenantId)(tx)()
+		if err != nil {
+			return err
+		}
+		generation = e.Generation
+		return nil
+	})
+	return generation, err
+}
@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+const (
+	EnvTTL  = "DATA_CACHE_TTL"
+	EnvSize = "DATA_CACHE_SIZE"
+)
+
+const (
+	defaultTTL  = time.Hour
+	defaultSize = 10000
+)
+
+// Key identifies one piece of template data as served to a tenant's region and game version.
+type Key struct {
+	TenantId     uuid.UUID
+	Region       string
+	MajorVersion uint16
+	MinorVersion uint16
+	Kind         string
+	Id           uint32
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s:%s:%d.%d:%s:%d", k.TenantId.String(), k.Region, k.MajorVersion, k.MinorVersion, k.Kind, k.Id)
+}
+
+type entry struct {
+	key       Key
+	value     any
+	expiresAt time.Time
+}
+
+// Stats counts how the cache has served lookups since it was created.
+type Stats struct {
+	Hits         uint64
+	Misses       uint64
+	LoadFailures uint64
+	Evictions    uint64
+	Size         int
+	Capacity     int
+	TTL          time.Duration
+}
+
+// Cache holds template data read from the data service. Entries expire after the TTL, the least recently used entry
+// is evicted once the size bound is reached, and concurrent misses for a key share one load. Failed loads, and loads
+// which finish after the tenant's data was invalidated, are not cached.
+type Cache struct {
+	mu           sync.Mutex
+	ttl          time.Duration
+	size         int
+	now          func() time.Time
+	entries      map[Key]*list.Element
+	order        *list.List
+	epochs       map[uuid.UUID]uint64
+	generations  map[uuid.UUID]uint64
+	group        singleflight.Group
+	hits         atomic.Uint64
+	misses       atomic.Uint64
+	loadFailures atomic.Uint64
+	evictions    atomic.Uint64
+}
+
+func NewCache(ttl time.Duration, size int, now func() time.Time) *Cache {
+	return &Cache{
+		ttl:         ttl,
+		size:        size,
+		now:         now,
+		entries:     make(map[Key]*list.Element),
+		order:       list.New(),
+		epochs:      make(map[uuid.UUID]uint64),
+		generations: make(map[uuid.UUID]uint64),
+	}
+}
+
+var cache *Cache
+var once sync.Once
+
+// GetCache returns the cache shared by the data processors, sized by DATA_CACHE_TTL and DATA_CACHE_SIZE.
+func GetCache() *Cache {
+	once.Do(func() {
+		ttl := defaultTTL
+		if d, err := time.ParseDuration(os.Getenv(EnvTTL)); err == nil && d > 0 {
+			ttl = d
+		}
+		size := defaultSize
+		if s, err := strconv.Atoi(os.Getenv(EnvSize)); err == nil && s > 0 {
+			size = s
+		}
+		cache = NewCache(ttl, size, time.Now)
+	})
+	return cache
+}
+
+func (c *Cache) get(k Key) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[k]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, k)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.value, true
+}
+
+// epoch counts the invalidations of the tenant's entries made through this cache.
+func (c *Cache) epoch(tenantId uuid.UUID) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.epochs[tenantId]
+}
+
+// put caches v, unless the tenant's entries were invalidated since its load began in epoch.
+func (c *Cache) put(k Key, v any, epoch uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.epochs[k.TenantId] != epoch {
+		return
+	}
+	expiresAt := c.now().Add(c.ttl)
+	if el, ok := c.entries[k]; ok {
+		e := el.Value.(*entry)
+		e.value = v
+		e.expiresAt = expiresAt
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[k] = c.order.PushFront(&entry{key: k, value: v, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*entry).key)
+		c.evictions.Add(1)
+	}
+}
+
+// Invalidate discards every entry held for the tenant, and the result of every load in flight for it, returning how
+// many entries were discarded. Only this cache is affected; InvalidateShared reaches every replica.
+func (c *Cache) Invalidate(tenantId uuid.UUID) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.invalidate(tenantId)
+}
+
+// InvalidateShared invalidates the tenant's entries, and records the invalidation in db so that the caches of other
+// replicas sharing it discard theirs once their InvalidationTask next runs.
+func (c *Cache) InvalidateShared(db *gorm.DB, tenantId uuid.UUID) (int, error) {
+	generation, err := advanceGeneration(db, tenantId)
+	if err != nil {
+		return 0, err
+	}
+	return c.Advance(tenantId, generation), nil
+}
+
+// Advance invalidates the tenant's entries when generation differs from the one the cache last saw for the tenant,
+// returning how many were discarded. A tenant never invalidated is at generation zero.
+func (c *Cache) Advance(tenantId uuid.UUID, generation uint64) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generations[tenantId] == generation {
+		return 0
+	}
+	c.generations[tenantId] = generation
+	return c.invalidate(tenantId)
+}
+
+func (c *Cache) invalidate(tenantId uuid.UUID) int {
+	c.epochs[tenantId]++
+	count := 0
+	for k, el := range c.entries {
+		if k.TenantId == tenantId {
+			c.order.Remove(el)
+			delete(c.entries, k)
+			count++
+		}
+	}
+	return count
+}
+
+func (c *Cache) Stats() Stats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+	return Stats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		LoadFailures: c.loadFailures.Load(),
+		Evictions:    c.evictions.Load(),
+		Size:         size,
+		Capacity:     c.size,
+		TTL:          c.ttl,
+	}
+}
+
+// Provider serves the data identified by kind and id for the context's tenant from the cache, falling back to p on a
+// miss. Without a tenant in the context, p is used directly.
+func Provider[M any](c *Cache, ctx context.Context, kind string, id uint32) func(p model.Provider[M]) model.Provider[M] {
+	return func(p model.Provider[M]) model.Provider[M] {
+		return func() (M, error) {
+			t, err := tenant.FromContext(ctx)()
+			if err != nil {
+				return p()
+			}
+			k := Key{TenantId: t.Id(), Region: t.Region(), MajorVersion: t.MajorVersion(), MinorVersion: t.MinorVersion(), Kind: kind, Id: id}
+			if v, ok := c.get(k); ok {
+				c.hits.Add(1)
+				return v.(M), nil
+			}
+			c.misses.Add(1)
+			// Misses after an invalidation do not share a load begun before it.
+			epoch := c.epoch(k.TenantId)
+			v, err, _ := c.group.Do(fmt.Sprintf("%s:%d", k.String(), epoch), func() (interface{}, error) {
+				m, err := p()
+				if err != nil {
+					c.loadFailures.Add(1)
+					return nil, err
+				}
+				c.put(k, m, epoch)
+				return m, nil
+			})
+			if err != nil {
+				var m M
+				return m, err
+			}
+			return v.(M), nil
+		}
+	}
+}
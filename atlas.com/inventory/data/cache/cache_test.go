@@ -0,0 +1,228 @@
+package cache_test
+
+import (
+	"atlas-inventory/data/cache"
+	"atlas-inventory/database"
+	"atlas-inventory/schema"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testContext(region string, majorVersion uint16) context.Context {
+	t, _ := tenant.Create(uuid.New(), region, majorVersion, 1)
+	return tenant.WithContext(context.Background(), t)
+}
+
+func counting(loads *atomic.Int64, value uint32) func() (uint32, error) {
+	return func() (uint32, error) {
+		loads.Add(1)
+		return value, nil
+	}
+}
+
+// TestProviderCachesPerTenant verifies a value is loaded once per tenant and kept until its TTL lapses.
+func TestProviderCachesPerTenant(t *testing.T) {
+	now := time.Now()
+	c := cache.NewCache(time.Minute, 10, func() time.Time { return now })
+	ctx := testContext("GMS", 83)
+	other := testContext("GMS", 83)
+
+	var loads atomic.Int64
+	for i := 0; i < 3; i++ {
+		v, err := cache.Provider[uint32](c, ctx, "setups", 1)(counting(&loads, 100))()
+		if err != nil || v != 100 {
+			t.Fatalf("Unexpected value [%d] and error [%v].", v, err)
+		}
+	}
+	if _, err := cache.Provider[uint32](c, other, "setups", 1)(counting(&loads, 100))(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loads.Load() != 2 {
+		t.Fatalf("Expected one load per tenant, got [%d].", loads.Load())
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := cache.Provider[uint32](c, ctx, "setups", 1)(counting(&loads, 100))(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loads.Load() != 3 {
+		t.Fatalf("Expected an expired entry to be loaded again.")
+	}
+
+	s := c.Stats()
+	if s.Hits != 2 || s.Misses != 3 {
+		t.Fatalf("Expected [2] hits and [3] misses, got [%d] and [%d].", s.Hits, s.Misses)
+	}
+}
+
+// TestProviderBoundsSizeAndSkipsFailures verifies the least recently used entry is evicted at capacity, and failed
+// loads are not cached.
+func TestProviderBoundsSizeAndSkipsFailures(t *testing.T) {
+	c := cache.NewCache(time.Minute, 2, time.Now)
+	ctx := testContext("GMS", 83)
+
+	var loads atomic.Int64
+	for _, id := range []uint32{1, 2, 1, 3} {
+		if _, err := cache.Provider[uint32](c, ctx, "etcs", id)(counting(&loads, id))(); err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+	}
+	if _, err := cache.Provider[uint32](c, ctx, "etcs", 1)(counting(&loads, 1))(); err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if loads.Load() != 3 || c.Stats().Evictions != 1 || c.Stats().Size != 2 {
+		t.Fatalf("Expected the least recently used entry to be evicted, got [%d] loads and stats [%+v].", loads.Load(), c.Stats())
+	}
+
+	failure := errors.New("unavailable")
+	_, err := cache.Provider[uint32](c, ctx, "etcs", 4)(func() (uint32, error) { return 0, failure })()
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the load failure, got [%v].", err)
+	}
+	if _, err = cache.Provider[uint32](c, ctx, "etcs", 4)(counting(&loads, 4))(); err != nil {
+		t.Fatalf("Expected the failure not to be cached: %v", err)
+	}
+}
+
+// TestProviderSharesConcurrentLoads verifies concurrent misses for a key share a single load.
+func TestProviderSharesConcurrentLoads(t *testing.T) {
+	c := cache.NewCache(time.Minute, 10, time.Now)
+	ctx := testContext("GMS", 83)
+
+	var loads atomic.Int64
+	release := make(chan struct{})
+	load := func() (uint32, error) {
+		loads.Add(1)
+		<-release
+		return 7, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.Provider[uint32](c, ctx, "equipables", 1)(load)()
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if loads.Load() != 1 {
+		t.Fatalf("Expected concurrent misses to share one load, got [%d].", loads.Load())
+	}
+}
+
+// TestInvalidate verifies invalidation discards only the tenant's entries.
+func TestInvalidate(t *testing.T) {
+	c := cache.NewCache(time.Minute, 10, time.Now)
+	ctx := testContext("GMS", 83)
+	other := testContext("GMS", 83)
+
+	var loads atomic.Int64
+	_, _ = cache.Provider[uint32](c, ctx, "consumables", 1)(counting(&loads, 1))()
+	_, _ = cache.Provider[uint32](c, other, "consumables", 1)(counting(&loads, 1))()
+
+	if count := c.Invalidate(tenant.MustFromContext(ctx).Id()); count != 1 {
+		t.Fatalf("Expected one entry to be invalidated, got [%d].", count)
+	}
+	_, _ = cache.Provider[uint32](c, ctx, "consumables", 1)(counting(&loads, 1))()
+	_, _ = cache.Provider[uint32](c, other, "consumables", 1)(counting(&loads, 1))()
+	if loads.Load() != 3 {
+		t.Fatalf("Expected only the invalidated tenant to load again, got [%d] loads.", loads.Load())
+	}
+}
+
+// TestInvalidateDropsLoadsInFlight verifies a load which finishes after an invalidation is not cached, and that a miss
+// after the invalidation does not share it.
+func TestInvalidateDropsLoadsInFlight(t *testing.T) {
+	c := cache.NewCache(time.Minute, 10, time.Now)
+	ctx := testContext("GMS", 83)
+
+	var loads atomic.Int64
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.Provider[uint32](c, ctx, "consumables", 1)(func() (uint32, error) {
+			loads.Add(1)
+			close(started)
+			<-release
+			return 1, nil
+		})()
+	}()
+	<-started
+
+	c.Invalidate(tenant.MustFromContext(ctx).Id())
+	v, err := cache.Provider[uint32](c, ctx, "consumables", 1)(counting(&loads, 2))()
+	if err != nil || v != 2 {
+		t.Fatalf("Expected a miss after the invalidation to load afresh, got [%d] and [%v].", v, err)
+	}
+	close(release)
+	<-done
+
+	v, err = cache.Provider[uint32](c, ctx, "consumables", 1)(counting(&loads, 3))()
+	if err != nil || v != 2 || loads.Load() != 2 {
+		t.Fatalf("Expected the fresh load to be kept over the stale one, got [%d] after [%d] loads.", v, loads.Load())
+	}
+}
+
+// TestInvalidateSharedReachesOtherReplicas verifies an invalidation made through one cache is picked up by another
+// sharing the database, and only once.
+func TestInvalidateSharedReachesOtherReplicas(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	ctx := testContext("GMS", 83)
+	tenantId := tenant.MustFromContext(ctx).Id()
+	local := cache.NewCache(time.Minute, 10, time.Now)
+	remote := cache.NewCache(time.Minute, 10, time.Now)
+	var loads atomic.Int64
+	for _, c := range []*cache.Cache{local, remote} {
+		_, _ = cache.Provider[uint32](c, ctx, "etcs", 1)(counting(&loads, 1))()
+	}
+
+	for i := 0; i < 2; i++ {
+		count, err := local.InvalidateShared(db, tenantId)
+		if err != nil {
+			t.Fatalf("Failed to invalidate: %v", err)
+		}
+		if count != 1-i {
+			t.Fatalf("Expected the local entry to be invalidated once, got [%d].", count)
+		}
+	}
+	if remote.Stats().Size != 1 {
+		t.Fatalf("Expected the remote entry to be kept until the task runs.")
+	}
+
+	for _, c := range []*cache.Cache{local, remote} {
+		cache.NewInvalidationTask(l, context.Background(), db, c, time.Second).Run()
+	}
+	if local.Stats().Size != 0 || remote.Stats().Size != 0 {
+		t.Fatalf("Expected both caches to be empty, got [%d] and [%d] entries.", local.Stats().Size, remote.Stats().Size)
+	}
+	for _, c := range []*cache.Cache{local, remote} {
+		_, _ = cache.Provider[uint32](c, ctx, "etcs", 1)(counting(&loads, 1))()
+		cache.NewInvalidationTask(l, context.Background(), db, c, time.Second).Run()
+	}
+	if local.Stats().Size != 1 || remote.Stats().Size != 1 || loads.Load() != 4 {
+		t.Fatalf("Expected the reloaded entries to be kept, got [%d] loads.", loads.Load())
+	}
+}
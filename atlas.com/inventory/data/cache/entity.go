@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"github.com/google/uuid"
+)
+
+// Entity counts how often a tenant's cached data has been invalidated, so that every replica sharing the database
+// discards its own copy.
+type Entity struct {
+	TenantId   uuid.UUID `gorm:"primaryKey;type:uuid;not null"`
+	Generation uint64    `gorm:"not null"`
+}
+
+func (e Entity) TableName() string {
+	return "data_cache_generations"
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getByTenantId(tenantId uuid.UUID) database.EntityProvider[Entity] {
+	return func(db *gorm.DB) model.Provider[Entity] {
+		return database.Query[Entity](db, &Entity{TenantId: tenantId})
+	}
+}
+
+func getAll() database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db, &Entity{})
+	}
+}
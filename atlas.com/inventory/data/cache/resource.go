@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"atlas-inventory/rest"
+	"net/http"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
+	return func(db *gorm.DB) server.RouteInitializer {
+		return func(router *mux.Router, l logrus.FieldLogger) {
+			registerGet := rest.RegisterHandler(l)(si)
+			registerDelete := rest.RegisterHandler(l)(si)
+			r := router.PathPrefix("/inventory/data-cache").Subrouter()
+			r.HandleFunc("", registerGet("get_data_cache_statistics", handleGetStatistics)).Methods(http.MethodGet)
+			r.HandleFunc("", registerDelete("invalidate_data_cache", handleInvalidate(db))).Methods(http.MethodDelete)
+		}
+	}
+}
+
+func handleGetStatistics(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rm, err := model.Map(Transform)(model.FixedProvider(GetCache().Stats()))()
+		if err != nil {
+			d.Logger().WithError(err).Errorf("Creating REST model.")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		query := r.URL.Query()
+		queryParams := jsonapi.ParseQueryFields(&query)
+		server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+	}
+}
+
+// handleInvalidate discards the cached data of the requesting tenant, on this replica at once and on the others once
+// their InvalidationTask next runs, so it is read afresh from the data service.
+func handleInvalidate(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, _ *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			t := tenant.MustFromContext(d.Context())
+			count, err := GetCache().InvalidateShared(db.WithContext(d.Context()), t.Id())
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to invalidate cached data for tenant [%s].", t.Id())
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			d.Logger().Infof("Invalidated [%d] cached data entries for tenant [%s].", count, t.Id())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package cache
+
+type RestModel struct {
+	Id           string `json:"-"`
+	Hits         uint64 `json:"hits"`
+	Misses       uint64 `json:"misses"`
+	LoadFailures uint64 `json:"loadFailures"`
+	Evictions    uint64 `json:"evictions"`
+	Size         int    `json:"size"`
+	Capacity     int    `json:"capacity"`
+	TTL          int64  `json:"ttl"`
+}
+
+func (r RestModel) GetName() string {
+	return "data-cache-statistics"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id
+}
+
+func (r *RestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
+
+func Transform(s Stats) (RestModel, error) {
+	return RestModel{
+		Id:           "data-cache",
+		Hits:         s.Hits,
+		Misses:       s.Misses,
+		LoadFailures: s.LoadFailures,
+		Evictions:    s.Evictions,
+		Size:         s.Size,
+		Capacity:     s.Capacity,
+		TTL:          int64(s.TTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type InvalidationTask struct {
+	l        logrus.FieldLogger
+	ctx      context.Context
+	db       *gorm.DB
+	c        *Cache
+	interval time.Duration
+}
+
+// NewInvalidationTask creates a task which discards the entries c holds for every tenant whose data was invalidated
+// through another replica since the task last ran.
+func NewInvalidationTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, c *Cache, interval time.Duration) *InvalidationTask {
+	return &InvalidationTask{
+		l:        l,
+		ctx:      ctx,
+		db:       db,
+		c:        c,
+		interval: interval,
+	}
+}
+
+func (t *InvalidationTask) Run() {
+	es, err := getAll()(t.db.WithContext(t.ctx))()
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to retrieve data cache generations.")
+		return
+	}
+	for _, e := range es {
+		count := t.c.Advance(e.TenantId, e.Generation)
+		if count > 0 {
+			t.l.Infof("Invalidated [%d] cached data entries for tenant [%s], as its data was invalidated elsewhere.", count, e.TenantId)
+		}
+	}
+}
+
+func (t *InvalidationTask) SleepTime() time.Duration {
+	return t.interval
+}
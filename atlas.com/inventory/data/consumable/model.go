@@ -52,6 +52,7 @@ type Model struct {
 	unitPrice       float64
 	slotMax         uint32
 	timeLimited     bool
+	replaceItemId   uint32
 	notSale         bool
 	reqLevel        uint32
 	quest           bool
@@ -114,6 +115,11 @@ func (m Model) Price() uint32 {
 	return m.price
 }
 
+// ReplaceItemId is the item a time limited consumable is converted to once it expires, or zero if it is removed.
+func (m Model) ReplaceItemId() uint32 {
+	return m.replaceItemId
+}
+
 func (m Model) GetSpec(specType SpecType) (int32, bool) {
 	val, ok := m.spec[specType]
 	return val, ok
@@ -1,6 +1,7 @@
 package consumable
 
 import (
+	"atlas-inventory/data/cache"
 	"context"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/sirupsen/logrus"
@@ -12,23 +13,29 @@ type Processor interface {
 }
 
 type ProcessorImpl struct {
-	l   logrus.FieldLogger
-	ctx context.Context
+	l     logrus.FieldLogger
+	ctx   context.Context
+	cache *cache.Cache
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context) *ProcessorImpl {
 	p := &ProcessorImpl{
-		l:   l,
-		ctx: ctx,
+		l:     l,
+		ctx:   ctx,
+		cache: cache.GetCache(),
 	}
 	return p
 }
 
 func (p *ProcessorImpl) GetById(itemId uint32) (Model, error) {
-	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(itemId), Extract)()
+	return cache.Provider[Model](p.cache, p.ctx, "consumables", itemId)(requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(itemId), Extract))()
 }
 
 func (p *ProcessorImpl) GetRechargeable() ([]Model, error) {
+	return cache.Provider[[]Model](p.cache, p.ctx, "consumables.rechargeable", 0)(p.loadRechargeable)()
+}
+
+func (p *ProcessorImpl) loadRechargeable() ([]Model, error) {
 	restModels, err := requestRechargeable()(p.l, p.ctx)
 	if err != nil {
 		return nil, err
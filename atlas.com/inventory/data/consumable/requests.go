@@ -1,7 +1,7 @@
 package consumable
 
 import (
-	"atlas-inventory/rest"
+	"atlas-inventory/data/source"
 	"fmt"
 	"github.com/Chronicle20/atlas-rest/requests"
 )
@@ -12,14 +12,10 @@ const (
 	Rechargeable = Resource + "?fields[consumables]=rechargeable&filter[rechargeable]=true"
 )
 
-func getBaseRequest() string {
-	return requests.RootUrl("DATA")
-}
-
 func requestById(id uint32) requests.Request[RestModel] {
-	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+ById, id))
+	return source.For[RestModel]().Get(fmt.Sprintf(ById, id))
 }
 
 func requestRechargeable() requests.Request[[]RestModel] {
-	return rest.MakeGetRequest[[]RestModel](getBaseRequest() + Rechargeable)
+	return source.For[[]RestModel]().Get(Rechargeable)
 }
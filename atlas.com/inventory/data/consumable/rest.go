@@ -12,6 +12,7 @@ type RestModel struct {
 	UnitPrice       float64            `json:"unitPrice"`
 	SlotMax         uint32             `json:"slotMax"`
 	TimeLimited     bool               `json:"timeLimited"`
+	ReplaceItemId   uint32             `json:"replaceItemId"`
 	NotSale         bool               `json:"notSale"`
 	ReqLevel        uint32             `json:"reqLevel"`
 	Quest           bool               `json:"quest"`
@@ -109,6 +110,7 @@ func Extract(rm RestModel) (Model, error) {
 		unitPrice:       rm.UnitPrice,
 		slotMax:         rm.SlotMax,
 		timeLimited:     rm.TimeLimited,
+		replaceItemId:   rm.ReplaceItemId,
 		notSale:         rm.NotSale,
 		reqLevel:        rm.ReqLevel,
 		quest:           rm.Quest,
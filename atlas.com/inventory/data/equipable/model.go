@@ -18,6 +18,7 @@ type Model struct {
 	jump          uint16
 	slots         uint16
 	price         uint32
+	cash          bool
 }
 
 func (m Model) Strength() uint16 {
@@ -84,6 +85,11 @@ func (m Model) Slots() uint16 {
 	return m.slots
 }
 
+// Cash reports whether the equipment is sold through the cash shop, and so is tracked as a cash item.
+func (m Model) Cash() bool {
+	return m.cash
+}
+
 func (m Model) Price() uint32 {
 	return m.price
 }
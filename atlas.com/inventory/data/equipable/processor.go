@@ -1,6 +1,7 @@
 package equipable
 
 import (
+	"atlas-inventory/data/cache"
 	"context"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
@@ -8,20 +9,22 @@ import (
 )
 
 type Processor struct {
-	l   logrus.FieldLogger
-	ctx context.Context
+	l     logrus.FieldLogger
+	ctx   context.Context
+	cache *cache.Cache
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context) *Processor {
 	p := &Processor{
-		l:   l,
-		ctx: ctx,
+		l:     l,
+		ctx:   ctx,
+		cache: cache.GetCache(),
 	}
 	return p
 }
 
 func (p *Processor) ByIdModelProvider(id uint32) model.Provider[Model] {
-	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(id), Extract)
+	return cache.Provider[Model](p.cache, p.ctx, "equipables", id)(requests.Provider[RestModel, Model](p.l, p.ctx)(requestById(id), Extract))
 }
 
 func (p *Processor) GetById(id uint32) (Model, error) {
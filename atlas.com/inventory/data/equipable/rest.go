@@ -147,5 +147,6 @@ func Extract(m RestModel) (Model, error) {
 		jump:          m.Jump,
 		slots:         m.Slots,
 		price:         m.Price,
+		cash:          m.Cash,
 	}, nil
 }
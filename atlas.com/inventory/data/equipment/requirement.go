@@ -0,0 +1,65 @@
+package equipment
+
+import (
+	"atlas-inventory/character"
+	"atlas-inventory/data/equipment/statistics"
+)
+
+type Requirement string
+
+const (
+	RequirementLevel        = Requirement("LEVEL")
+	RequirementJob          = Requirement("JOB")
+	RequirementStrength     = Requirement("STRENGTH")
+	RequirementDexterity    = Requirement("DEXTERITY")
+	RequirementIntelligence = Requirement("INTELLIGENCE")
+	RequirementLuck         = Requirement("LUCK")
+)
+
+// UnmetRequirements lists the requirements of an item the character does not meet.
+func UnmetRequirements(r statistics.Requirements, c character.Model) []Requirement {
+	var results []Requirement
+	if c.Level() < r.Level() {
+		results = append(results, RequirementLevel)
+	}
+	if !jobPermitted(r.Job(), c.JobId()) {
+		results = append(results, RequirementJob)
+	}
+	if c.Strength() < r.Strength() {
+		results = append(results, RequirementStrength)
+	}
+	if c.Dexterity() < r.Dexterity() {
+		results = append(results, RequirementDexterity)
+	}
+	if c.Intelligence() < r.Intelligence() {
+		results = append(results, RequirementIntelligence)
+	}
+	if c.Luck() < r.Luck() {
+		results = append(results, RequirementLuck)
+	}
+	return results
+}
+
+// jobPermitted reports whether a job belongs to a branch in mask. Bits one through five permit warriors, magicians,
+// bowmen, thieves and pirates, whichever class line (explorer, Cygnus or legend) the job is from. Beginners only meet
+// an empty mask.
+func jobPermitted(mask uint16, jobId uint16) bool {
+	if mask == 0 {
+		return true
+	}
+	branch := (jobId / 100) % 10
+	if branch < 1 || branch > 5 {
+		return false
+	}
+	return mask&(1<<(branch-1)) != 0
+}
+
+// UnmetRequirements lists the requirements of the item the character does not meet.
+func (p *Processor) UnmetRequirements(itemId uint32, c character.Model) ([]Requirement, error) {
+	is, err := p.statProcessor.GetById(itemId)
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to retrieve statistics for item [%d].", itemId)
+		return nil, err
+	}
+	return UnmetRequirements(is.Requirements(), c), nil
+}
@@ -0,0 +1,64 @@
+package equipment_test
+
+import (
+	"atlas-inventory/character"
+	"atlas-inventory/data/equipment"
+	"atlas-inventory/data/equipment/statistics"
+	"slices"
+	"testing"
+)
+
+func testCharacter(t *testing.T, level byte, jobId uint16, strength uint16, dexterity uint16) character.Model {
+	c, err := character.Extract(character.RestModel{Id: 1, Level: level, JobId: jobId, Strength: strength, Dexterity: dexterity, Intelligence: 4, Luck: 4})
+	if err != nil {
+		t.Fatalf("Failed to extract character: %v", err)
+	}
+	return c
+}
+
+func testRequirements(t *testing.T, rm statistics.RestModel) statistics.Requirements {
+	s, err := statistics.Extract(rm)
+	if err != nil {
+		t.Fatalf("Failed to extract statistics: %v", err)
+	}
+	return s.Requirements()
+}
+
+// TestUnmetRequirements verifies level, stat and job requirements are each reported when not met.
+func TestUnmetRequirements(t *testing.T) {
+	// A warrior's sword requiring level 30, 95 STR and 25 DEX.
+	sword := testRequirements(t, statistics.RestModel{Id: 1302008, ReqLevel: 30, ReqJob: 1, ReqStrength: 95, ReqDexterity: 25})
+
+	tests := []struct {
+		name     string
+		c        character.Model
+		expected []equipment.Requirement
+	}{
+		{"fighter meeting everything", testCharacter(t, 30, 110, 95, 25), nil},
+		{"dawn warrior meeting everything", testCharacter(t, 35, 1110, 120, 30), nil},
+		{"aran meeting everything", testCharacter(t, 35, 2110, 120, 30), nil},
+		{"fighter under level", testCharacter(t, 29, 110, 95, 25), []equipment.Requirement{equipment.RequirementLevel}},
+		{"fighter short of stats", testCharacter(t, 30, 110, 94, 24), []equipment.Requirement{equipment.RequirementStrength, equipment.RequirementDexterity}},
+		{"magician", testCharacter(t, 30, 210, 95, 25), []equipment.Requirement{equipment.RequirementJob}},
+		{"beginner", testCharacter(t, 10, 0, 4, 4), []equipment.Requirement{equipment.RequirementLevel, equipment.RequirementJob, equipment.RequirementStrength, equipment.RequirementDexterity}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unmet := equipment.UnmetRequirements(sword, tt.c)
+			if !slices.Equal(unmet, tt.expected) {
+				t.Fatalf("Expected unmet requirements [%v], got [%v].", tt.expected, unmet)
+			}
+		})
+	}
+
+	// Items without a job requirement may be worn by any job, beginners included.
+	hat := testRequirements(t, statistics.RestModel{Id: 1002014, ReqLevel: 10})
+	if unmet := equipment.UnmetRequirements(hat, testCharacter(t, 10, 0, 4, 4)); len(unmet) != 0 {
+		t.Fatalf("Expected a beginner to meet every requirement, got [%v].", unmet)
+	}
+	// Thief and pirate bits both permit a brawler.
+	gloves := testRequirements(t, statistics.RestModel{Id: 1082145, ReqJob: 8 | 16})
+	if unmet := equipment.UnmetRequirements(gloves, testCharacter(t, 30, 510, 4, 4)); len(unmet) != 0 {
+		t.Fatalf("Expected a brawler to meet every requirement, got [%v].", unmet)
+	}
+}
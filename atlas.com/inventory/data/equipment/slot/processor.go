@@ -1,6 +1,7 @@
 package slot
 
 import (
+	"atlas-inventory/data/cache"
 	"context"
 	"github.com/Chronicle20/atlas-model/model"
 	"github.com/Chronicle20/atlas-rest/requests"
@@ -10,18 +11,20 @@ import (
 type Processor struct {
 	l       logrus.FieldLogger
 	ctx     context.Context
+	cache   *cache.Cache
 	GetById func(id uint32) ([]Model, error)
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context) *Processor {
 	p := &Processor{
-		l:   l,
-		ctx: ctx,
+		l:     l,
+		ctx:   ctx,
+		cache: cache.GetCache(),
 	}
 	p.GetById = model.CollapseProvider(p.ByIdModelProvider)
 	return p
 }
 
 func (p *Processor) ByIdModelProvider(id uint32) model.Provider[[]Model] {
-	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestEquipmentSlotDestination(id), Extract, model.Filters[Model]())
+	return cache.Provider[[]Model](p.cache, p.ctx, "equipment.slots", id)(requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestEquipmentSlotDestination(id), Extract, model.Filters[Model]()))
 }
@@ -1,7 +1,7 @@
 package slot
 
 import (
-	"atlas-inventory/rest"
+	"atlas-inventory/data/source"
 	"fmt"
 	"github.com/Chronicle20/atlas-rest/requests"
 )
@@ -12,10 +12,6 @@ const (
 	slotsForEquipment       = itemInformationById + "/slots"
 )
 
-func getBaseRequest() string {
-	return requests.RootUrl("DATA")
-}
-
 func requestEquipmentSlotDestination(id uint32) requests.Request[[]RestModel] {
-	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+slotsForEquipment, id))
+	return source.For[[]RestModel]().Get(fmt.Sprintf(slotsForEquipment, id))
 }
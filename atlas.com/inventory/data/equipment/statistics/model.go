@@ -18,6 +18,7 @@ type Model struct {
 	jump          uint16
 	slots         uint16
 	cash          bool
+	requirements  Requirements
 }
 
 func (m Model) Strength() uint16 {
@@ -87,3 +88,42 @@ func (m Model) Slots() uint16 {
 func (m Model) Cash() bool {
 	return m.cash
 }
+
+func (m Model) Requirements() Requirements {
+	return m.requirements
+}
+
+// Requirements are what a character must have to equip an item. Job is a mask of the job branches permitted, where
+// zero permits every job.
+type Requirements struct {
+	level        byte
+	job          uint16
+	strength     uint16
+	dexterity    uint16
+	intelligence uint16
+	luck         uint16
+}
+
+func (r Requirements) Level() byte {
+	return r.level
+}
+
+func (r Requirements) Job() uint16 {
+	return r.job
+}
+
+func (r Requirements) Strength() uint16 {
+	return r.strength
+}
+
+func (r Requirements) Dexterity() uint16 {
+	return r.dexterity
+}
+
+func (r Requirements) Intelligence() uint16 {
+	return r.intelligence
+}
+
+func (r Requirements) Luck() uint16 {
+	return r.luck
+}
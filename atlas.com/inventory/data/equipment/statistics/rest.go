@@ -6,24 +6,30 @@ import (
 )
 
 type RestModel struct {
-	Id            uint32          `json:"-"`
-	Strength      uint16          `json:"strength"`
-	Dexterity     uint16          `json:"dexterity"`
-	Intelligence  uint16          `json:"intelligence"`
-	Luck          uint16          `json:"luck"`
-	HP            uint16          `json:"hp"`
-	MP            uint16          `json:"mp"`
-	WeaponAttack  uint16          `json:"weaponAttack"`
-	MagicAttack   uint16          `json:"magicAttack"`
-	WeaponDefense uint16          `json:"weaponDefense"`
-	MagicDefense  uint16          `json:"magicDefense"`
-	Accuracy      uint16          `json:"accuracy"`
-	Avoidability  uint16          `json:"avoidability"`
-	Speed         uint16          `json:"speed"`
-	Jump          uint16          `json:"jump"`
-	Slots         uint16          `json:"slots"`
-	Cash          bool            `json:"cash"`
-	EquipSlots    []SlotRestModel `json:"-"`
+	Id              uint32          `json:"-"`
+	Strength        uint16          `json:"strength"`
+	Dexterity       uint16          `json:"dexterity"`
+	Intelligence    uint16          `json:"intelligence"`
+	Luck            uint16          `json:"luck"`
+	HP              uint16          `json:"hp"`
+	MP              uint16          `json:"mp"`
+	WeaponAttack    uint16          `json:"weaponAttack"`
+	MagicAttack     uint16          `json:"magicAttack"`
+	WeaponDefense   uint16          `json:"weaponDefense"`
+	MagicDefense    uint16          `json:"magicDefense"`
+	Accuracy        uint16          `json:"accuracy"`
+	Avoidability    uint16          `json:"avoidability"`
+	Speed           uint16          `json:"speed"`
+	Jump            uint16          `json:"jump"`
+	Slots           uint16          `json:"slots"`
+	Cash            bool            `json:"cash"`
+	ReqLevel        byte            `json:"reqLevel"`
+	ReqJob          uint16          `json:"reqJob"`
+	ReqStrength     uint16          `json:"reqStrength"`
+	ReqDexterity    uint16          `json:"reqDexterity"`
+	ReqIntelligence uint16          `json:"reqIntelligence"`
+	ReqLuck         uint16          `json:"reqLuck"`
+	EquipSlots      []SlotRestModel `json:"-"`
 }
 
 func (r RestModel) GetName() string {
@@ -146,5 +152,13 @@ func Extract(m RestModel) (Model, error) {
 		jump:          m.Jump,
 		slots:         m.Slots,
 		cash:          m.Cash,
+		requirements: Requirements{
+			level:        m.ReqLevel,
+			job:          m.ReqJob,
+			strength:     m.ReqStrength,
+			dexterity:    m.ReqDexterity,
+			intelligence: m.ReqIntelligence,
+			luck:         m.ReqLuck,
+		},
 	}, nil
 }
@@ -0,0 +1,60 @@
+package set
+
+// Model is a set of equipment which grants bonuses when several of its items are worn together.
+type Model struct {
+	id      uint32
+	name    string
+	itemIds []uint32
+	bonuses []Bonus
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) Name() string {
+	return m.name
+}
+
+func (m Model) ItemIds() []uint32 {
+	return m.itemIds
+}
+
+func (m Model) Bonuses() []Bonus {
+	return m.bonuses
+}
+
+// Bonus is granted while at least Count items of the set are worn.
+type Bonus struct {
+	count         uint32
+	strength      uint16
+	dexterity     uint16
+	intelligence  uint16
+	luck          uint16
+	hp            uint16
+	mp            uint16
+	weaponAttack  uint16
+	magicAttack   uint16
+	weaponDefense uint16
+	magicDefense  uint16
+	accuracy      uint16
+	avoidability  uint16
+	speed         uint16
+	jump          uint16
+}
+
+func (b Bonus) Count() uint32         { return b.count }
+func (b Bonus) Strength() uint16      { return b.strength }
+func (b Bonus) Dexterity() uint16     { return b.dexterity }
+func (b Bonus) Intelligence() uint16  { return b.intelligence }
+func (b Bonus) Luck() uint16          { return b.luck }
+func (b Bonus) HP() uint16            { return b.hp }
+func (b Bonus) MP() uint16            { return b.mp }
+func (b Bonus) WeaponAttack() uint16  { return b.weaponAttack }
+func (b Bonus) MagicAttack() uint16   { return b.magicAttack }
+func (b Bonus) WeaponDefense() uint16 { return b.weaponDefense }
+func (b Bonus) MagicDefense() uint16  { return b.magicDefense }
+func (b Bonus) Accuracy() uint16      { return b.accuracy }
+func (b Bonus) Avoidability() uint16  { return b.avoidability }
+func (b Bonus) Speed() uint16         { return b.speed }
+func (b Bonus) Jump() uint16          { return b.jump }
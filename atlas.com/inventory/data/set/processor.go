@@ -0,0 +1,32 @@
+package set
+
+import (
+	"atlas-inventory/data/cache"
+	"context"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor struct {
+	l     logrus.FieldLogger
+	ctx   context.Context
+	cache *cache.Cache
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) *Processor {
+	p := &Processor{
+		l:     l,
+		ctx:   ctx,
+		cache: cache.GetCache(),
+	}
+	return p
+}
+
+func (p *Processor) AllProvider() model.Provider[[]Model] {
+	return cache.Provider[[]Model](p.cache, p.ctx, "sets", 0)(requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestAll(), Extract, model.Filters[Model]()))
+}
+
+func (p *Processor) GetAll() ([]Model, error) {
+	return p.AllProvider()()
+}
@@ -0,0 +1,14 @@
+package set
+
+import (
+	"atlas-inventory/data/source"
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	Resource = "data/sets"
+)
+
+func requestAll() requests.Request[[]RestModel] {
+	return source.For[[]RestModel]().Get(Resource)
+}
@@ -0,0 +1,75 @@
+package set
+
+import "strconv"
+
+type RestModel struct {
+	Id      uint32           `json:"-"`
+	Name    string           `json:"name"`
+	ItemIds []uint32         `json:"itemIds"`
+	Bonuses []BonusRestModel `json:"bonuses"`
+}
+
+// BonusRestModel is the effect of wearing Count items of a set.
+type BonusRestModel struct {
+	Count         uint32 `json:"count"`
+	Strength      uint16 `json:"strength"`
+	Dexterity     uint16 `json:"dexterity"`
+	Intelligence  uint16 `json:"intelligence"`
+	Luck          uint16 `json:"luck"`
+	HP            uint16 `json:"hp"`
+	MP            uint16 `json:"mp"`
+	WeaponAttack  uint16 `json:"weaponAttack"`
+	MagicAttack   uint16 `json:"magicAttack"`
+	WeaponDefense uint16 `json:"weaponDefense"`
+	MagicDefense  uint16 `json:"magicDefense"`
+	Accuracy      uint16 `json:"accuracy"`
+	Avoidability  uint16 `json:"avoidability"`
+	Speed         uint16 `json:"speed"`
+	Jump          uint16 `json:"jump"`
+}
+
+func (r RestModel) GetName() string {
+	return "sets"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Extract(m RestModel) (Model, error) {
+	bs := make([]Bonus, 0, len(m.Bonuses))
+	for _, b := range m.Bonuses {
+		bs = append(bs, Bonus{
+			count:         b.Count,
+			strength:      b.Strength,
+			dexterity:     b.Dexterity,
+			intelligence:  b.Intelligence,
+			luck:          b.Luck,
+			hp:            b.HP,
+			mp:            b.MP,
+			weaponAttack:  b.WeaponAttack,
+			magicAttack:   b.MagicAttack,
+			weaponDefense: b.WeaponDefense,
+			magicDefense:  b.MagicDefense,
+			accuracy:      b.Accuracy,
+			avoidability:  b.Avoidability,
+			speed:         b.Speed,
+			jump:          b.Jump,
+		})
+	}
+	return Model{
+		id:      m.Id,
+		name:    m.Name,
+		itemIds: m.ItemIds,
+		bonuses: bs,
+	}, nil
+}
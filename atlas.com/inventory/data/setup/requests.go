@@ -1,7 +1,7 @@
 package setup
 
 import (
-	"atlas-inventory/rest"
+	"atlas-inventory/data/source"
 	"fmt"
 	"github.com/Chronicle20/atlas-rest/requests"
 )
@@ -11,10 +11,6 @@ const (
 	itemInformationById     = itemInformationResource + "%d"
 )
 
-func getBaseRequest() string {
-	return requests.RootUrl("DATA")
-}
-
 func requestById(id uint32) requests.Request[RestModel] {
-	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+itemInformationById, id))
+	return source.For[RestModel]().Get(fmt.Sprintf(itemInformationById, id))
 }
@@ -0,0 +1,303 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Chronicle20/atlas-rest/requests"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+)
+
+type fileSource[A any] struct {
+	directory string
+}
+
+// NewFileSource reads item data from JSON:API documents exported from the data service into directory. A resource is
+// read from the file named after it, so data/setups/4030000 is read from data/setups/4030000.json. Without that file,
+// the resource is found by id in its collection, data/setups.json, and a relationship such as
+// data/equipment/1302000/slots is read from the resources included with data/equipment/1302000. Collections are
+// filtered by filter[ids] and by filter[<attribute>] as the data service would. When the directory holds a
+// <region>/<major>.<minor> directory for the tenant, that is read instead.
+func NewFileSource[A any](directory string) Source[A] {
+	return fileSource[A]{directory: directory}
+}
+
+func (s fileSource[A]) Get(resource string) requests.Request[A] {
+	return func(l logrus.FieldLogger, ctx context.Context) (A, error) {
+		var result A
+		u, err := url.Parse(resource)
+		if err != nil {
+			return result, err
+		}
+		body, err := read(s.root(ctx), strings.Trim(u.Path, "/"), u.Query())
+		if err != nil {
+			if !errors.Is(err, requests.ErrNotFound) {
+				l.WithError(err).Errorf("Unable to read item data [%s] from [%s].", resource, s.directory)
+			}
+			return result, err
+		}
+		err = jsonapi.Unmarshal(body, &result)
+		if err != nil {
+			l.WithError(err).Errorf("Unable to unmarshal item data [%s] from [%s].", resource, s.directory)
+			return result, err
+		}
+		return result, nil
+	}
+}
+
+func (s fileSource[A]) root(ctx context.Context) string {
+	t, err := tenant.FromContext(ctx)()
+	if err != nil {
+		return s.directory
+	}
+	dir := filepath.Join(s.directory, t.Region(), fmt.Sprintf("%d.%d", t.MajorVersion(), t.MinorVersion()))
+	if fi, err := os.Stat(dir); err == nil && fi.IsDir() {
+		return dir
+	}
+	return s.directory
+}
+
+type identifier struct {
+	Type string `json:"type"`
+	Id   string `json:"id"`
+}
+
+type relationship struct {
+	Data json.RawMessage `json:"data"`
+}
+
+type object struct {
+	identifier
+	Attributes    map[string]json.RawMessage `json:"attributes"`
+	Relationships map[string]relationship    `json:"relationships"`
+	raw           json.RawMessage
+}
+
+type document struct {
+	objects  []object
+	single   bool
+	included map[identifier]json.RawMessage
+}
+
+var documents = make(map[string]*document)
+var documentsLock sync.Mutex
+
+// load parses the document in file, remembering it for later reads. A missing file yields a nil document.
+func load(file string) (*document, error) {
+	documentsLock.Lock()
+	defer documentsLock.Unlock()
+	if d, ok := documents[file]; ok {
+		return d, nil
+	}
+
+	body, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		documents[file] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		Data     json.RawMessage   `json:"data"`
+		Included []json.RawMessage `json:"included"`
+	}
+	err = json.Unmarshal(body, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("parsing [%s]: %w", file, err)
+	}
+
+	d := &document{included: make(map[identifier]json.RawMessage)}
+	var elements []json.RawMessage
+	if strings.HasPrefix(strings.TrimSpace(string(raw.Data)), "[") {
+		err = json.Unmarshal(raw.Data, &elements)
+		if err != nil {
+			return nil, fmt.Errorf("parsing [%s]: %w", file, err)
+		}
+	} else {
+		d.single = true
+		elements = append(elements, raw.Data)
+	}
+	for _, e := range elements {
+		o := object{raw: e}
+		err = json.Unmarshal(e, &o)
+		if err != nil {
+			return nil, fmt.Errorf("parsing [%s]: %w", file, err)
+		}
+		d.objects = append(d.objects, o)
+	}
+	for _, e := range raw.Included {
+		var i identifier
+		err = json.Unmarshal(e, &i)
+		if err != nil {
+			return nil, fmt.Errorf("parsing [%s]: %w", file, err)
+		}
+		d.included[i] = e
+	}
+	documents[file] = d
+	return d, nil
+}
+
+// read renders the document for the resource at p, relative to root.
+func read(root string, p string, query url.Values) ([]byte, error) {
+	d, err := load(filepath.Join(root, filepath.FromSlash(p)+".json"))
+	if err != nil {
+		return nil, err
+	}
+	if d != nil {
+		if d.single {
+			return render(d, d.objects[0].raw, d.objects)
+		}
+		matched := filter(d.objects, query)
+		return render(d, objectsData(matched), matched)
+	}
+
+	parent, last := path.Split(p)
+	parent = strings.TrimSuffix(parent, "/")
+	if parent == "" {
+		return nil, requests.ErrNotFound
+	}
+	if _, err = strconv.ParseUint(last, 10, 32); err == nil {
+		d, err = load(filepath.Join(root, filepath.FromSlash(parent)+".json"))
+		if err != nil {
+			return nil, err
+		}
+		if d == nil || d.single {
+			return nil, requests.ErrNotFound
+		}
+		for _, o := range d.objects {
+			if o.Id == last {
+				return render(d, o.raw, []object{o})
+			}
+		}
+		return nil, requests.ErrNotFound
+	}
+
+	// A relationship of the parent resource, served from what was included with it.
+	owner, err := read(root, parent, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var od struct {
+		Data     object            `json:"data"`
+		Included []json.RawMessage `json:"included"`
+	}
+	err = json.Unmarshal(owner, &od)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := od.Data.Relationships[last]
+	if !ok {
+		return nil, requests.ErrNotFound
+	}
+	included := make(map[identifier]json.RawMessage)
+	for _, e := range od.Included {
+		var i identifier
+		err = json.Unmarshal(e, &i)
+		if err != nil {
+			return nil, err
+		}
+		included[i] = e
+	}
+	elements := make([]json.RawMessage, 0)
+	for _, i := range identifiers(r) {
+		if e, ok := included[i]; ok {
+			elements = append(elements, e)
+		}
+	}
+	return json.Marshal(struct {
+		Data []json.RawMessage `json:"data"`
+	}{Data: elements})
+}
+
+// filter keeps the objects matching the query's filters. filter[ids] matches a comma separated list of ids, and any
+// other filter matches the attribute of that name.
+func filter(objects []object, query url.Values) []object {
+	results := make([]object, 0, len(objects))
+	for _, o := range objects {
+		if matches(o, query) {
+			results = append(results, o)
+		}
+	}
+	return results
+}
+
+func matches(o object, query url.Values) bool {
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") || len(values) == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]")
+		if name == "ids" {
+			found := false
+			for _, id := range strings.Split(values[0], ",") {
+				if id == o.Id {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+			continue
+		}
+		a, ok := o.Attributes[name]
+		if !ok || strings.Trim(string(a), "\"") != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func identifiers(r relationship) []identifier {
+	var is []identifier
+	if err := json.Unmarshal(r.Data, &is); err == nil {
+		return is
+	}
+	var i identifier
+	if err := json.Unmarshal(r.Data, &i); err == nil && i.Id != "" {
+		return []identifier{i}
+	}
+	return nil
+}
+
+func objectsData(objects []object) json.RawMessage {
+	elements := make([]json.RawMessage, 0, len(objects))
+	for _, o := range objects {
+		elements = append(elements, o.raw)
+	}
+	body, _ := json.Marshal(elements)
+	return body
+}
+
+// render builds a document holding data, along with whatever d included for the objects in it.
+func render(d *document, data json.RawMessage, objects []object) ([]byte, error) {
+	included := make([]json.RawMessage, 0)
+	seen := make(map[identifier]bool)
+	for _, o := range objects {
+		for _, r := range o.Relationships {
+			for _, i := range identifiers(r) {
+				if e, ok := d.included[i]; ok && !seen[i] {
+					seen[i] = true
+					included = append(included, e)
+				}
+			}
+		}
+	}
+	return json.Marshal(struct {
+		Data     json.RawMessage   `json:"data"`
+		Included []json.RawMessage `json:"included,omitempty"`
+	}{Data: data, Included: included})
+}
@@ -0,0 +1,171 @@
+package source_test
+
+import (
+	"atlas-inventory/data/consumable"
+	"atlas-inventory/data/equipment/slot"
+	"atlas-inventory/data/equipment/statistics"
+	"atlas-inventory/data/etc"
+	"atlas-inventory/data/setup"
+	"atlas-inventory/data/source"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Chronicle20/atlas-rest/requests"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+func testContext(region string, majorVersion uint16) context.Context {
+	t, _ := tenant.Create(uuid.New(), region, majorVersion, 1)
+	return tenant.WithContext(context.Background(), t)
+}
+
+func writeFile(t *testing.T, name string, body []byte) {
+	err := os.MkdirAll(filepath.Dir(name), 0755)
+	if err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	err = os.WriteFile(name, body, 0644)
+	if err != nil {
+		t.Fatalf("Failed to write [%s]: %v", name, err)
+	}
+}
+
+func writeDocument(t *testing.T, name string, data interface{}) {
+	body, err := jsonapi.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal [%s]: %v", name, err)
+	}
+	writeFile(t, name, body)
+}
+
+// testExport writes an export holding setups, a single etc, equipment with their slots and consumables, along with
+// setups priced differently for GMS v83.
+func testExport(t *testing.T) string {
+	dir := t.TempDir()
+	writeDocument(t, filepath.Join(dir, "data", "setups.json"), []setup.RestModel{{Id: 3010000, Price: 1, SlotMax: 1}, {Id: 3010001, Price: 2, SlotMax: 1}})
+	writeDocument(t, filepath.Join(dir, "GMS", "83.1", "data", "setups.json"), []setup.RestModel{{Id: 3010000, Price: 83, SlotMax: 1}})
+	writeDocument(t, filepath.Join(dir, "data", "etcs", "4000000.json"), etc.RestModel{Id: 4000000, Price: 5, SlotMax: 200})
+	writeDocument(t, filepath.Join(dir, "data", "equipment.json"), []statistics.RestModel{
+		{Id: 1302000, Strength: 3, EquipSlots: []statistics.SlotRestModel{{Id: "weapon", Name: "weapon", WZ: "Wp", Slot: -11}}},
+		{Id: 1040002, Dexterity: 2, EquipSlots: []statistics.SlotRestModel{{Id: "overall", Name: "overall", WZ: "Ma", Slot: -5}}},
+	})
+	writeFile(t, filepath.Join(dir, "data", "consumables.json"), []byte(`{"data":[
+		{"type":"consumables","id":"2000000","attributes":{"slotMax":100,"rechargeable":false}},
+		{"type":"consumables","id":"2070000","attributes":{"slotMax":500,"rechargeable":true}}
+	]}`))
+	return dir
+}
+
+// TestFileSourceReadsResources verifies resources are read from their own file, found by id in their collection, or
+// read from what was included with the resource they belong to.
+func TestFileSourceReadsResources(t *testing.T) {
+	dir := testExport(t)
+	l := testLogger()
+	ctx := testContext("JMS", 185)
+
+	s, err := source.NewFileSource[setup.RestModel](dir).Get("data/setups/3010001")(l, ctx)
+	if err != nil || s.Id != 3010001 || s.Price != 2 {
+		t.Fatalf("Unexpected setup [%+v] and error [%v].", s, err)
+	}
+	e, err := source.NewFileSource[etc.RestModel](dir).Get("data/etcs/4000000")(l, ctx)
+	if err != nil || e.Id != 4000000 || e.SlotMax != 200 {
+		t.Fatalf("Unexpected etc [%+v] and error [%v].", e, err)
+	}
+	st, err := source.NewFileSource[statistics.RestModel](dir).Get("data/equipment/1302000")(l, ctx)
+	if err != nil || st.Strength != 3 || len(st.EquipSlots) != 1 || st.EquipSlots[0].Slot != -11 {
+		t.Fatalf("Unexpected statistics [%+v] and error [%v].", st, err)
+	}
+	ss, err := source.NewFileSource[[]slot.RestModel](dir).Get("data/equipment/1040002/slots")(l, ctx)
+	if err != nil || len(ss) != 1 || ss[0].Name != "overall" || ss[0].Slot != -5 {
+		t.Fatalf("Unexpected slots [%+v] and error [%v].", ss, err)
+	}
+
+	_, err = source.NewFileSource[setup.RestModel](dir).Get("data/setups/3019999")(l, ctx)
+	if !errors.Is(err, requests.ErrNotFound) {
+		t.Fatalf("Expected a missing setup to be not found, got [%v].", err)
+	}
+	_, err = source.NewFileSource[etc.RestModel](dir).Get("data/etcs/4000001")(l, ctx)
+	if !errors.Is(err, requests.ErrNotFound) {
+		t.Fatalf("Expected a missing etc to be not found, got [%v].", err)
+	}
+}
+
+// TestFileSourceFiltersCollections verifies collections are filtered by ids and by attribute.
+func TestFileSourceFiltersCollections(t *testing.T) {
+	dir := testExport(t)
+	l := testLogger()
+	ctx := testContext("JMS", 185)
+
+	cs, err := source.NewFileSource[[]consumable.RestModel](dir).Get(consumable.Rechargeable)(l, ctx)
+	if err != nil || len(cs) != 1 || cs[0].Id != 2070000 || cs[0].SlotMax != 500 {
+		t.Fatalf("Unexpected rechargeable consumables [%+v] and error [%v].", cs, err)
+	}
+	sts, err := source.NewFileSource[[]statistics.RestModel](dir).Get("data/equipment?filter[ids]=1040002,1302000,1999999")(l, ctx)
+	if err != nil || len(sts) != 2 {
+		t.Fatalf("Unexpected statistics [%+v] and error [%v].", sts, err)
+	}
+}
+
+// TestFileSourcePrefersTenantDirectory verifies a tenant's region and version directory is read ahead of the shared
+// export, which still serves tenants without one.
+func TestFileSourcePrefersTenantDirectory(t *testing.T) {
+	dir := testExport(t)
+	l := testLogger()
+
+	s, err := source.NewFileSource[setup.RestModel](dir).Get("data/setups/3010000")(l, testContext("GMS", 83))
+	if err != nil || s.Price != 83 {
+		t.Fatalf("Unexpected setup [%+v] and error [%v].", s, err)
+	}
+	s, err = source.NewFileSource[setup.RestModel](dir).Get("data/setups/3010000")(l, testContext("GMS", 95))
+	if err != nil || s.Price != 1 {
+		t.Fatalf("Unexpected setup [%+v] and error [%v].", s, err)
+	}
+	_, err = source.NewFileSource[setup.RestModel](dir).Get("data/setups/3010001")(l, testContext("GMS", 83))
+	if !errors.Is(err, requests.ErrNotFound) {
+		t.Fatalf("Expected a setup missing from the tenant's export to be not found, got [%v].", err)
+	}
+}
+
+// TestDirectoryRequiresExistingDirectory verifies the file source is never pointed at the working directory by an
+// unset, missing or misnamed DATA_DIRECTORY, and that a relative one is made absolute.
+func TestDirectoryRequiresExistingDirectory(t *testing.T) {
+	if _, err := source.Directory(""); !errors.Is(err, source.ErrNoDirectory) {
+		t.Fatalf("Expected an unset directory to be refused, got [%v].", err)
+	}
+	root := t.TempDir()
+	if _, err := source.Directory(filepath.Join(root, "missing")); err == nil {
+		t.Fatalf("Expected a missing directory to be refused.")
+	}
+	file := filepath.Join(root, "file.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := source.Directory(file); err == nil {
+		t.Fatalf("Expected a file to be refused.")
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	rel, err := filepath.Rel(wd, root)
+	if err != nil {
+		t.Skipf("Temporary directory is not reachable from the working directory: %v", err)
+	}
+	dir, err := source.Directory(rel)
+	if err != nil || dir != root {
+		t.Fatalf("Expected [%s] to resolve to [%s], got [%s] and [%v].", rel, root, dir, err)
+	}
+}
@@ -0,0 +1,97 @@
+package source
+
+import (
+	"atlas-inventory/rest"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Chronicle20/atlas-rest/requests"
+)
+
+const (
+	EnvSource    = "DATA_SOURCE"
+	EnvDirectory = "DATA_DIRECTORY"
+	SourceRest   = "rest"
+	SourceFile   = "file"
+)
+
+// Source reads item data, addressed by its resource on the data service, such as data/setups/4030000.
+type Source[A any] interface {
+	Get(resource string) requests.Request[A]
+}
+
+type restSource[A any] struct {
+}
+
+// NewRestSource reads item data from the data service.
+func NewRestSource[A any]() Source[A] {
+	return restSource[A]{}
+}
+
+func (s restSource[A]) Get(resource string) requests.Request[A] {
+	return rest.MakeGetRequest[A](requests.RootUrl("DATA") + resource)
+}
+
+type configuration struct {
+	source    string
+	directory string
+}
+
+var config configuration
+var once sync.Once
+
+func getConfiguration() configuration {
+	once.Do(func() {
+		config = configuration{source: SourceRest, directory: os.Getenv(EnvDirectory)}
+		if os.Getenv(EnvSource) == SourceFile {
+			config.source = SourceFile
+		}
+		if dir, err := Directory(config.directory); err == nil {
+			config.directory = dir
+		}
+	})
+	return config
+}
+
+// ErrNoDirectory is returned for the file source when DATA_DIRECTORY is not set.
+var ErrNoDirectory = errors.New("no item data directory is configured")
+
+// Directory resolves dir to the absolute path of the directory the file source reads, failing unless it names an
+// existing directory. An empty dir is an error, rather than the working directory.
+func Directory(dir string) (string, error) {
+	if dir == "" {
+		return "", ErrNoDirectory
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(abs)
+	if err != nil {
+		return "", err
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("[%s] is not a directory", abs)
+	}
+	return abs, nil
+}
+
+// Name reports the source named by DATA_SOURCE, and the directory read when it is the file source, made absolute where
+// it exists.
+func Name() (string, string) {
+	c := getConfiguration()
+	return c.source, c.directory
+}
+
+// For returns the source named by DATA_SOURCE. The data service is used unless the file source is requested, in which
+// case item data is read from DATA_DIRECTORY.
+func For[A any]() Source[A] {
+	c := getConfiguration()
+	if c.source == SourceFile {
+		return NewFileSource[A](c.directory)
+	}
+	return NewRestSource[A]()
+}
@@ -60,17 +60,26 @@ func (d *DSNBuilder) Build() string {
 
 type Configuration struct {
 	dsn        string
-	migrations []Migrator
+	migrations []Migration
+	migrate    bool
 }
 
 type Configurator func(c *Configuration)
 
-func SetMigrations(migrations ...Migrator) Configurator {
+func SetMigrations(migrations ...Migration) Configurator {
 	return func(c *Configuration) {
 		c.migrations = migrations
 	}
 }
 
+// SetMigrateOnStartup chooses whether pending migrations are applied on connecting. When they are not, connecting
+// fails if any are pending.
+func SetMigrateOnStartup(migrate bool) Configurator {
+	return func(c *Configuration) {
+		c.migrate = migrate
+	}
+}
+
 type Migrator func(db *gorm.DB) error
 
 func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
@@ -105,7 +114,8 @@ func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
 
 	c := &Configuration{
 		dsn:        dsnBuilder.Build(),
-		migrations: make([]Migrator, 0),
+		migrations: make([]Migration, 0),
+		migrate:    true,
 	}
 	for _, configurator := range configurators {
 		configurator(c)
@@ -114,7 +124,7 @@ func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
 	var db *gorm.DB
 	tryToConnect := func(attempt int) (bool, error) {
 		var err error
-		db, err = gorm.Open(postgres.Open(dsnBuilder.Build()), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dsnBuilder.Build()), &gorm.Config{TranslateError: true})
 		if err != nil {
 			return true, err
 		}
@@ -127,11 +137,22 @@ func Connect(l logrus.FieldLogger, configurators ...Configurator) *gorm.DB {
 	}
 
 	// Migrate the schema
-	for _, m := range c.migrations {
-		err = m(db)
+	if len(c.migrations) == 0 {
+		return db
+	}
+	if c.migrate {
+		err = Migrate(l, db, c.migrations)
 		if err != nil {
 			l.WithError(err).Fatalf("Migrating schema.")
 		}
+		return db
+	}
+	pending, err := Pending(db, c.migrations)
+	if err != nil {
+		l.WithError(err).Fatalf("Verifying schema version.")
+	}
+	if len(pending) > 0 {
+		l.Fatalf("Schema is missing [%d] migrations, starting with [%d] [%s]. Apply them with the migrate command.", len(pending), pending[0].Version, pending[0].Name)
 	}
 	return db
 }
@@ -0,0 +1,190 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// migrationLockId identifies the advisory lock held while migrating, so that replicas started together migrate one at
+// a time.
+const migrationLockId = 7_104_116_108_097
+
+// Migration is one versioned step of the schema. Down reverses Up.
+type Migration struct {
+	Version uint32
+	Name    string
+	Up      Migrator
+	Down    Migrator
+}
+
+type schemaVersionEntity struct {
+	Version   uint32    `gorm:"primaryKey;autoIncrement:false;not null"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (e schemaVersionEntity) TableName() string {
+	return "schema_versions"
+}
+
+// MigrationStatus reports whether a migration has been applied, and when.
+type MigrationStatus struct {
+	Version   uint32
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrate applies, in version order, every migration not yet recorded as applied.
+func Migrate(l logrus.FieldLogger, db *gorm.DB, migrations []Migration) error {
+	ms, err := ordered(migrations)
+	if err != nil {
+		return err
+	}
+	return locked(db, func(tx *gorm.DB) error {
+		applied, err := appliedVersions(tx)
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			if _, ok := applied[m.Version]; ok {
+				continue
+			}
+			l.Infof("Applying migration [%d] [%s].", m.Version, m.Name)
+			err = m.Up(tx)
+			if err != nil {
+				return fmt.Errorf("applying migration [%d] [%s]: %w", m.Version, m.Name, err)
+			}
+			err = tx.Create(&schemaVersionEntity{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback reverses the latest steps applied migrations, most recent first.
+func Rollback(l logrus.FieldLogger, db *gorm.DB, migrations []Migration, steps int) error {
+	ms, err := ordered(migrations)
+	if err != nil {
+		return err
+	}
+	return locked(db, func(tx *gorm.DB) error {
+		applied, err := appliedVersions(tx)
+		if err != nil {
+			return err
+		}
+		for i := len(ms) - 1; i >= 0 && steps > 0; i-- {
+			m := ms[i]
+			if _, ok := applied[m.Version]; !ok {
+				continue
+			}
+			if m.Down == nil {
+				return fmt.Errorf("migration [%d] [%s] cannot be rolled back", m.Version, m.Name)
+			}
+			l.Infof("Rolling back migration [%d] [%s].", m.Version, m.Name)
+			err = m.Down(tx)
+			if err != nil {
+				return fmt.Errorf("rolling back migration [%d] [%s]: %w", m.Version, m.Name, err)
+			}
+			err = tx.Delete(&schemaVersionEntity{Version: m.Version}).Error
+			if err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status reports each migration in version order, and whether it has been applied.
+func Status(db *gorm.DB, migrations []Migration) ([]MigrationStatus, error) {
+	ms, err := ordered(migrations)
+	if err != nil {
+		return nil, err
+	}
+	applied := make(map[uint32]schemaVersionEntity)
+	if db.Migrator().HasTable(&schemaVersionEntity{}) {
+		applied, err = appliedVersions(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+	results := make([]MigrationStatus, 0, len(ms))
+	for _, m := range ms {
+		s := MigrationStatus{Version: m.Version, Name: m.Name}
+		if e, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = e.AppliedAt
+		}
+		results = append(results, s)
+	}
+	return results, nil
+}
+
+// Pending reports the migrations not yet applied.
+func Pending(db *gorm.DB, migrations []Migration) ([]MigrationStatus, error) {
+	ss, err := Status(db, migrations)
+	if err != nil {
+		return nil, err
+	}
+	var results []MigrationStatus
+	for _, s := range ss {
+		if !s.Applied {
+			results = append(results, s)
+		}
+	}
+	return results, nil
+}
+
+func ordered(migrations []Migration) ([]Migration, error) {
+	ms := make([]Migration, len(migrations))
+	copy(ms, migrations)
+	sort.Slice(ms, func(i, j int) bool { return ms[i].Version < ms[j].Version })
+	for i, m := range ms {
+		if m.Up == nil {
+			return nil, fmt.Errorf("migration [%d] [%s] has no up step", m.Version, m.Name)
+		}
+		if i > 0 && ms[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration version [%d] is registered more than once", m.Version)
+		}
+	}
+	return ms, nil
+}
+
+// locked runs f in a single transaction, holding the migration lock for its duration. Schema changes are
+// transactional in PostgreSQL, so a failed run leaves the schema as it was. Other databases are not locked.
+func locked(db *gorm.DB, f func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockId).Error
+			if err != nil {
+				return err
+			}
+		}
+		err := tx.AutoMigrate(&schemaVersionEntity{})
+		if err != nil {
+			return err
+		}
+		return f(tx)
+	})
+}
+
+func appliedVersions(db *gorm.DB) (map[uint32]schemaVersionEntity, error) {
+	var es []schemaVersionEntity
+	err := db.Find(&es).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	results := make(map[uint32]schemaVersionEntity)
+	for _, e := range es {
+		results[e.Version] = e
+	}
+	return results, nil
+}
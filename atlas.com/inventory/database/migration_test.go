@@ -0,0 +1,128 @@
+package database_test
+
+import (
+	"atlas-inventory/database"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type widgetEntity struct {
+	Id   uint32 `gorm:"primaryKey;autoIncrement;not null"`
+	Name string `gorm:"not null"`
+}
+
+func (e widgetEntity) TableName() string {
+	return "widgets"
+}
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+func testMigrations() []database.Migration {
+	return []database.Migration{
+		{
+			Version: 2,
+			Name:    "add_widget_color",
+			Up: func(db *gorm.DB) error {
+				return db.Exec("ALTER TABLE widgets ADD COLUMN color TEXT NOT NULL DEFAULT ''").Error
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Exec("ALTER TABLE widgets DROP COLUMN color").Error
+			},
+		},
+		{
+			Version: 1,
+			Name:    "create_widgets",
+			Up: func(db *gorm.DB) error {
+				return db.AutoMigrate(&widgetEntity{})
+			},
+			Down: func(db *gorm.DB) error {
+				return db.Migrator().DropTable(&widgetEntity{})
+			},
+		},
+	}
+}
+
+// TestMigrateAndRollback verifies migrations apply in version order exactly once, and roll back most recent first.
+func TestMigrateAndRollback(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	ms := testMigrations()
+
+	pending, err := database.Pending(db, ms)
+	if err != nil {
+		t.Fatalf("Failed to read pending migrations: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Version != 1 {
+		t.Fatalf("Expected both migrations to be pending in version order, got [%+v].", pending)
+	}
+
+	if err = database.Migrate(l, db, ms); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	if err = database.Migrate(l, db, ms); err != nil {
+		t.Fatalf("Expected a second migration to apply nothing: %v", err)
+	}
+	if !db.Migrator().HasColumn(&widgetEntity{}, "color") {
+		t.Fatalf("Expected the widgets table to have a color column.")
+	}
+
+	if err = database.Rollback(l, db, ms, 1); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if db.Migrator().HasColumn(&widgetEntity{}, "color") || !db.Migrator().HasTable(&widgetEntity{}) {
+		t.Fatalf("Expected only the color column to be rolled back.")
+	}
+	ss, err := database.Status(db, ms)
+	if err != nil {
+		t.Fatalf("Failed to read migration status: %v", err)
+	}
+	if !ss[0].Applied || ss[1].Applied {
+		t.Fatalf("Expected only the first migration to remain applied, got [%+v].", ss)
+	}
+
+	if err = database.Rollback(l, db, ms, 5); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if db.Migrator().HasTable(&widgetEntity{}) {
+		t.Fatalf("Expected the widgets table to be dropped.")
+	}
+}
+
+// TestMigrateFailureLeavesVersionUnrecorded verifies a failing migration is not recorded as applied.
+func TestMigrateFailureLeavesVersionUnrecorded(t *testing.T) {
+	l := testLogger()
+	db := testDatabase(t)
+	ms := []database.Migration{{
+		Version: 1,
+		Name:    "broken",
+		Up: func(db *gorm.DB) error {
+			return db.Exec("ALTER TABLE missing ADD COLUMN color TEXT").Error
+		},
+	}}
+
+	if err := database.Migrate(l, db, ms); err == nil {
+		t.Fatalf("Expected the migration to fail.")
+	}
+	pending, err := database.Pending(db, ms)
+	if err != nil {
+		t.Fatalf("Failed to read pending migrations: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Expected the failed migration to remain pending.")
+	}
+}
@@ -1,11 +1,23 @@
 package database
 
 import (
+	"sync"
+
 	"gorm.io/gorm"
 )
 
+// hooks collects work to run once the outermost transaction has finished.
+type hooks struct {
+	commit   []func()
+	rollback []func()
+}
+
+// pending maps the connection of each transaction started by ExecuteTransaction to its hooks.
+var pending sync.Map
+
 // ExecuteTransaction runs the given function within a transaction.
 // If the provided *gorm.DB is already in a transaction, it will just run the function without starting a new one.
+// Once the transaction it started commits or rolls back, the matching AfterCommit or AfterRollback functions run.
 func ExecuteTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
 	if isTransaction(db) {
 		// Already in a transaction, execute directly
@@ -13,10 +25,59 @@ func ExecuteTransaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
 	}
 
 	// Not in a transaction, start a new one
-	return db.Transaction(fn)
+	h := &hooks{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		pending.Store(tx.Statement.ConnPool, h)
+		defer pending.Delete(tx.Statement.ConnPool)
+		return fn(tx)
+	})
+	fs := h.commit
+	if err != nil {
+		fs = h.rollback
+	}
+	for i := len(fs) - 1; i >= 0; i-- {
+		fs[i]()
+	}
+	return err
+}
+
+// AfterCommit defers f until the transaction tx belongs to commits, dropping it if the transaction rolls back.
+// Outside a transaction started by ExecuteTransaction, f runs immediately.
+func AfterCommit(tx *gorm.DB, f func()) {
+	h, ok := hooksOf(tx)
+	if !ok {
+		f()
+		return
+	}
+	h.commit = append(h.commit, f)
+}
+
+// AfterRollback defers f until the transaction tx belongs to rolls back, dropping it if the transaction commits.
+// Outside a transaction started by ExecuteTransaction, f never runs.
+func AfterRollback(tx *gorm.DB, f func()) {
+	h, ok := hooksOf(tx)
+	if !ok {
+		return
+	}
+	h.rollback = append(h.rollback, f)
+}
+
+func hooksOf(tx *gorm.DB) (*hooks, bool) {
+	if !isTransaction(tx) {
+		return nil, false
+	}
+	v, ok := pending.Load(tx.Statement.ConnPool)
+	if !ok {
+		return nil, false
+	}
+	return v.(*hooks), true
 }
 
 // isTransaction checks if the *gorm.DB is already in a transaction
 func isTransaction(db *gorm.DB) bool {
-	return db.Statement != nil && db.Statement.ConnPool != nil
+	if db.Statement == nil || db.Statement.ConnPool == nil {
+		return false
+	}
+	_, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok
 }
@@ -0,0 +1,112 @@
+package database_test
+
+import (
+	"atlas-inventory/database"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// TestExecuteTransaction verifies that a connection not already in a transaction gets one, so a failure undoes every
+// write made through it, and that a nested call joins the enclosing transaction rather than committing on its own.
+func TestExecuteTransaction(t *testing.T) {
+	db := testDatabase(t)
+	if err := db.Exec("CREATE TABLE IF NOT EXISTS transaction_tests (id INTEGER)").Error; err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	abort := errors.New("abort")
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want int64
+	}{
+		{name: "rollback", err: abort, want: 0},
+		{name: "commit", want: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := db.Exec("DELETE FROM transaction_tests").Error; err != nil {
+				t.Fatalf("Failed to clear table: %v", err)
+			}
+			err := database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+				if err := tx.Exec("INSERT INTO transaction_tests (id) VALUES (1)").Error; err != nil {
+					return err
+				}
+				err := database.ExecuteTransaction(tx, func(tx *gorm.DB) error {
+					return tx.Exec("INSERT INTO transaction_tests (id) VALUES (2)").Error
+				})
+				if err != nil {
+					return err
+				}
+				return tc.err
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Unexpected transaction error: %v", err)
+			}
+			var count int64
+			if err = db.Raw("SELECT COUNT(*) FROM transaction_tests").Scan(&count).Error; err != nil {
+				t.Fatalf("Failed to count rows: %v", err)
+			}
+			if count != tc.want {
+				t.Fatalf("Expected [%d] rows, got [%d].", tc.want, count)
+			}
+		})
+	}
+}
+
+// TestAfterCommitAndRollback verifies that deferred work runs, newest first, only once the outermost transaction has
+// committed or rolled back, and only for the outcome it was registered for.
+func TestAfterCommitAndRollback(t *testing.T) {
+	db := testDatabase(t)
+	abort := errors.New("abort")
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "commit", want: "commit 2,commit 1"},
+		{name: "rollback", err: abort, want: "rollback 2,rollback 1"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var ran []string
+			err := database.ExecuteTransaction(db, func(tx *gorm.DB) error {
+				database.AfterCommit(tx, func() { ran = append(ran, "commit 1") })
+				database.AfterRollback(tx, func() { ran = append(ran, "rollback 1") })
+				err := database.ExecuteTransaction(tx, func(tx *gorm.DB) error {
+					database.AfterCommit(tx, func() { ran = append(ran, "commit 2") })
+					database.AfterRollback(tx, func() { ran = append(ran, "rollback 2") })
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				if len(ran) != 0 {
+					t.Errorf("Ran %v before the transaction finished.", ran)
+				}
+				return tc.err
+			})
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Unexpected transaction error: %v", err)
+			}
+			if got := strings.Join(ran, ","); got != tc.want {
+				t.Fatalf("Ran [%s], want [%s].", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestAfterCommitOutsideTransaction verifies that work deferred outside a transaction runs at once, and that there is
+// nothing to roll back.
+func TestAfterCommitOutsideTransaction(t *testing.T) {
+	db := testDatabase(t)
+
+	var ran []string
+	database.AfterCommit(db, func() { ran = append(ran, "commit") })
+	database.AfterRollback(db, func() { ran = append(ran, "rollback") })
+	if got := strings.Join(ran, ","); got != "commit" {
+		t.Fatalf("Ran [%s], want [commit].", got)
+	}
+}
@@ -28,6 +28,19 @@ func (p *Processor) GetById(equipmentId uint32) (Model, error) {
 	return p.ByEquipmentIdModelProvider(equipmentId)()
 }
 
+// ByIdsProvider yields the equipables with the given ids in one request. Ids the service does not know are omitted.
+func (p *Processor) ByIdsProvider(ids []uint32) model.Provider[[]Model] {
+	if len(ids) == 0 {
+		return model.FixedProvider[[]Model](nil)
+	}
+	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByIds(ids), Extract, model.Filters[Model]())
+}
+
+// AllProvider yields every equipable the equipables service holds for the tenant, whether or not an asset refers to it.
+func (p *Processor) AllProvider() model.Provider[[]Model] {
+	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestAll(), Extract, model.Filters[Model]())
+}
+
 func (p *Processor) Delete(equipmentId uint32) error {
 	return deleteById(equipmentId)(p.l, p.ctx)
 }
@@ -10,6 +10,7 @@ import (
 const (
 	equipmentResource = "equipables"
 	equipResource     = equipmentResource + "/%d"
+	equipsByIds       = equipmentResource + "?filter[ids]=%s"
 )
 
 func getBaseRequest() string {
@@ -20,6 +21,14 @@ func requestById(equipmentId uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+equipResource, equipmentId))
 }
 
+func requestByIds(ids []uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+equipsByIds, rest.IdList(ids)))
+}
+
+func requestAll() requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](getBaseRequest() + equipmentResource)
+}
+
 func deleteById(equipmentId uint32) requests.EmptyBodyRequest {
 	return rest.MakeDeleteRequest(fmt.Sprintf(getBaseRequest()+equipResource, equipmentId))
 }
@@ -5,7 +5,9 @@ import (
 	"atlas-inventory/database"
 	"atlas-inventory/kafka/message"
 	inventory2 "atlas-inventory/kafka/message/inventory"
-	"atlas-inventory/kafka/producer"
+	"atlas-inventory/loadout"
+	"atlas-inventory/outbox"
+	"atlas-inventory/statistics"
 	"context"
 	"errors"
 	"github.com/Chronicle20/atlas-constants/inventory"
@@ -30,6 +32,9 @@ type ProcessorImpl struct {
 	ctx                  context.Context
 	db                   *gorm.DB
 	compartmentProcessor *compartment.Processor
+	loadoutProcessor     *loadout.Processor
+	statisticsProcessor  *statistics.Processor
+	outboxProcessor      *outbox.Processor
 }
 
 func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) Processor {
@@ -38,6 +43,9 @@ func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) Proces
 		ctx:                  ctx,
 		db:                   db,
 		compartmentProcessor: compartment.NewProcessor(l, ctx, db),
+		loadoutProcessor:     loadout.NewProcessor(l, ctx, db),
+		statisticsProcessor:  statistics.NewProcessor(l, ctx, db),
+		outboxProcessor:      outbox.NewProcessor(l, ctx, db),
 	}
 	return p
 }
@@ -48,6 +56,9 @@ func (p *ProcessorImpl) WithTransaction(db *gorm.DB) Processor {
 		ctx:                  p.ctx,
 		db:                   db,
 		compartmentProcessor: p.compartmentProcessor,
+		loadoutProcessor:     p.loadoutProcessor,
+		statisticsProcessor:  p.statisticsProcessor,
+		outboxProcessor:      p.outboxProcessor,
 	}
 }
 
@@ -65,7 +76,7 @@ func (p *ProcessorImpl) ByCharacterIdProvider(characterId uint32) model.Provider
 
 func (p *ProcessorImpl) CreateAndEmit(transactionId uuid.UUID, characterId uint32) (Model, error) {
 	var m Model
-	err := message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	err := message.Emit(p.outboxProcessor.Producer())(func(buf *message.Buffer) error {
 		var err error
 		m, err = p.Create(buf)(transactionId, characterId)
 		return err
@@ -77,7 +88,7 @@ func (p *ProcessorImpl) Create(mb *message.Buffer) func(transactionId uuid.UUID,
 	return func(transactionId uuid.UUID, characterId uint32) (Model, error) {
 		p.l.Debugf("Attempting to create inventory for character [%d].", characterId)
 		var i Model
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			// Check if inventory already exists for character.
 			var err error
 			i, err = p.WithTransaction(tx).GetByCharacterId(characterId)
@@ -100,7 +111,7 @@ func (p *ProcessorImpl) Create(mb *message.Buffer) func(transactionId uuid.UUID,
 			}
 			i = b.Build()
 			return mb.Put(inventory2.EnvEventTopicStatus, CreatedEventStatusProvider(characterId))
-		})
+		}))
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Unable to create inventory for character [%d].", characterId)
 			return Model{}, txErr
@@ -111,7 +122,7 @@ func (p *ProcessorImpl) Create(mb *message.Buffer) func(transactionId uuid.UUID,
 }
 
 func (p *ProcessorImpl) DeleteAndEmit(transactionId uuid.UUID, characterId uint32) error {
-	return message.Emit(producer.ProviderImpl(p.l)(p.ctx))(func(buf *message.Buffer) error {
+	return message.Emit(p.outboxProcessor.Producer())(func(buf *message.Buffer) error {
 		return p.Delete(buf)(transactionId, characterId)
 	})
 }
@@ -120,7 +131,7 @@ func (p *ProcessorImpl) Delete(mb *message.Buffer) func(transactionId uuid.UUID,
 	return func(transactionId uuid.UUID, characterId uint32) error {
 		p.l.Debugf("Attempting to delete inventory for character [%d].", characterId)
 		var i Model
-		txErr := database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		txErr := database.ExecuteTransaction(p.db, p.outboxProcessor.Staged(mb, func(tx *gorm.DB) error {
 			var err error
 			i, err = p.WithTransaction(tx).GetByCharacterId(characterId)
 			if err != nil {
@@ -132,8 +143,16 @@ func (p *ProcessorImpl) Delete(mb *message.Buffer) func(transactionId uuid.UUID,
 			if err != nil {
 				return err
 			}
+			err = p.loadoutProcessor.WithTransaction(tx).DeleteByCharacterId(characterId)
+			if err != nil {
+				return err
+			}
+			err = p.statisticsProcessor.WithTransaction(tx).DeleteByCharacterId(characterId)
+			if err != nil {
+				return err
+			}
 			return mb.Put(inventory2.EnvEventTopicStatus, DeletedEventStatusProvider(characterId))
-		})
+		}))
 		if txErr != nil {
 			p.l.WithError(txErr).Errorf("Unable to delete inventory for character [%d].", characterId)
 			return txErr
@@ -4,6 +4,7 @@ import (
 	"atlas-inventory/compartment"
 	consumer2 "atlas-inventory/kafka/consumer"
 	compartment2 "atlas-inventory/kafka/message/compartment"
+	"atlas-inventory/ledger"
 	"context"
 	"github.com/Chronicle20/atlas-constants/channel"
 	"github.com/Chronicle20/atlas-constants/inventory"
@@ -18,6 +19,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"math"
+	"time"
 )
 
 func InitConsumers(l logrus.FieldLogger) func(func(config consumer.Config, decorators ...model.Decorator[consumer.Config])) func(consumerGroupId string) {
@@ -48,6 +50,8 @@ func InitHandlers(l logrus.FieldLogger) func(db *gorm.DB) func(rf func(topic str
 			_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleSortCommand(db))))
 			_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleAcceptCommand(db))))
 			_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleReleaseCommand(db))))
+			_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleRenewReservationCommand(db))))
+			_, _ = rf(t, message.AdaptHandler(message.PersistentConfig(handleApplyLoadoutCommand(db))))
 		}
 	}
 }
@@ -57,7 +61,8 @@ func handleEquipItemCommand(db *gorm.DB) message.Handler[compartment2.Command[co
 		if c.Type != compartment2.CommandEquip {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).EquipItemAndEmit(c.TransactionId, c.CharacterId, c.Body.Source, c.Body.Destination)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.EquipCommandFailed, p.EquipItemAndEmit(c.TransactionId, c.CharacterId, c.Body.Source, c.Body.Destination))
 	}
 }
 
@@ -66,7 +71,8 @@ func handleUnequipItemCommand(db *gorm.DB) message.Handler[compartment2.Command[
 		if c.Type != compartment2.CommandUnequip {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).RemoveEquipAndEmit(c.TransactionId, c.CharacterId, c.Body.Source, c.Body.Destination)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.UnequipCommandFailed, p.RemoveEquipAndEmit(c.TransactionId, c.CharacterId, c.Body.Source, c.Body.Destination))
 	}
 }
 
@@ -75,7 +81,8 @@ func handleMoveItemCommand(db *gorm.DB) message.Handler[compartment2.Command[com
 		if c.Type != compartment2.CommandMove {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).MoveAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Source, c.Body.Destination)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.MoveCommandFailed, p.MoveAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Source, c.Body.Destination))
 	}
 }
 
@@ -84,7 +91,8 @@ func handleIncreaseCapacityCommand(db *gorm.DB) message.Handler[compartment2.Com
 		if c.Type != compartment2.CommandIncreaseCapacity {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).IncreaseCapacityAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Amount)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.IncreaseCapacityCommandFailed, p.IncreaseCapacityAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Amount))
 	}
 }
 
@@ -95,7 +103,8 @@ func handleDropItemCommand(db *gorm.DB) message.Handler[compartment2.Command[com
 		}
 
 		m := _map.NewModel(world.Id(c.Body.WorldId))(channel.Id(c.Body.ChannelId))(_map.Id(c.Body.MapId))
-		_ = compartment.NewProcessor(l, ctx, db).DropAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), m, c.Body.X, c.Body.Y, c.Body.Source, c.Body.Quantity)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.DropCommandFailed, p.DropAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), m, c.Body.X, c.Body.Y, c.Body.Source, c.Body.Quantity))
 	}
 }
 
@@ -106,10 +115,15 @@ func handleRequestReserveItemCommand(db *gorm.DB) message.Handler[compartment2.C
 		}
 		reserves := make([]compartment.ReservationRequest, 0)
 		for _, i := range c.Body.Items {
+			inventoryType := inventory.Type(c.InventoryType)
+			if i.InventoryType != 0 {
+				inventoryType = inventory.Type(i.InventoryType)
+			}
 			reserves = append(reserves, compartment.ReservationRequest{
-				Slot:     i.Source,
-				ItemId:   i.ItemId,
-				Quantity: i.Quantity,
+				InventoryType: inventoryType,
+				Slot:          i.Source,
+				ItemId:        i.ItemId,
+				Quantity:      i.Quantity,
 			})
 		}
 
@@ -118,7 +132,25 @@ func handleRequestReserveItemCommand(db *gorm.DB) message.Handler[compartment2.C
 		if transactionId == uuid.Nil {
 			transactionId = c.Body.TransactionId
 		}
-		_ = compartment.NewProcessor(l, ctx, db).RequestReserveAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), reserves)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, transactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.RequestReserveCommandFailed, p.RequestReserveAndEmit(transactionId, c.CharacterId, reserves, time.Duration(c.Body.Ttl)*time.Second))
+	}
+}
+
+func handleRenewReservationCommand(db *gorm.DB) message.Handler[compartment2.Command[compartment2.RenewReservationCommandBody]] {
+	return func(l logrus.FieldLogger, ctx context.Context, c compartment2.Command[compartment2.RenewReservationCommandBody]) {
+		if c.Type != compartment2.CommandRenewReservation {
+			return
+		}
+
+		transactionId := c.TransactionId
+		if transactionId == uuid.Nil {
+			transactionId = c.Body.TransactionId
+		}
+		// Renewing is repeated under the reservation's transaction for as long as the hold is wanted, and extending a
+		// hold twice is harmless, so renewals are not recorded in the ledger.
+		p := compartment.NewProcessor(l, ctx, db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.RenewReservationCommandFailed, p.RenewReservationAndEmit(transactionId, c.CharacterId, time.Duration(c.Body.Ttl)*time.Second))
 	}
 }
 
@@ -133,7 +165,8 @@ func handleCancelItemReservationCommand(db *gorm.DB) message.Handler[compartment
 		if transactionId == uuid.Nil {
 			transactionId = c.Body.TransactionId
 		}
-		_ = compartment.NewProcessor(l, ctx, db).CancelReservationAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, transactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.CancelReservationCommandFailed, p.CancelReservationAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot))
 	}
 }
 
@@ -148,7 +181,8 @@ func handleConsumeItemCommand(db *gorm.DB) message.Handler[compartment2.Command[
 		if transactionId == uuid.Nil {
 			transactionId = c.Body.TransactionId
 		}
-		_ = compartment.NewProcessor(l, ctx, db).ConsumeAssetAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, transactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.ConsumeCommandFailed, p.ConsumeAssetAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot))
 	}
 }
 
@@ -161,7 +195,8 @@ func handleDestroyItemCommand(db *gorm.DB) message.Handler[compartment2.Command[
 		if quantity == 0 {
 			quantity = math.MaxInt32
 		}
-		_ = compartment.NewProcessor(l, ctx, db).DestroyAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot, quantity)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.DestroyCommandFailed, p.DestroyAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot, quantity))
 	}
 }
 
@@ -170,7 +205,8 @@ func handleCreateAssetCommand(db *gorm.DB) message.Handler[compartment2.Command[
 		if c.Type != compartment2.CommandCreateAsset {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).CreateAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.TemplateId, c.Body.Quantity, c.Body.Expiration, c.Body.OwnerId, c.Body.Flag, c.Body.Rechargeable)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.CreateAssetCommandFailed, p.CreateAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.TemplateId, c.Body.Quantity, c.Body.Expiration, c.Body.OwnerId, c.Body.Flag, c.Body.Rechargeable))
 	}
 }
 
@@ -179,7 +215,8 @@ func handleRechargeItemCommand(db *gorm.DB) message.Handler[compartment2.Command
 		if c.Type != compartment2.CommandRecharge {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).RechargeAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot, c.Body.Quantity)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.RechargeCommandFailed, p.RechargeAssetAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.Slot, c.Body.Quantity))
 	}
 }
 
@@ -188,7 +225,8 @@ func handleMergeCommand(db *gorm.DB) message.Handler[compartment2.Command[compar
 		if c.Type != compartment2.CommandMerge {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).MergeAndCompactAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType))
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.MergeCommandFailed, p.MergeAndCompactAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType)))
 	}
 }
 
@@ -197,7 +235,8 @@ func handleSortCommand(db *gorm.DB) message.Handler[compartment2.Command[compart
 		if c.Type != compartment2.CommandSort {
 			return
 		}
-		_ = compartment.NewProcessor(l, ctx, db).CompactAndSortAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType))
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.SortCommandFailed, p.CompactAndSortAndEmit(c.TransactionId, c.CharacterId, inventory.Type(c.InventoryType)))
 	}
 }
 
@@ -212,7 +251,8 @@ func handleAcceptCommand(db *gorm.DB) message.Handler[compartment2.Command[compa
 		if transactionId == uuid.Nil {
 			transactionId = c.Body.TransactionId
 		}
-		_ = compartment.NewProcessor(l, ctx, db).AcceptAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.ReferenceId)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, transactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.AcceptCommandFailed, p.AcceptAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.ReferenceId))
 	}
 }
 
@@ -227,6 +267,23 @@ func handleReleaseCommand(db *gorm.DB) message.Handler[compartment2.Command[comp
 		if transactionId == uuid.Nil {
 			transactionId = c.Body.TransactionId
 		}
-		_ = compartment.NewProcessor(l, ctx, db).ReleaseAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.AssetId)
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, transactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(transactionId, c.CharacterId, compartment2.ReleaseCommandFailed, p.ReleaseAndEmit(transactionId, c.CharacterId, inventory.Type(c.InventoryType), c.Body.AssetId))
+	}
+}
+
+func handleApplyLoadoutCommand(db *gorm.DB) message.Handler[compartment2.Command[compartment2.ApplyLoadoutCommandBody]] {
+	return func(l logrus.FieldLogger, ctx context.Context, c compartment2.Command[compartment2.ApplyLoadoutCommandBody]) {
+		if c.Type != compartment2.CommandApplyLoadout {
+			return
+		}
+		p := compartment.NewProcessor(l, ledger.WithSubjectCommand(ctx, c.Type, c.TransactionId, subject(c)), db)
+		_ = p.ReportFailureAndEmit(c.TransactionId, c.CharacterId, compartment2.ApplyLoadoutCommandFailed, p.ApplyLoadoutAndEmit(c.TransactionId, c.CharacterId, c.Body.LoadoutId, c.Body.SkipMissing))
 	}
 }
+
+// subject names what a command addresses within its transaction, so that one transaction may carry several commands
+// of a type, for several characters or with different bodies, and each is recorded on its own.
+func subject[E any](c compartment2.Command[E]) string {
+	return ledger.Subject(c.CharacterId, inventory.Type(c.InventoryType), c.Body)
+}
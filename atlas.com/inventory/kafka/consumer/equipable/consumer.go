@@ -51,6 +51,10 @@ func handleUpdatedStatusEvent(db *gorm.DB) message.Handler[equipable2.StatusEven
 		if err != nil {
 			return
 		}
-		_ = ap.RelayUpdateAndEmit(uuid.New(), c.CharacterId(), a.ReferenceId(), a.ReferenceType(), a.ReferenceData())
+		transactionId := uuid.New()
+		_ = ap.RelayUpdateAndEmit(transactionId, c.CharacterId(), a.ReferenceId(), a.ReferenceType(), a.ReferenceData())
+		if a.Slot() < 0 {
+			_ = cp.RefreshEquipmentStatisticsAndEmit(transactionId, c.CharacterId())
+		}
 	}
 }
@@ -12,6 +12,10 @@ const (
 	StatusEventTypeDeleted         = "DELETED"
 	StatusEventTypeMoved           = "MOVED"
 	StatusEventTypeQuantityChanged = "QUANTITY_CHANGED"
+	StatusEventTypeExpiringSoon    = "EXPIRING_SOON"
+
+	DeletedReasonExpired  = "EXPIRED"
+	DeletedReasonDangling = "DANGLING"
 )
 
 type StatusEvent[E any] struct {
@@ -140,12 +144,18 @@ type PetReferenceData struct {
 }
 
 type DeletedStatusEventBody struct {
+	Reason string `json:"reason,omitempty"`
 }
 
 type MovedStatusEventBody struct {
 	OldSlot int16 `json:"oldSlot"`
 }
 
+type ExpiringSoonStatusEventBody struct {
+	Expiration time.Time `json:"expiration"`
+	Threshold  int64     `json:"threshold"`
+}
+
 type QuantityChangedEventBody struct {
 	Quantity uint32 `json:"quantity"`
 }
@@ -22,6 +22,8 @@ const (
 	CommandSort              = "SORT"
 	CommandAccept            = "ACCEPT"
 	CommandRelease           = "RELEASE"
+	CommandRenewReservation  = "RENEW_RESERVATION"
+	CommandApplyLoadout      = "APPLY_LOADOUT"
 )
 
 type Command[E any] struct {
@@ -60,12 +62,14 @@ type DropCommandBody struct {
 type RequestReserveCommandBody struct {
 	TransactionId uuid.UUID  `json:"transactionId"`
 	Items         []ItemBody `json:"items"`
+	Ttl           uint32     `json:"ttl,omitempty"` // seconds, bounded by the tenant configuration
 }
 
 type ItemBody struct {
-	Source   int16  `json:"source"`
-	ItemId   uint32 `json:"itemId"`
-	Quantity int16  `json:"quantity"`
+	InventoryType byte   `json:"inventoryType,omitempty"`
+	Source        int16  `json:"source"`
+	ItemId        uint32 `json:"itemId"`
+	Quantity      int16  `json:"quantity"`
 }
 
 type ConsumeCommandBody struct {
@@ -83,6 +87,11 @@ type CancelReservationCommandBody struct {
 	Slot          int16     `json:"slot"`
 }
 
+type RenewReservationCommandBody struct {
+	TransactionId uuid.UUID `json:"transactionId"`
+	Ttl           uint32    `json:"ttl,omitempty"` // seconds, bounded by the tenant configuration
+}
+
 type IncreaseCapacityCommandBody struct {
 	Amount uint32 `json:"amount"`
 }
@@ -117,6 +126,13 @@ type ReleaseCommandBody struct {
 	AssetId       uint32    `json:"assetId"`
 }
 
+// ApplyLoadoutCommandBody names a saved loadout to put on. With SkipMissing, slots whose asset the character no longer
+// has are left as they are, rather than failing the command.
+type ApplyLoadoutCommandBody struct {
+	LoadoutId   uuid.UUID `json:"loadoutId"`
+	SkipMissing bool      `json:"skipMissing"`
+}
+
 const (
 	EnvEventTopicStatus                 = "EVENT_TOPIC_COMPARTMENT_STATUS"
 	StatusEventTypeCreated              = "CREATED"
@@ -124,14 +140,55 @@ const (
 	StatusEventTypeCapacityChanged      = "CAPACITY_CHANGED"
 	StatusEventTypeReserved             = "RESERVED"
 	StatusEventTypeReservationCancelled = "RESERVATION_CANCELLED"
+	StatusEventTypeReservationExpired   = "RESERVATION_EXPIRED"
+	StatusEventTypeReservationComplete  = "RESERVATION_COMPLETE"
+	StatusEventTypeReservationRenewed   = "RESERVATION_RENEWED"
 	StatusEventTypeMergeComplete        = "MERGE_COMPLETE"
 	StatusEventTypeSortComplete         = "SORT_COMPLETE"
 	StatusEventTypeAccepted             = "ACCEPTED"
 	StatusEventTypeReleased             = "RELEASED"
 	StatusEventTypeError                = "ERROR"
-
-	AcceptCommandFailed  = "ACCEPT_COMMAND_FAILED"
-	ReleaseCommandFailed = "RELEASE_COMMAND_FAILED"
+	StatusEventTypeStatsChanged         = "EQUIPMENT_STATS_CHANGED"
+	StatusEventTypeLoadoutApplied       = "LOADOUT_APPLIED"
+
+	EquipCommandFailed             = "EQUIP_COMMAND_FAILED"
+	UnequipCommandFailed           = "UNEQUIP_COMMAND_FAILED"
+	MoveCommandFailed              = "MOVE_COMMAND_FAILED"
+	DropCommandFailed              = "DROP_COMMAND_FAILED"
+	ConsumeCommandFailed           = "CONSUME_COMMAND_FAILED"
+	DestroyCommandFailed           = "DESTROY_COMMAND_FAILED"
+	CancelReservationCommandFailed = "CANCEL_RESERVATION_COMMAND_FAILED"
+	IncreaseCapacityCommandFailed  = "INCREASE_CAPACITY_COMMAND_FAILED"
+	CreateAssetCommandFailed       = "CREATE_ASSET_COMMAND_FAILED"
+	RechargeCommandFailed          = "RECHARGE_COMMAND_FAILED"
+	MergeCommandFailed             = "MERGE_COMMAND_FAILED"
+	SortCommandFailed              = "SORT_COMMAND_FAILED"
+	AcceptCommandFailed            = "ACCEPT_COMMAND_FAILED"
+	ReleaseCommandFailed           = "RELEASE_COMMAND_FAILED"
+	RequestReserveCommandFailed    = "REQUEST_RESERVE_COMMAND_FAILED"
+	RenewReservationCommandFailed  = "RENEW_RESERVATION_COMMAND_FAILED"
+	ApplyLoadoutCommandFailed      = "APPLY_LOADOUT_COMMAND_FAILED"
+
+	ErrorReasonCompartmentNotFound  = "COMPARTMENT_NOT_FOUND"
+	ErrorReasonInventoryFull        = "INVENTORY_FULL"
+	ErrorReasonSlotEmpty            = "SLOT_EMPTY"
+	ErrorReasonAssetNotFound        = "ASSET_NOT_FOUND"
+	ErrorReasonInvalidQuantity      = "INVALID_QUANTITY"
+	ErrorReasonInsufficientQuantity = "INSUFFICIENT_QUANTITY"
+	ErrorReasonTemplateMismatch     = "TEMPLATE_MISMATCH"
+	ErrorReasonReserved             = "RESERVED"
+	ErrorReasonNotReserved          = "NOT_RESERVED"
+	ErrorReasonNotEquippable        = "NOT_EQUIPPABLE"
+	ErrorReasonNotRechargeable      = "NOT_RECHARGEABLE"
+	ErrorReasonLockTimeout          = "LOCK_TIMEOUT"
+	ErrorReasonCompartmentExists    = "COMPARTMENT_EXISTS"
+	ErrorReasonSlotOccupied         = "SLOT_OCCUPIED"
+	ErrorReasonRequirementsNotMet   = "REQUIREMENTS_NOT_MET"
+	ErrorReasonSlotConflict         = "SLOT_CONFLICT"
+	ErrorReasonNoFreeSlotToUnequip  = "NO_FREE_SLOT_TO_UNEQUIP"
+	ErrorReasonLoadoutNotFound      = "LOADOUT_NOT_FOUND"
+	ErrorReasonCharacterNotFound    = "CHARACTER_NOT_FOUND"
+	ErrorReasonUnknown              = "UNKNOWN"
 )
 
 type StatusEvent[E any] struct {
@@ -167,6 +224,30 @@ type ReservationCancelledEventBody struct {
 	Quantity uint32 `json:"quantity"`
 }
 
+type ReservationCompleteEventBody struct {
+	Items []ReservedItemBody `json:"items"`
+}
+
+type ReservedItemBody struct {
+	InventoryType byte   `json:"inventoryType"`
+	ItemId        uint32 `json:"itemId"`
+	Slot          int16  `json:"slot"`
+	Quantity      uint32 `json:"quantity"`
+}
+
+type ReservationRenewedEventBody struct {
+	ItemId   uint32    `json:"itemId"`
+	Slot     int16     `json:"slot"`
+	Quantity uint32    `json:"quantity"`
+	Expiry   time.Time `json:"expiry"`
+}
+
+type ReservationExpiredEventBody struct {
+	ItemId   uint32 `json:"itemId"`
+	Slot     int16  `json:"slot"`
+	Quantity uint32 `json:"quantity"`
+}
+
 type MergeAndSortCompleteEventBody struct {
 	Type byte `json:"type"`
 }
@@ -187,7 +268,46 @@ type ReleasedEventBody struct {
 	TransactionId uuid.UUID `json:"transactionId"`
 }
 
+// EquipmentStatsChangedEventBody carries the statistics now granted by a character's equipment, without set bonuses.
+// They match the equipment statistics endpoint's totals when set bonuses are not requested from it.
+type EquipmentStatsChangedEventBody struct {
+	Strength      uint32 `json:"strength"`
+	Dexterity     uint32 `json:"dexterity"`
+	Intelligence  uint32 `json:"intelligence"`
+	Luck          uint32 `json:"luck"`
+	HP            uint32 `json:"hp"`
+	MP            uint32 `json:"mp"`
+	WeaponAttack  uint32 `json:"weaponAttack"`
+	MagicAttack   uint32 `json:"magicAttack"`
+	WeaponDefense uint32 `json:"weaponDefense"`
+	MagicDefense  uint32 `json:"magicDefense"`
+	Accuracy      uint32 `json:"accuracy"`
+	Avoidability  uint32 `json:"avoidability"`
+	Speed         uint32 `json:"speed"`
+	Jump          uint32 `json:"jump"`
+}
+
+// LoadoutAppliedEventBody summarises the equipment a loadout put on and took off, and the slots it skipped for want of
+// their asset.
+type LoadoutAppliedEventBody struct {
+	LoadoutId  uuid.UUID         `json:"loadoutId"`
+	Name       string            `json:"name"`
+	Equipped   []LoadoutItemBody `json:"equipped"`
+	Unequipped []LoadoutItemBody `json:"unequipped"`
+	Skipped    []LoadoutItemBody `json:"skipped"`
+}
+
+// LoadoutItemBody identifies an asset and the slot it was moved to, or for a skipped asset the slot it was meant for.
+type LoadoutItemBody struct {
+	AssetId    uint32 `json:"assetId"`
+	TemplateId uint32 `json:"templateId,omitempty"`
+	Slot       int16  `json:"slot"`
+}
+
 type ErrorEventBody struct {
 	ErrorCode     string    `json:"errorCode"`
 	TransactionId uuid.UUID `json:"transactionId"`
+	InventoryType byte      `json:"inventoryType,omitempty"`
+	Slot          int16     `json:"slot,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
 }
@@ -41,6 +41,15 @@ func (b *Buffer) GetAll() map[string][]kafka.Message {
 	return result
 }
 
+// Take returns everything buffered so far and empties the buffer.
+func (b *Buffer) Take() map[string][]kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	result := b.buffer
+	b.buffer = make(map[string][]kafka.Message)
+	return result
+}
+
 func Emit(p producer.Provider) func(f func(buf *Buffer) error) error {
 	return func(f func(buf *Buffer) error) error {
 		b := NewBuffer()
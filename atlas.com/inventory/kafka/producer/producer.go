@@ -18,3 +18,11 @@ func ProviderImpl(l logrus.FieldLogger) func(ctx context.Context) func(token str
 		}
 	}
 }
+
+// RelayProviderImpl publishes messages with only the headers they already carry, such as those staged with them in
+// the outbox.
+func RelayProviderImpl(l logrus.FieldLogger) Provider {
+	return func(token string) producer.MessageProducer {
+		return producer.Produce(l)(producer.WriterProvider(topic.EnvProvider(l)(token)))()
+	}
+}
@@ -0,0 +1,25 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, tenantId uuid.UUID, c Command, now time.Time) error {
+	e := &Entity{
+		TenantId:      tenantId,
+		CommandType:   c.Type,
+		TransactionId: c.TransactionId,
+		Subject:       c.Subject,
+		ExecutionId:   c.ExecutionId,
+		CreatedAt:     now,
+	}
+	return db.Create(e).Error
+}
+
+func deleteOlderThan(db *gorm.DB, cutoff time.Time) (int64, error) {
+	result := db.Where("created_at < ?", cutoff).Delete(&Entity{})
+	return result.RowsAffected, result.Error
+}
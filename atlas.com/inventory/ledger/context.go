@@ -0,0 +1,55 @@
+package ledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+// Command identifies one execution of a command. Executions of the same command share a type, transaction id and
+// subject.
+type Command struct {
+	Type          string
+	TransactionId uuid.UUID
+	Subject       string
+	ExecutionId   uuid.UUID
+}
+
+type commandKey struct{}
+
+// WithCommand marks ctx as executing the command, so mutations made with it are recorded in the ledger. Commands
+// without a transaction id cannot be told apart and are not recorded.
+func WithCommand(ctx context.Context, commandType string, transactionId uuid.UUID) context.Context {
+	return WithSubjectCommand(ctx, commandType, transactionId, "")
+}
+
+// WithSubjectCommand marks ctx as executing a command addressed to one subject of its transaction, such as a single
+// reserved slot. A transaction may carry one command of the type per subject, and each is recorded on its own.
+func WithSubjectCommand(ctx context.Context, commandType string, transactionId uuid.UUID, subject string) context.Context {
+	if transactionId == uuid.Nil {
+		return ctx
+	}
+	return context.WithValue(ctx, commandKey{}, Command{Type: commandType, TransactionId: transactionId, Subject: subject, ExecutionId: uuid.New()})
+}
+
+// Subject names what a command addresses within its transaction: the character and compartment it is applied to, and
+// its body, which is digested. Commands of one type under one transaction are recorded on their own unless all three
+// match, as a redelivery's do.
+func Subject(characterId uint32, inventoryType inventory.Type, body any) string {
+	b, err := json.Marshal(body)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%+v", body))
+	}
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("%d:%d:%s", characterId, inventoryType, hex.EncodeToString(sum[:16]))
+}
+
+func FromContext(ctx context.Context) (Command, bool) {
+	c, ok := ctx.Value(commandKey{}).(Command)
+	return c, ok
+}
@@ -0,0 +1,32 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Entity struct {
+	TenantId      uuid.UUID `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	Id            uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	CommandType   string    `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	TransactionId uuid.UUID `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	Subject       string    `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	ExecutionId   uuid.UUID `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null;index"`
+}
+
+func (e Entity) TableName() string {
+	return "command_ledger"
+}
+
+func Make(e Entity) (Model, error) {
+	return Model{
+		id:            e.Id,
+		commandType:   e.CommandType,
+		transactionId: e.TransactionId,
+		subject:       e.Subject,
+		executionId:   e.ExecutionId,
+		createdAt:     e.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,33 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV6 struct {
+	TenantId      uuid.UUID `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	Id            uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	CommandType   string    `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	TransactionId uuid.UUID `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	Subject       string    `gorm:"not null;uniqueIndex:idx_command_ledger_command"`
+	ExecutionId   uuid.UUID `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"not null;index"`
+}
+
+func (e entityV6) TableName() string {
+	return "command_ledger"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV6{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("command_ledger")
+}
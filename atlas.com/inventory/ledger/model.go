@@ -0,0 +1,42 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Model struct {
+	id            uint64
+	commandType   string
+	transactionId uuid.UUID
+	subject       string
+	executionId   uuid.UUID
+	createdAt     time.Time
+}
+
+func (m Model) Id() uint64 {
+	return m.id
+}
+
+func (m Model) CommandType() string {
+	return m.commandType
+}
+
+func (m Model) TransactionId() uuid.UUID {
+	return m.transactionId
+}
+
+// Subject names what part of its transaction the command addressed, or is empty for a command on the whole transaction.
+func (m Model) Subject() string {
+	return m.subject
+}
+
+// ExecutionId identifies the execution which applied the command, and so the events it emitted.
+func (m Model) ExecutionId() uuid.UUID {
+	return m.executionId
+}
+
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
@@ -0,0 +1,79 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ErrDuplicate reports that the command was already applied by an earlier execution.
+var ErrDuplicate = errors.New("command already processed")
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+// ProcessedProvider yields the ledger entry left by whichever execution applied the command.
+func (p *Processor) ProcessedProvider(c Command) model.Provider[Model] {
+	return model.Map(Make)(getByCommand(p.t.Id(), c.Type, c.TransactionId, c.Subject)(p.db))
+}
+
+// Claim records that the command is being applied by this execution, or returns ErrDuplicate if it already was.
+// Run it in the transaction which applies the command, so the claim is only kept if the change is.
+func (p *Processor) Claim(c Command) error {
+	_, err := p.ProcessedProvider(c)()
+	if err == nil {
+		return ErrDuplicate
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return create(p.db, p.t.Id(), c, time.Now())
+}
+
+// Claimed decorates a transactional function so that, when the context names a command, the command is claimed in
+// the same transaction before f runs.
+func (p *Processor) Claimed(f func(tx *gorm.DB) error) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		c, ok := FromContext(p.ctx)
+		if ok {
+			err := p.WithTransaction(tx).Claim(c)
+			if err != nil {
+				return err
+			}
+		}
+		return f(tx)
+	}
+}
+
+// Purge forgets every command processed before the cutoff, across all tenants. A later redelivery of one is applied again.
+func Purge(db *gorm.DB, cutoff time.Time) (int64, error) {
+	return deleteOlderThan(db, cutoff)
+}
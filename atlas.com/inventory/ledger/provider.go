@@ -0,0 +1,15 @@
+package ledger
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getByCommand(tenantId uuid.UUID, commandType string, transactionId uuid.UUID, subject string) database.EntityProvider[Entity] {
+	return func(db *gorm.DB) model.Provider[Entity] {
+		return database.Query[Entity](db.Where("subject = ?", subject), &Entity{TenantId: tenantId, CommandType: commandType, TransactionId: transactionId})
+	}
+}
@@ -0,0 +1,43 @@
+package ledger
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RetentionTask struct {
+	l         logrus.FieldLogger
+	ctx       context.Context
+	db        *gorm.DB
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewRetentionTask creates a task which forgets processed commands once they are older than retention.
+func NewRetentionTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, retention time.Duration, interval time.Duration) *RetentionTask {
+	return &RetentionTask{
+		l:         l,
+		ctx:       ctx,
+		db:        db,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+func (t *RetentionTask) Run() {
+	rows, err := Purge(t.db.WithContext(t.ctx), time.Now().Add(-t.retention))
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to purge processed commands older than [%s].", t.retention)
+		return
+	}
+	if rows > 0 {
+		t.l.Debugf("Purged [%d] processed commands older than [%s].", rows, t.retention)
+	}
+}
+
+func (t *RetentionTask) SleepTime() time.Duration {
+	return t.interval
+}
@@ -0,0 +1,65 @@
+package loadout
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, tenantId uuid.UUID, m Model) error {
+	e := &Entity{
+		TenantId:    tenantId,
+		Id:          m.Id(),
+		CharacterId: m.CharacterId(),
+		Name:        m.Name(),
+		CreatedAt:   m.CreatedAt(),
+		UpdatedAt:   m.UpdatedAt(),
+	}
+	err := db.Create(e).Error
+	if err != nil {
+		return err
+	}
+	return createSlots(db, tenantId, m.Id(), m.Slots())
+}
+
+func createSlots(db *gorm.DB, tenantId uuid.UUID, loadoutId uuid.UUID, slots []Slot) error {
+	for _, s := range slots {
+		se := &SlotEntity{
+			TenantId:  tenantId,
+			LoadoutId: loadoutId,
+			Slot:      s.Slot(),
+			AssetId:   s.AssetId(),
+		}
+		err := db.Create(se).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update renames a loadout and replaces its slots.
+func update(db *gorm.DB, tenantId uuid.UUID, id uuid.UUID, name string, slots []Slot, now time.Time) error {
+	err := db.Model(&Entity{}).Where("tenant_id = ? AND id = ?", tenantId, id).Updates(map[string]interface{}{"name": name, "updated_at": now}).Error
+	if err != nil {
+		return err
+	}
+	err = deleteSlots(db, tenantId, id)
+	if err != nil {
+		return err
+	}
+	return createSlots(db, tenantId, id, slots)
+}
+
+func deleteSlots(db *gorm.DB, tenantId uuid.UUID, loadoutId uuid.UUID) error {
+	return db.Where(&SlotEntity{TenantId: tenantId, LoadoutId: loadoutId}).Delete(&SlotEntity{}).Error
+}
+
+func deleteById(db *gorm.DB, tenantId uuid.UUID, id uuid.UUID) error {
+	err := deleteSlots(db, tenantId, id)
+	if err != nil {
+		return err
+	}
+	return db.Where(&Entity{TenantId: tenantId, Id: id}).Delete(&Entity{}).Error
+}
@@ -0,0 +1,51 @@
+package loadout
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity is a named set of equipment a character can put on at once.
+type Entity struct {
+	TenantId    uuid.UUID `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:1"`
+	Id          uuid.UUID `gorm:"primaryKey;type:uuid;not null"`
+	CharacterId uint32    `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:2"`
+	Name        string    `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:3"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (e Entity) TableName() string {
+	return "loadouts"
+}
+
+// SlotEntity names the asset a loadout wears in one equipment slot.
+type SlotEntity struct {
+	TenantId  uuid.UUID `gorm:"not null"`
+	Id        uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	LoadoutId uuid.UUID `gorm:"not null;index"`
+	Slot      int16     `gorm:"not null"`
+	AssetId   uint32    `gorm:"not null"`
+}
+
+func (e SlotEntity) TableName() string {
+	return "loadout_slots"
+}
+
+func Make(e Entity) (Model, error) {
+	return Model{
+		id:          e.Id,
+		characterId: e.CharacterId,
+		name:        e.Name,
+		createdAt:   e.CreatedAt,
+		updatedAt:   e.UpdatedAt,
+	}, nil
+}
+
+func MakeSlot(e SlotEntity) (Slot, error) {
+	return Slot{
+		slot:    e.Slot,
+		assetId: e.AssetId,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package loadout
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV10 struct {
+	TenantId    uuid.UUID `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:1"`
+	Id          uuid.UUID `gorm:"primaryKey;type:uuid;not null"`
+	CharacterId uint32    `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:2"`
+	Name        string    `gorm:"not null;uniqueIndex:idx_loadouts_character_name,priority:3"`
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (e entityV10) TableName() string {
+	return "loadouts"
+}
+
+type slotEntityV10 struct {
+	TenantId  uuid.UUID `gorm:"not null"`
+	Id        uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	LoadoutId uuid.UUID `gorm:"not null;index"`
+	Slot      int16     `gorm:"not null"`
+	AssetId   uint32    `gorm:"not null"`
+}
+
+func (e slotEntityV10) TableName() string {
+	return "loadout_slots"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV10{}, &slotEntityV10{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("loadout_slots", "loadouts")
+}
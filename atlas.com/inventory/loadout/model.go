@@ -0,0 +1,92 @@
+package loadout
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalid reports a loadout without a name, or whose slots are not distinct equipment slots holding distinct
+	// assets.
+	ErrInvalid = errors.New("invalid loadout")
+	// ErrNameTaken reports the character already has a loadout of the name.
+	ErrNameTaken = errors.New("loadout name taken")
+)
+
+type Model struct {
+	id          uuid.UUID
+	characterId uint32
+	name        string
+	slots       []Slot
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func (m Model) Id() uuid.UUID {
+	return m.id
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m Model) Name() string {
+	return m.name
+}
+
+// Slots lists the equipment slots the loadout fills, in the order they are applied.
+func (m Model) Slots() []Slot {
+	return m.slots
+}
+
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+func (m Model) UpdatedAt() time.Time {
+	return m.updatedAt
+}
+
+func (m Model) SetSlots(slots []Slot) Model {
+	m.slots = slots
+	return m
+}
+
+// Slot names the asset worn in an equipment slot.
+type Slot struct {
+	slot    int16
+	assetId uint32
+}
+
+func NewSlot(slot int16, assetId uint32) Slot {
+	return Slot{slot: slot, assetId: assetId}
+}
+
+func (s Slot) Slot() int16 {
+	return s.slot
+}
+
+func (s Slot) AssetId() uint32 {
+	return s.assetId
+}
+
+// validate rejects a loadout without a name, or one which fills a slot outside the equipment slots, fills a slot
+// twice, or wears an asset twice.
+func validate(name string, slots []Slot) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalid
+	}
+	filled := make(map[int16]bool)
+	worn := make(map[uint32]bool)
+	for _, s := range slots {
+		if s.Slot() >= 0 || s.AssetId() == 0 || filled[s.Slot()] || worn[s.AssetId()] {
+			return ErrInvalid
+		}
+		filled[s.Slot()] = true
+		worn[s.AssetId()] = true
+	}
+	return nil
+}
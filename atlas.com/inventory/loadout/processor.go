@@ -0,0 +1,142 @@
+package loadout
+
+import (
+	"atlas-inventory/database"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+func (p *Processor) ByIdProvider(characterId uint32, id uuid.UUID) model.Provider[Model] {
+	return model.Map(p.DecorateSlots)(model.Map(Make)(getById(p.t.Id(), characterId, id)(p.db)))
+}
+
+func (p *Processor) GetById(characterId uint32, id uuid.UUID) (Model, error) {
+	return p.ByIdProvider(characterId, id)()
+}
+
+// ByCharacterIdProvider yields a character's loadouts, ordered by name.
+func (p *Processor) ByCharacterIdProvider(characterId uint32) model.Provider[[]Model] {
+	rp := model.SliceMap(Make)(getByCharacterId(p.t.Id(), characterId)(p.db))(model.ParallelMap())
+	return model.SliceMap(p.DecorateSlots)(rp)(model.ParallelMap())
+}
+
+func (p *Processor) GetByCharacterId(characterId uint32) ([]Model, error) {
+	return p.ByCharacterIdProvider(characterId)()
+}
+
+func (p *Processor) DecorateSlots(m Model) (Model, error) {
+	ss, err := model.SliceMap(MakeSlot)(getSlots(p.t.Id(), m.Id())(p.db))(model.ParallelMap())()
+	if err != nil {
+		return Model{}, err
+	}
+	return m.SetSlots(ss), nil
+}
+
+// Create saves a new loadout for a character. Loadout names are unique per character.
+func (p *Processor) Create(characterId uint32, name string, slots []Slot) (Model, error) {
+	err := validate(name, slots)
+	if err != nil {
+		return Model{}, err
+	}
+	now := time.Now()
+	m := Model{id: uuid.New(), characterId: characterId, name: name, slots: slots, createdAt: now, updatedAt: now}
+	err = database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		return create(tx, p.t.Id(), m)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return Model{}, ErrNameTaken
+	}
+	if err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Created loadout [%s] [%s] for character [%d].", m.Id(), name, characterId)
+	return m, nil
+}
+
+// Update renames a character's loadout and replaces the slots it fills.
+func (p *Processor) Update(characterId uint32, id uuid.UUID, name string, slots []Slot) (Model, error) {
+	err := validate(name, slots)
+	if err != nil {
+		return Model{}, err
+	}
+	var m Model
+	err = database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		_, err = p.WithTransaction(tx).GetById(characterId, id)
+		if err != nil {
+			return err
+		}
+		err = update(tx, p.t.Id(), id, name, slots, time.Now())
+		if err != nil {
+			return err
+		}
+		m, err = p.WithTransaction(tx).GetById(characterId, id)
+		return err
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return Model{}, ErrNameTaken
+	}
+	if err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Updated loadout [%s] for character [%d].", id, characterId)
+	return m, nil
+}
+
+// Delete removes a character's loadout.
+func (p *Processor) Delete(characterId uint32, id uuid.UUID) error {
+	return database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		_, err := getById(p.t.Id(), characterId, id)(tx)()
+		if err != nil {
+			return err
+		}
+		return deleteById(tx, p.t.Id(), id)
+	})
+}
+
+// DeleteByCharacterId removes every loadout of a character, as when its inventory is deleted.
+func (p *Processor) DeleteByCharacterId(characterId uint32) error {
+	es, err := getByCharacterId(p.t.Id(), characterId)(p.db)()
+	if err != nil {
+		return err
+	}
+	for _, e := range es {
+		err = deleteById(p.db, p.t.Id(), e.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
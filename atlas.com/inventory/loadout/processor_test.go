@@ -0,0 +1,119 @@
+package loadout_test
+
+import (
+	"atlas-inventory/database"
+	"atlas-inventory/loadout"
+	"atlas-inventory/schema"
+	"context"
+	"errors"
+	"testing"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func testContext() context.Context {
+	t, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return tenant.WithContext(context.Background(), t)
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+// TestLoadoutLifecycle verifies a loadout is saved, listed, renamed with new slots, and deleted.
+func TestLoadoutLifecycle(t *testing.T) {
+	characterId := uint32(1)
+	p := loadout.NewProcessor(testLogger(), testContext(), testDatabase(t))
+
+	m, err := p.Create(characterId, "Bossing", []loadout.Slot{loadout.NewSlot(-11, 10), loadout.NewSlot(-1, 11)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+	_, err = p.Create(characterId, "Training", []loadout.Slot{loadout.NewSlot(-11, 12)})
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+
+	ms, err := p.GetByCharacterId(characterId)
+	if err != nil || len(ms) != 2 || ms[0].Name() != "Bossing" || ms[1].Name() != "Training" {
+		t.Fatalf("Unexpected loadouts [%+v] and error [%v].", ms, err)
+	}
+	ss := ms[0].Slots()
+	if len(ss) != 2 || ss[0].Slot() != -1 || ss[0].AssetId() != 11 || ss[1].Slot() != -11 || ss[1].AssetId() != 10 {
+		t.Fatalf("Unexpected slots [%+v].", ss)
+	}
+
+	m, err = p.Update(characterId, m.Id(), "Party", []loadout.Slot{loadout.NewSlot(-5, 13)})
+	if err != nil || m.Name() != "Party" || len(m.Slots()) != 1 || m.Slots()[0].AssetId() != 13 {
+		t.Fatalf("Unexpected loadout [%+v] and error [%v].", m, err)
+	}
+
+	err = p.Delete(characterId, m.Id())
+	if err != nil {
+		t.Fatalf("Failed to delete loadout: %v", err)
+	}
+	_, err = p.GetById(characterId, m.Id())
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Expected the deleted loadout to be not found, got [%v].", err)
+	}
+	err = p.Delete(characterId, m.Id())
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Expected deleting a deleted loadout to be not found, got [%v].", err)
+	}
+}
+
+// TestLoadoutValidation verifies loadouts need a name unique to the character, and distinct equipment slots holding
+// distinct assets.
+func TestLoadoutValidation(t *testing.T) {
+	p := loadout.NewProcessor(testLogger(), testContext(), testDatabase(t))
+
+	tests := []struct {
+		name        string
+		loadoutName string
+		slots       []loadout.Slot
+	}{
+		{"no name", " ", []loadout.Slot{loadout.NewSlot(-11, 10)}},
+		{"inventory slot", "Inventory", []loadout.Slot{loadout.NewSlot(3, 10)}},
+		{"slot filled twice", "Twice", []loadout.Slot{loadout.NewSlot(-11, 10), loadout.NewSlot(-11, 11)}},
+		{"asset worn twice", "Cloned", []loadout.Slot{loadout.NewSlot(-12, 10), loadout.NewSlot(-13, 10)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.Create(1, tt.loadoutName, tt.slots)
+			if !errors.Is(err, loadout.ErrInvalid) {
+				t.Fatalf("Expected the loadout to be invalid, got [%v].", err)
+			}
+		})
+	}
+
+	_, err := p.Create(1, "Bossing", nil)
+	if err != nil {
+		t.Fatalf("Failed to create loadout: %v", err)
+	}
+	_, err = p.Create(1, "Bossing", nil)
+	if !errors.Is(err, loadout.ErrNameTaken) {
+		t.Fatalf("Expected the name to be taken, got [%v].", err)
+	}
+	_, err = p.Create(2, "Bossing", nil)
+	if err != nil {
+		t.Fatalf("Expected another character to use the name, got [%v].", err)
+	}
+}
@@ -0,0 +1,27 @@
+package loadout
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getByCharacterId(tenantId uuid.UUID, characterId uint32) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Order("name"), &Entity{TenantId: tenantId, CharacterId: characterId})
+	}
+}
+
+func getById(tenantId uuid.UUID, characterId uint32, id uuid.UUID) database.EntityProvider[Entity] {
+	return func(db *gorm.DB) model.Provider[Entity] {
+		return database.Query[Entity](db, &Entity{TenantId: tenantId, CharacterId: characterId, Id: id})
+	}
+}
+
+func getSlots(tenantId uuid.UUID, loadoutId uuid.UUID) database.EntityProvider[[]SlotEntity] {
+	return func(db *gorm.DB) model.Provider[[]SlotEntity] {
+		return database.SliceQuery[SlotEntity](db.Order("slot desc"), &SlotEntity{TenantId: tenantId, LoadoutId: loadoutId})
+	}
+}
@@ -0,0 +1,166 @@
+package loadout
+
+import (
+	"atlas-inventory/rest"
+	"errors"
+	"net/http"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
+	return func(db *gorm.DB) server.RouteInitializer {
+		return func(router *mux.Router, l logrus.FieldLogger) {
+			registerGet := rest.RegisterHandler(l)(si)
+			registerInput := rest.RegisterInputHandler[InputRestModel](l)(si)
+			r := router.PathPrefix("/characters/{characterId}/inventory/loadouts").Subrouter()
+			r.HandleFunc("", registerGet("get_loadouts", handleGetLoadouts(db))).Methods(http.MethodGet)
+			r.HandleFunc("", registerInput("create_loadout", handleCreateLoadout(db))).Methods(http.MethodPost)
+			r.HandleFunc("/{loadoutId}", registerGet("get_loadout", handleGetLoadout(db))).Methods(http.MethodGet)
+			r.HandleFunc("/{loadoutId}", registerInput("update_loadout", handleUpdateLoadout(db))).Methods(http.MethodPatch)
+			r.HandleFunc("/{loadoutId}", registerGet("delete_loadout", handleDeleteLoadout(db))).Methods(http.MethodDelete)
+		}
+	}
+}
+
+func handleGetLoadouts(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				ms, err := NewProcessor(d.Logger(), d.Context(), db).GetByCharacterId(characterId)
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to retrieve loadouts for character [%d].", characterId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				rm, err := model.SliceMap(Transform)(model.FixedProvider(ms))(model.ParallelMap())()
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Creating REST model.")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				query := r.URL.Query()
+				queryParams := jsonapi.ParseQueryFields(&query)
+				server.MarshalResponse[[]RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+			}
+		})
+	}
+}
+
+func handleGetLoadout(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseLoadoutId(d.Logger(), func(loadoutId uuid.UUID) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					m, err := NewProcessor(d.Logger(), d.Context(), db).GetById(characterId, loadoutId)
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to retrieve loadout [%s] for character [%d].", loadoutId, characterId)
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					marshalLoadout(d, c, w, r, m)
+				}
+			})
+		})
+	}
+}
+
+func handleCreateLoadout(db *gorm.DB) rest.InputHandler[InputRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i InputRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				ss, err := model.SliceMap(ExtractSlot)(model.FixedProvider(i.Slots))(model.ParallelMap())()
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				m, err := NewProcessor(d.Logger(), d.Context(), db).Create(characterId, i.Name, ss)
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to create loadout [%s] for character [%d].", i.Name, characterId)
+					w.WriteHeader(errorStatus(err))
+					return
+				}
+				marshalLoadout(d, c, w, r, m)
+			}
+		})
+	}
+}
+
+func handleUpdateLoadout(db *gorm.DB) rest.InputHandler[InputRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i InputRestModel) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseLoadoutId(d.Logger(), func(loadoutId uuid.UUID) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					ss, err := model.SliceMap(ExtractSlot)(model.FixedProvider(i.Slots))(model.ParallelMap())()
+					if err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					m, err := NewProcessor(d.Logger(), d.Context(), db).Update(characterId, loadoutId, i.Name, ss)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to update loadout [%s] for character [%d].", loadoutId, characterId)
+						w.WriteHeader(errorStatus(err))
+						return
+					}
+					marshalLoadout(d, c, w, r, m)
+				}
+			})
+		})
+	}
+}
+
+func handleDeleteLoadout(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseCharacterId(d.Logger(), func(characterId uint32) http.HandlerFunc {
+			return rest.ParseLoadoutId(d.Logger(), func(loadoutId uuid.UUID) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					err := NewProcessor(d.Logger(), d.Context(), db).Delete(characterId, loadoutId)
+					if err != nil {
+						d.Logger().WithError(err).Errorf("Unable to delete loadout [%s] for character [%d].", loadoutId, characterId)
+						w.WriteHeader(errorStatus(err))
+						return
+					}
+					w.WriteHeader(http.StatusNoContent)
+				}
+			})
+		})
+	}
+}
+
+func marshalLoadout(d *rest.HandlerDependency, c *rest.HandlerContext, w http.ResponseWriter, r *http.Request, m Model) {
+	rm, err := model.Map(Transform)(model.FixedProvider(m))()
+	if err != nil {
+		d.Logger().WithError(err).Errorf("Creating REST model.")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	query := r.URL.Query()
+	queryParams := jsonapi.ParseQueryFields(&query)
+	server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+}
+
+// errorStatus maps why a loadout could not be saved or removed to the HTTP status reported for it.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrNameTaken):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,85 @@
+package loadout
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+)
+
+type RestModel struct {
+	Id          uuid.UUID       `json:"-"`
+	CharacterId uint32          `json:"characterId"`
+	Name        string          `json:"name"`
+	Slots       []SlotRestModel `json:"slots"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+func (r RestModel) GetName() string {
+	return "loadouts"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := uuid.Parse(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+	return nil
+}
+
+type SlotRestModel struct {
+	Slot    int16  `json:"slot"`
+	AssetId uint32 `json:"assetId"`
+}
+
+func Transform(m Model) (RestModel, error) {
+	ss, err := model.SliceMap(TransformSlot)(model.FixedProvider(m.Slots()))(model.ParallelMap())()
+	if err != nil {
+		return RestModel{}, err
+	}
+	return RestModel{
+		Id:          m.Id(),
+		CharacterId: m.CharacterId(),
+		Name:        m.Name(),
+		Slots:       ss,
+		CreatedAt:   m.CreatedAt(),
+		UpdatedAt:   m.UpdatedAt(),
+	}, nil
+}
+
+func TransformSlot(s Slot) (SlotRestModel, error) {
+	return SlotRestModel{
+		Slot:    s.Slot(),
+		AssetId: s.AssetId(),
+	}, nil
+}
+
+func ExtractSlot(r SlotRestModel) (Slot, error) {
+	return NewSlot(r.Slot, r.AssetId), nil
+}
+
+// InputRestModel creates or replaces a loadout, naming the asset to wear in each equipment slot.
+type InputRestModel struct {
+	Id    string          `json:"-"`
+	Name  string          `json:"name"`
+	Slots []SlotRestModel `json:"slots"`
+}
+
+func (r InputRestModel) GetName() string {
+	return "loadouts"
+}
+
+func (r InputRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *InputRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
@@ -0,0 +1,70 @@
+package lock
+
+import (
+	"atlas-inventory/database"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const advisoryRetryInterval = time.Millisecond * 25
+
+// AdvisoryProvider takes Postgres transaction-level advisory locks, so replicas sharing a database exclude one another.
+// Postgres grants a session a lock it already holds, which makes nested calls within a transaction re-entrant.
+type AdvisoryProvider struct {
+	timeout time.Duration
+}
+
+func NewAdvisoryProvider(timeout time.Duration) *AdvisoryProvider {
+	return &AdvisoryProvider{timeout: timeout}
+}
+
+func (p *AdvisoryProvider) Transaction(ctx context.Context, db *gorm.DB, keys Set, f func(tx *gorm.DB) error) error {
+	deadline := time.Now().Add(p.timeout)
+	return database.ExecuteTransaction(db.WithContext(ctx), func(tx *gorm.DB) error {
+		for _, key := range keys {
+			err := p.acquire(ctx, tx, key, deadline)
+			if err != nil {
+				return err
+			}
+		}
+		return f(tx)
+	})
+}
+
+// acquire polls for the key rather than blocking in Postgres, so the wait honours both the deadline and ctx.
+func (p *AdvisoryProvider) acquire(ctx context.Context, tx *gorm.DB, key Key, deadline time.Time) error {
+	for {
+		var acquired bool
+		err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", key.Hash()).Scan(&acquired).Error
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return TimeoutError{Key: key, Timeout: p.timeout}
+		}
+		select {
+		case <-time.After(advisoryRetryInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (p *AdvisoryProvider) Exclusive(ctx context.Context, db *gorm.DB, name string, f func(tx *gorm.DB) error) (bool, error) {
+	ran := false
+	err := database.ExecuteTransaction(db.WithContext(ctx), func(tx *gorm.DB) error {
+		var acquired bool
+		err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", hash(name)).Scan(&acquired).Error
+		if err != nil || !acquired {
+			return err
+		}
+		ran = true
+		return f(tx)
+	})
+	return ran, err
+}
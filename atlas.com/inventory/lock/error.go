@@ -0,0 +1,22 @@
+package lock
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError reports that a key could not be acquired before the provider's timeout elapsed.
+type TimeoutError struct {
+	Key     Key
+	Timeout time.Duration
+}
+
+func (e TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after [%s] acquiring lock [%s]", e.Timeout, e.Key.String())
+}
+
+func IsTimeout(err error) bool {
+	var te TimeoutError
+	return errors.As(err, &te)
+}
@@ -0,0 +1,94 @@
+package lock
+
+import (
+	"atlas-inventory/database"
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryProvider holds locks within this process only. It is suitable when a single replica serves a tenant.
+type MemoryProvider struct {
+	timeout time.Duration
+	locks   sync.Map
+}
+
+func NewMemoryProvider(timeout time.Duration) *MemoryProvider {
+	return &MemoryProvider{timeout: timeout}
+}
+
+func (p *MemoryProvider) get(key any) chan struct{} {
+	val, _ := p.locks.LoadOrStore(key, make(chan struct{}, 1))
+	return val.(chan struct{})
+}
+
+// heldKey finds, in the context of a transaction, the locks the provider holds for it.
+type heldKey struct {
+	p *MemoryProvider
+}
+
+// held yields the locks already held for the transaction db is in, so a nested call does not wait on itself.
+func (p *MemoryProvider) held(db *gorm.DB) map[any]struct{} {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return nil
+	}
+	if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+		return nil
+	}
+	h, _ := db.Statement.Context.Value(heldKey{p}).(map[any]struct{})
+	return h
+}
+
+// withHeld records in ctx the locks held for the transaction run with it.
+func (p *MemoryProvider) withHeld(ctx context.Context, held map[any]struct{}, keys ...any) context.Context {
+	h := make(map[any]struct{}, len(held)+len(keys))
+	for key := range held {
+		h[key] = struct{}{}
+	}
+	for _, key := range keys {
+		h[key] = struct{}{}
+	}
+	return context.WithValue(ctx, heldKey{p}, h)
+}
+
+// Transaction is re-entrant: called with the transaction an enclosing call gave f, it skips the keys already held.
+func (p *MemoryProvider) Transaction(ctx context.Context, db *gorm.DB, keys Set, f func(tx *gorm.DB) error) error {
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	held := p.held(db)
+	acquired := make([]any, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := held[key]; ok {
+			continue
+		}
+		l := p.get(key)
+		select {
+		case l <- struct{}{}:
+			defer func() { <-l }()
+		case <-timer.C:
+			return TimeoutError{Key: key, Timeout: p.timeout}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		acquired = append(acquired, key)
+	}
+	return database.ExecuteTransaction(db.WithContext(p.withHeld(ctx, held, acquired...)), f)
+}
+
+// Exclusive is re-entrant as Transaction is: a lock already held for the transaction is treated as acquired.
+func (p *MemoryProvider) Exclusive(ctx context.Context, db *gorm.DB, name string, f func(tx *gorm.DB) error) (bool, error) {
+	held := p.held(db)
+	if _, ok := held[name]; !ok {
+		l := p.get(name)
+		select {
+		case l <- struct{}{}:
+			defer func() { <-l }()
+		default:
+			return false, nil
+		}
+	}
+	return true, database.ExecuteTransaction(db.WithContext(p.withHeld(ctx, held, name)), f)
+}
@@ -0,0 +1,217 @@
+package lock_test
+
+import (
+	"atlas-inventory/lock"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	return db
+}
+
+// hold takes the keys in the background until the returned function is called.
+func hold(t *testing.T, p lock.Provider, db *gorm.DB, keys lock.Set) func() {
+	acquired := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- p.Transaction(context.Background(), db, keys, func(tx *gorm.DB) error {
+			close(acquired)
+			<-release
+			return nil
+		})
+	}()
+	select {
+	case <-acquired:
+	case err := <-done:
+		t.Fatalf("Failed to hold keys: %v", err)
+	}
+	return func() {
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("Failed to release keys: %v", err)
+		}
+	}
+}
+
+// TestSetCanonicalOrder verifies that a set orders its keys by tenant, character and inventory type whatever order
+// they are given in, and holds each only once.
+func TestSetCanonicalOrder(t *testing.T) {
+	a := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	b := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	s := lock.NewSet(
+		lock.CompartmentKey(b, 1, inventory.TypeValueEquip),
+		lock.CompartmentKey(a, 2, inventory.TypeValueUse),
+		lock.CompartmentKey(a, 1, inventory.TypeValueETC),
+		lock.CompartmentKey(a, 2, inventory.TypeValueUse),
+		lock.CompartmentKey(a, 1, inventory.TypeValueEquip),
+	)
+	want := lock.Set{
+		lock.CompartmentKey(a, 1, inventory.TypeValueEquip),
+		lock.CompartmentKey(a, 1, inventory.TypeValueETC),
+		lock.CompartmentKey(a, 2, inventory.TypeValueUse),
+		lock.CompartmentKey(b, 1, inventory.TypeValueEquip),
+	}
+	if len(s) != len(want) {
+		t.Fatalf("Expected [%d] keys, got [%d].", len(want), len(s))
+	}
+	for i := range want {
+		if s[i] != want[i] {
+			t.Fatalf("Expected key [%s] at [%d], got [%s].", want[i], i, s[i])
+		}
+	}
+}
+
+// TestMemoryTransactionTimeout verifies that waiting on a held key gives up with a TimeoutError naming the key.
+func TestMemoryTransactionTimeout(t *testing.T) {
+	db := testDatabase(t)
+	p := lock.NewMemoryProvider(time.Millisecond * 50)
+	key := lock.CompartmentKey(uuid.New(), 1, inventory.TypeValueUse)
+	release := hold(t, p, db, lock.NewSet(key))
+	defer release()
+
+	ran := false
+	err := p.Transaction(context.Background(), db, lock.NewSet(key), func(tx *gorm.DB) error {
+		ran = true
+		return nil
+	})
+	var te lock.TimeoutError
+	if !errors.As(err, &te) || !lock.IsTimeout(err) || te.Key != key {
+		t.Fatalf("Expected a timeout acquiring [%s], got: %v", key, err)
+	}
+	if ran {
+		t.Fatalf("Expected the transaction not to run without its locks.")
+	}
+}
+
+// TestMemoryTransactionCancelled verifies that waiting on a held key stops once the context is cancelled.
+func TestMemoryTransactionCancelled(t *testing.T) {
+	db := testDatabase(t)
+	p := lock.NewMemoryProvider(time.Minute)
+	key := lock.CompartmentKey(uuid.New(), 1, inventory.TypeValueUse)
+	release := hold(t, p, db, lock.NewSet(key))
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(time.Millisecond*20, cancel)
+	err := p.Transaction(ctx, db, lock.NewSet(key), func(tx *gorm.DB) error {
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the wait to be cancelled, got: %v", err)
+	}
+}
+
+// TestMemoryTransactionReleases verifies that keys are released once the transaction finishes, whether it committed
+// or failed, including keys acquired before a later key timed out.
+func TestMemoryTransactionReleases(t *testing.T) {
+	db := testDatabase(t)
+	p := lock.NewMemoryProvider(time.Millisecond * 50)
+	tenantId := uuid.New()
+	first := lock.CompartmentKey(tenantId, 1, inventory.TypeValueEquip)
+	second := lock.CompartmentKey(tenantId, 1, inventory.TypeValueUse)
+
+	failure := errors.New("failure")
+	if err := p.Transaction(context.Background(), db, lock.NewSet(first), func(tx *gorm.DB) error { return nil }); err != nil {
+		t.Fatalf("Failed to run transaction: %v", err)
+	}
+	if err := p.Transaction(context.Background(), db, lock.NewSet(first), func(tx *gorm.DB) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("Expected the transaction to fail, got: %v", err)
+	}
+
+	release := hold(t, p, db, lock.NewSet(second))
+	if err := p.Transaction(context.Background(), db, lock.NewSet(first, second), func(tx *gorm.DB) error { return nil }); !lock.IsTimeout(err) {
+		t.Fatalf("Expected a timeout acquiring [%s], got: %v", second, err)
+	}
+	release()
+
+	if err := p.Transaction(context.Background(), db, lock.NewSet(first, second), func(tx *gorm.DB) error { return nil }); err != nil {
+		t.Fatalf("Expected both keys to be free, got: %v", err)
+	}
+}
+
+// TestMemoryTransactionReentrant verifies that a nested call with the enclosing transaction does not wait on the keys
+// that transaction already holds, while other callers still do.
+func TestMemoryTransactionReentrant(t *testing.T) {
+	db := testDatabase(t)
+	p := lock.NewMemoryProvider(time.Millisecond * 50)
+	tenantId := uuid.New()
+	first := lock.CompartmentKey(tenantId, 1, inventory.TypeValueEquip)
+	second := lock.CompartmentKey(tenantId, 1, inventory.TypeValueUse)
+
+	nested := false
+	err := p.Transaction(context.Background(), db, lock.NewSet(first), func(tx *gorm.DB) error {
+		return p.Transaction(context.Background(), tx, lock.NewSet(first, second), func(tx *gorm.DB) error {
+			ran, err := p.Exclusive(context.Background(), tx, "task", func(tx *gorm.DB) error {
+				return p.Transaction(context.Background(), tx, lock.NewSet(second), func(tx *gorm.DB) error {
+					nested = true
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+			if !ran {
+				return errors.New("exclusive work was skipped")
+			}
+
+			err = p.Transaction(context.Background(), db, lock.NewSet(first), func(tx *gorm.DB) error { return nil })
+			if !lock.IsTimeout(err) {
+				return errors.New("another caller acquired a held key")
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to run nested transactions: %v", err)
+	}
+	if !nested {
+		t.Fatalf("Expected the innermost transaction to run.")
+	}
+}
+
+// TestMemoryExclusive verifies that exclusive work runs when its lock is free and is skipped while another caller
+// holds it.
+func TestMemoryExclusive(t *testing.T) {
+	db := testDatabase(t)
+	p := lock.NewMemoryProvider(time.Minute)
+
+	ran, err := p.Exclusive(context.Background(), db, "task", func(tx *gorm.DB) error {
+		other, err := p.Exclusive(context.Background(), db, "task", func(tx *gorm.DB) error {
+			t.Errorf("Expected exclusive work to be skipped while the lock is held.")
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if other {
+			return errors.New("exclusive work ran twice")
+		}
+		return nil
+	})
+	if err != nil || !ran {
+		t.Fatalf("Expected exclusive work to run, got [%t] [%v].", ran, err)
+	}
+
+	failure := errors.New("failure")
+	ran, err = p.Exclusive(context.Background(), db, "task", func(tx *gorm.DB) error { return failure })
+	if !ran || !errors.Is(err, failure) {
+		t.Fatalf("Expected exclusive work to run and fail, got [%t] [%v].", ran, err)
+	}
+	ran, err = p.Exclusive(context.Background(), db, "task", func(tx *gorm.DB) error { return nil })
+	if !ran || err != nil {
+		t.Fatalf("Expected the lock to be released after a failure, got [%t] [%v].", ran, err)
+	}
+}
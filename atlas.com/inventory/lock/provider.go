@@ -0,0 +1,82 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	EnvProvider      = "LOCK_PROVIDER"
+	EnvTimeout       = "LOCK_TIMEOUT"
+	ProviderMemory   = "memory"
+	ProviderAdvisory = "advisory"
+)
+
+// Key identifies a character's compartment within a tenant.
+type Key struct {
+	TenantId      uuid.UUID
+	CharacterId   uint32
+	InventoryType inventory.Type
+}
+
+func CompartmentKey(tenantId uuid.UUID, characterId uint32, inventoryType inventory.Type) Key {
+	return Key{TenantId: tenantId, CharacterId: characterId, InventoryType: inventoryType}
+}
+
+func (k Key) String() string {
+	return fmt.Sprintf("%s:%d:%d", k.TenantId.String(), k.CharacterId, k.InventoryType)
+}
+
+// Hash folds the key into the 64-bit space used by Postgres advisory locks.
+func (k Key) Hash() int64 {
+	return hash(k.String())
+}
+
+func hash(s string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return int64(h.Sum64())
+}
+
+// Provider serializes mutations of compartments.
+type Provider interface {
+	// Transaction runs f in a database transaction while holding the locks for every key in the set.
+	// The locks are held until the transaction has committed or rolled back. If the set cannot be acquired within
+	// the provider's timeout a TimeoutError is returned, and if ctx is cancelled first its error is returned.
+	// Calls are re-entrant: f may call Transaction or Exclusive again with the transaction it was given, and locks
+	// already held for that transaction are not waited for.
+	Transaction(ctx context.Context, db *gorm.DB, keys Set, f func(tx *gorm.DB) error) error
+
+	// Exclusive runs f in a database transaction while holding the lock of the given name, if no one else holds it.
+	// Otherwise f is skipped. It reports whether f ran, so periodic work can be left to whichever replica gets there first.
+	Exclusive(ctx context.Context, db *gorm.DB, name string, f func(tx *gorm.DB) error) (bool, error)
+}
+
+var provider Provider
+var once sync.Once
+
+const defaultTimeout = time.Second * 10
+
+// GetProvider returns the lock provider named by LOCK_PROVIDER. In-memory locks are used unless advisory locks are requested.
+func GetProvider() Provider {
+	once.Do(func() {
+		timeout := defaultTimeout
+		if d, err := time.ParseDuration(os.Getenv(EnvTimeout)); err == nil && d > 0 {
+			timeout = d
+		}
+		if os.Getenv(EnvProvider) == ProviderAdvisory {
+			provider = NewAdvisoryProvider(timeout)
+		} else {
+			provider = NewMemoryProvider(timeout)
+		}
+	})
+	return provider
+}
@@ -0,0 +1,27 @@
+package lock
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+)
+
+// Set is a collection of keys held together. Keys are kept in canonical order, so any two callers locking overlapping
+// sets acquire the shared keys in the same sequence and cannot deadlock on one another.
+type Set []Key
+
+func NewSet(keys ...Key) Set {
+	s := slices.Clone(keys)
+	slices.SortFunc(s, compare)
+	return slices.Compact(s)
+}
+
+func compare(a Key, b Key) int {
+	if c := bytes.Compare(a.TenantId[:], b.TenantId[:]); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.CharacterId, b.CharacterId); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.InventoryType, b.InventoryType)
+}
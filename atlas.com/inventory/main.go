@@ -3,20 +3,33 @@ package main
 import (
 	"atlas-inventory/asset"
 	"atlas-inventory/compartment"
+	"atlas-inventory/configuration"
+	"atlas-inventory/data/cache"
+	"atlas-inventory/data/source"
 	"atlas-inventory/database"
 	"atlas-inventory/inventory"
 	"atlas-inventory/kafka/consumer/character"
 	compartment2 "atlas-inventory/kafka/consumer/compartment"
 	"atlas-inventory/kafka/consumer/drop"
 	"atlas-inventory/kafka/consumer/equipable"
+	"atlas-inventory/kafka/producer"
+	"atlas-inventory/ledger"
+	"atlas-inventory/loadout"
 	"atlas-inventory/logger"
+	"atlas-inventory/outbox"
+	"atlas-inventory/reconciliation"
+	"atlas-inventory/schema"
 	"atlas-inventory/service"
-	"atlas-inventory/stackable"
+	"atlas-inventory/tasks"
+	"atlas-inventory/tenant"
 	"atlas-inventory/tracing"
 	"github.com/Chronicle20/atlas-kafka/consumer"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/sirupsen/logrus"
 )
 
 const serviceName = "atlas-inventory"
@@ -53,7 +66,34 @@ func main() {
 		l.WithError(err).Fatal("Unable to initialize tracer.")
 	}
 
-	db := database.Connect(l, database.SetMigrations(compartment.Migration, asset.Migration, stackable.Migration))
+	configuration.Load(l)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(l, os.Args[2:])
+		return
+	}
+
+	if s, dir := source.Name(); s == source.SourceFile {
+		dir, err = source.Directory(dir)
+		if err != nil {
+			l.WithError(err).Fatalf("Unable to read item data from [%s] set in [%s].", os.Getenv(source.EnvDirectory), source.EnvDirectory)
+		}
+		l.Infof("Reading item data from [%s].", dir)
+	}
+
+	ledgerRetention := getDuration(l)("COMMAND_LEDGER_RETENTION", time.Hour*24)
+	outboxRetention := getDuration(l)("OUTBOX_RETENTION", time.Hour*24)
+	if outboxRetention < ledgerRetention {
+		l.Fatalf("OUTBOX_RETENTION [%s] is shorter than COMMAND_LEDGER_RETENTION [%s], so duplicate commands could find no messages to re-emit.", outboxRetention, ledgerRetention)
+	}
+
+	db := database.Connect(l, database.SetMigrations(schema.Migrations()...), database.SetMigrateOnStartup(getBool(l)("DB_MIGRATE_ON_STARTUP", true)))
+
+	tenants := tenant.NewProcessor(l, tdm.Context()).AllProvider()
+
+	if getBool(l)("CONSISTENCY_CHECK_ON_STARTUP", false) {
+		compartment.CheckConsistency(l, tdm.Context(), db, tenants, getBool(l)("CONSISTENCY_FIX_ON_STARTUP", false))
+	}
 
 	cmf := consumer.GetManager().AddConsumer(l, tdm.Context(), tdm.WaitGroup())
 	character.InitConsumers(l)(cmf)(consumerGroupId)
@@ -74,10 +114,51 @@ func main() {
 		AddRouteInitializer(inventory.InitResource(GetServer())(db)).
 		AddRouteInitializer(compartment.InitResource(GetServer())(db)).
 		AddRouteInitializer(asset.InitResource(GetServer())(db)).
+		AddRouteInitializer(reconciliation.InitResource(GetServer())(db)).
+		AddRouteInitializer(loadout.InitResource(GetServer())(db)).
+		AddRouteInitializer(cache.InitResource(GetServer())(db)).
 		Run()
 
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(compartment.NewReservationExpiryTask(l, tdm.Context(), db, getDuration(l)("RESERVATION_EXPIRY_INTERVAL", time.Second*5)))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(compartment.NewAssetExpiryTask(l, tdm.Context(), db, tenants, getDuration(l)("ASSET_EXPIRY_INTERVAL", time.Minute), time.Now))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(reconciliation.NewTask(l, tdm.Context(), db, tenants, getDuration(l)("RECONCILIATION_INTERVAL", time.Hour), getBool(l)("RECONCILIATION_REPAIR", false)))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(outbox.NewRelayTask(l, tdm.Context(), db, producer.RelayProviderImpl(l), getDuration(l)("OUTBOX_RELAY_INTERVAL", time.Millisecond*100)))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(cache.NewInvalidationTask(l, tdm.Context(), db, cache.GetCache(), getDuration(l)("DATA_CACHE_INVALIDATION_INTERVAL", time.Second*5)))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(ledger.NewRetentionTask(l, tdm.Context(), db, ledgerRetention, time.Minute))
+	tasks.Register(l, tdm.Context(), tdm.WaitGroup())(outbox.NewRetentionTask(l, tdm.Context(), db, outboxRetention, time.Minute))
+
 	tdm.TeardownFunc(tracing.Teardown(l)(tc))
 
 	tdm.Wait()
 	l.Infoln("Service shutdown.")
 }
+
+func getDuration(l logrus.FieldLogger) func(key string, def time.Duration) time.Duration {
+	return func(key string, def time.Duration) time.Duration {
+		v, ok := os.LookupEnv(key)
+		if !ok {
+			return def
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			l.Warnf("Invalid duration [%s] configured for [%s]. Defaulting to [%s].", v, key, def)
+			return def
+		}
+		return d
+	}
+}
+
+func getBool(l logrus.FieldLogger) func(key string, def bool) bool {
+	return func(key string, def bool) bool {
+		v, ok := os.LookupEnv(key)
+		if !ok {
+			return def
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			l.Warnf("Invalid boolean [%s] configured for [%s]. Defaulting to [%t].", v, key, def)
+			return def
+		}
+		return b
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"atlas-inventory/database"
+	"atlas-inventory/schema"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// runMigrate applies or rolls back schema migrations apart from starting the service. args is one of "up" (the
+// default), "down [steps]" to roll back the latest steps migrations (default 1), or "status".
+func runMigrate(l logrus.FieldLogger, args []string) {
+	db := database.Connect(l)
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	switch command {
+	case "up":
+		err := database.Migrate(l, db, schema.Migrations())
+		if err != nil {
+			l.WithError(err).Fatalf("Unable to apply migrations.")
+		}
+		l.Infof("Schema is up to date.")
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				l.Fatalf("Invalid number of migrations [%s] to roll back.", args[1])
+			}
+		}
+		err := database.Rollback(l, db, schema.Migrations(), steps)
+		if err != nil {
+			l.WithError(err).Fatalf("Unable to roll back migrations.")
+		}
+	case "status":
+		ss, err := database.Status(db, schema.Migrations())
+		if err != nil {
+			l.WithError(err).Fatalf("Unable to read schema version.")
+		}
+		for _, s := range ss {
+			if s.Applied {
+				l.Infof("Migration [%d] [%s] applied at [%s].", s.Version, s.Name, s.AppliedAt)
+			} else {
+				l.Infof("Migration [%d] [%s] pending.", s.Version, s.Name)
+			}
+		}
+	default:
+		l.Fatalf("Unknown migrate command [%s]. Expected up, down or status.", command)
+	}
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"encoding/json"
+	"maps"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+// create stages m. Its headers are kept along with the given headers, which take precedence.
+func create(db *gorm.DB, t tenant.Model, executionId uuid.UUID, topic string, m kafka.Message, headers map[string]string, now time.Time) error {
+	hs := make(map[string]string)
+	for _, h := range m.Headers {
+		hs[h.Key] = string(h.Value)
+	}
+	maps.Copy(hs, headers)
+	hb, err := json.Marshal(hs)
+	if err != nil {
+		return err
+	}
+
+	e := &Entity{
+		TenantId:           t.Id(),
+		TenantRegion:       t.Region(),
+		TenantMajorVersion: t.MajorVersion(),
+		TenantMinorVersion: t.MinorVersion(),
+		Topic:              topic,
+		Key:                m.Key,
+		Value:              m.Value,
+		Headers:            hb,
+		ExecutionId:        executionId,
+		CreatedAt:          now,
+	}
+	return db.Create(e).Error
+}
+
+func markSent(db *gorm.DB, tenantId uuid.UUID, id uint64, now time.Time) error {
+	return db.Model(&Entity{}).
+		Where("tenant_id = ? AND id = ?", tenantId, id).
+		Update("sent_at", now).Error
+}
+
+func deleteSentBefore(db *gorm.DB, cutoff time.Time) (int64, error) {
+	result := db.Where("sent_at < ?", cutoff).Delete(&Entity{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,46 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Entity struct {
+	TenantId           uuid.UUID `gorm:"not null"`
+	TenantRegion       string    `gorm:"not null"`
+	TenantMajorVersion uint16    `gorm:"not null"`
+	TenantMinorVersion uint16    `gorm:"not null"`
+	Id                 uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	Topic              string    `gorm:"not null"`
+	Key                []byte
+	Value              []byte
+	Headers            []byte
+	ExecutionId        uuid.UUID  `gorm:"index"`
+	CreatedAt          time.Time  `gorm:"not null"`
+	SentAt             *time.Time `gorm:"index"`
+}
+
+func (e Entity) TableName() string {
+	return "outbox_messages"
+}
+
+func Make(e Entity) (Model, error) {
+	headers := make(map[string]string)
+	if len(e.Headers) > 0 {
+		err := json.Unmarshal(e.Headers, &headers)
+		if err != nil {
+			return Model{}, err
+		}
+	}
+	return Model{
+		id:        e.Id,
+		topic:     e.Topic,
+		key:       e.Key,
+		value:     e.Value,
+		headers:   headers,
+		createdAt: e.CreatedAt,
+		sentAt:    e.SentAt,
+	}, nil
+}
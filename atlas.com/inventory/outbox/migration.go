@@ -0,0 +1,38 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV5 struct {
+	TenantId           uuid.UUID `gorm:"not null"`
+	TenantRegion       string    `gorm:"not null"`
+	TenantMajorVersion uint16    `gorm:"not null"`
+	TenantMinorVersion uint16    `gorm:"not null"`
+	Id                 uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	Topic              string    `gorm:"not null"`
+	Key                []byte
+	Value              []byte
+	Headers            []byte
+	ExecutionId        uuid.UUID  `gorm:"index"`
+	CreatedAt          time.Time  `gorm:"not null"`
+	SentAt             *time.Time `gorm:"index"`
+}
+
+func (e entityV5) TableName() string {
+	return "outbox_messages"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV5{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("outbox_messages")
+}
@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Model struct {
+	id        uint64
+	topic     string
+	key       []byte
+	value     []byte
+	headers   map[string]string
+	createdAt time.Time
+	sentAt    *time.Time
+}
+
+func (m Model) Id() uint64 {
+	return m.id
+}
+
+// Topic is the token naming the environment variable which resolves the Kafka topic.
+func (m Model) Topic() string {
+	return m.topic
+}
+
+func (m Model) Key() []byte {
+	return m.key
+}
+
+func (m Model) Value() []byte {
+	return m.value
+}
+
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
+
+func (m Model) Sent() bool {
+	return m.sentAt != nil
+}
+
+// Headers are the Kafka headers the message is published with, including the span and tenant it was staged under.
+func (m Model) Headers() map[string]string {
+	return m.headers
+}
+
+func (m Model) Message() kafka.Message {
+	hs := make([]kafka.Header, 0, len(m.headers))
+	for _, k := range slices.Sorted(maps.Keys(m.headers)) {
+		hs = append(hs, kafka.Header{Key: k, Value: []byte(m.headers[k])})
+	}
+	return kafka.Message{Key: m.key, Value: m.value, Headers: hs}
+}
@@ -0,0 +1,204 @@
+package outbox
+
+import (
+	"atlas-inventory/database"
+	"atlas-inventory/kafka/message"
+	"atlas-inventory/kafka/producer"
+	"atlas-inventory/ledger"
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+// executionId tags messages with the command execution which produced them, so a redelivery can re-emit them.
+func (p *Processor) executionId() uuid.UUID {
+	if c, ok := ledger.FromContext(p.ctx); ok {
+		return c.ExecutionId
+	}
+	return uuid.Nil
+}
+
+// headers are the span and tenant headers a direct producer would attach in this context. They are staged with each
+// message, as the relay publishes outside of it.
+func (p *Processor) headers() (map[string]string, error) {
+	results := make(map[string]string)
+	for _, hd := range []producer2.HeaderDecorator{producer2.SpanHeaderDecorator(p.ctx), producer2.TenantHeaderDecorator(p.ctx)} {
+		if hd == nil {
+			continue
+		}
+		hs, err := hd()
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(results, hs)
+	}
+	return results, nil
+}
+
+// Stage moves everything buffered in mb into the outbox. When run inside a transaction, the messages are only
+// visible to the relay once that transaction commits.
+func (p *Processor) Stage(mb *message.Buffer) error {
+	now := time.Now()
+	executionId := p.executionId()
+	headers, err := p.headers()
+	if err != nil {
+		return err
+	}
+	ms := mb.Take()
+	for _, topic := range slices.Sorted(maps.Keys(ms)) {
+		for _, m := range ms[topic] {
+			err = create(p.db, p.t, executionId, topic, m, headers, now)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Staged decorates a transactional function so that, once it succeeds, what it buffered is staged in the same transaction.
+func (p *Processor) Staged(mb *message.Buffer, f func(tx *gorm.DB) error) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		err := f(tx)
+		if err != nil {
+			return err
+		}
+		return p.WithTransaction(tx).Stage(mb)
+	}
+}
+
+// Producer stages messages in the outbox instead of publishing them, so they are relayed behind any staged before them.
+func (p *Processor) Producer() producer.Provider {
+	return func(topic string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			executionId := p.executionId()
+			headers, err := p.headers()
+			if err != nil {
+				return err
+			}
+			return database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+				for _, m := range ms {
+					err = create(tx, p.t, executionId, topic, m, headers, now)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// Reemit stages again, in their original order, the messages produced by an earlier command execution.
+func (p *Processor) Reemit(executionId uuid.UUID) error {
+	if executionId == uuid.Nil {
+		return nil
+	}
+	now := time.Now()
+	headers, err := p.headers()
+	if err != nil {
+		return err
+	}
+	return database.ExecuteTransaction(p.db, func(tx *gorm.DB) error {
+		ms, err := model.SliceMap(Make)(getByExecution(p.t.Id(), executionId)(tx))()()
+		if err != nil {
+			return err
+		}
+		for _, m := range ms {
+			err = create(tx, p.t, uuid.Nil, m.Topic(), m.Message(), headers, now)
+			if err != nil {
+				return err
+			}
+		}
+		p.l.Debugf("Re-emitting [%d] messages of execution [%s].", len(ms), executionId.String())
+		return nil
+	})
+}
+
+func (p *Processor) PendingProvider(limit int) model.Provider[[]Model] {
+	return model.SliceMap(Make)(getPending(p.t.Id(), limit)(p.db))()
+}
+
+// Relay publishes up to limit pending messages in the order they were staged, marking each sent. Once a message
+// fails, later messages sharing its topic and key (the character) are held back until the next run, so they are
+// never published ahead of it. Delivery is at least once. Only one relay may run for a tenant at a time, or messages
+// are published twice and out of order; RelayTask holds the tenant's relay lock for each batch. pp must publish
+// messages with the headers they carry, adding none of its own.
+func (p *Processor) Relay(pp producer.Provider, limit int) (int, error) {
+	ms, err := p.PendingProvider(limit)()
+	if err != nil {
+		return 0, err
+	}
+	held := make(map[string]bool)
+	sent := 0
+	for _, m := range ms {
+		ordering := m.Topic() + ":" + string(m.Key())
+		if held[ordering] {
+			continue
+		}
+		err = pp(m.Topic())(model.FixedProvider([]kafka.Message{m.Message()}))
+		if err != nil {
+			p.l.WithError(err).Warnf("Unable to relay outbox message [%d] to [%s]. Holding back later messages with the same key.", m.Id(), m.Topic())
+			held[ordering] = true
+			continue
+		}
+		err = markSent(p.db, p.t.Id(), m.Id(), time.Now())
+		if err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Purge deletes every message sent before the cutoff, across all tenants. They can no longer be re-emitted.
+func Purge(db *gorm.DB, cutoff time.Time) (int64, error) {
+	return deleteSentBefore(db, cutoff)
+}
+
+// PendingTenantsProvider yields every tenant which has messages waiting to be relayed.
+func PendingTenantsProvider(db *gorm.DB) model.Provider[[]tenant.Model] {
+	return model.SliceMap(func(e Entity) (tenant.Model, error) {
+		return tenant.Create(e.TenantId, e.TenantRegion, e.TenantMajorVersion, e.TenantMinorVersion)
+	})(getPendingTenants()(db))()
+}
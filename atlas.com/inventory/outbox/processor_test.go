@@ -0,0 +1,251 @@
+package outbox_test
+
+import (
+	"atlas-inventory/database"
+	"atlas-inventory/kafka/message"
+	"atlas-inventory/kafka/producer"
+	"atlas-inventory/ledger"
+	"atlas-inventory/outbox"
+	"atlas-inventory/schema"
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	producer2 "github.com/Chronicle20/atlas-kafka/producer"
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func testTenant() tenant.Model {
+	t, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return t
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+type published struct {
+	topic   string
+	key     string
+	value   string
+	headers map[string]string
+}
+
+// fakeProducer records what it publishes, failing for any key named in fail.
+func fakeProducer(sent *[]published, fail map[string]bool) producer.Provider {
+	return func(topic string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			for _, m := range ms {
+				if fail[string(m.Key)] {
+					return errors.New("broker unavailable")
+				}
+				hs := make(map[string]string)
+				for _, h := range m.Headers {
+					hs[h.Key] = string(h.Value)
+				}
+				*sent = append(*sent, published{topic: topic, key: string(m.Key), value: string(m.Value), headers: hs})
+			}
+			return nil
+		}
+	}
+}
+
+func buffered(key string, values ...string) model.Provider[[]kafka.Message] {
+	ms := make([]kafka.Message, 0, len(values))
+	for _, v := range values {
+		ms = append(ms, kafka.Message{Key: []byte(key), Value: []byte(v)})
+	}
+	return model.FixedProvider(ms)
+}
+
+// TestStagedRollsBackWithTransaction verifies messages are only staged when the transaction which buffered them commits.
+func TestStagedRollsBackWithTransaction(t *testing.T) {
+	l := testLogger()
+	ctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+	p := outbox.NewProcessor(l, ctx, db)
+
+	mb := message.NewBuffer()
+	err := database.ExecuteTransaction(db, p.Staged(mb, func(tx *gorm.DB) error {
+		_ = mb.Put("TOPIC", buffered("1", "lost"))
+		return errors.New("mutation failed")
+	}))
+	if err == nil {
+		t.Fatalf("Expected the transaction to fail.")
+	}
+
+	mb = message.NewBuffer()
+	err = database.ExecuteTransaction(db, p.Staged(mb, func(tx *gorm.DB) error {
+		return mb.Put("TOPIC", buffered("1", "kept"))
+	}))
+	if err != nil {
+		t.Fatalf("Failed to stage messages: %v", err)
+	}
+	if len(mb.GetAll()) != 0 {
+		t.Fatalf("Expected staged messages to be taken from the buffer.")
+	}
+
+	ms, err := p.PendingProvider(10)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	if len(ms) != 1 || string(ms[0].Value()) != "kept" {
+		t.Fatalf("Expected only the committed message to be pending, got [%d].", len(ms))
+	}
+}
+
+// TestRelayPreservesOrderPerKey verifies the relay publishes in staging order, and that a failure holds back later
+// messages for the same key without blocking other keys.
+func TestRelayPreservesOrderPerKey(t *testing.T) {
+	l := testLogger()
+	ctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+	p := outbox.NewProcessor(l, ctx, db)
+
+	err := message.Emit(p.Producer())(func(buf *message.Buffer) error {
+		_ = buf.Put("TOPIC", buffered("1", "a1", "a2"))
+		return buf.Put("TOPIC", buffered("2", "b1", "b2"))
+	})
+	if err != nil {
+		t.Fatalf("Failed to stage messages: %v", err)
+	}
+
+	var sent []published
+	n, err := p.Relay(fakeProducer(&sent, map[string]bool{"1": true}), 10)
+	if err != nil {
+		t.Fatalf("Failed to relay messages: %v", err)
+	}
+	if n != 2 || len(sent) != 2 || sent[0].value != "b1" || sent[1].value != "b2" {
+		t.Fatalf("Expected only key [2] to be relayed in order, got %v.", sent)
+	}
+
+	sent = nil
+	n, err = p.Relay(fakeProducer(&sent, nil), 10)
+	if err != nil {
+		t.Fatalf("Failed to relay messages: %v", err)
+	}
+	if n != 2 || len(sent) != 2 || sent[0].value != "a1" || sent[1].value != "a2" {
+		t.Fatalf("Expected held back messages of key [1] to be relayed in order, got %v.", sent)
+	}
+
+	ms, err := p.PendingProvider(10)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	if len(ms) != 0 {
+		t.Fatalf("Expected every message to be marked sent, [%d] pending.", len(ms))
+	}
+}
+
+// TestRelayKeepsHeaders verifies the headers a message is staged with are published with it, including when an
+// execution is re-emitted.
+func TestRelayKeepsHeaders(t *testing.T) {
+	l := testLogger()
+	ctx := ledger.WithCommand(tenant.WithContext(context.Background(), testTenant()), "COMMAND", uuid.New())
+	c, _ := ledger.FromContext(ctx)
+	db := testDatabase(t)
+	p := outbox.NewProcessor(l, ctx, db)
+
+	err := message.Emit(p.Producer())(func(buf *message.Buffer) error {
+		return buf.Put("TOPIC", model.FixedProvider([]kafka.Message{{Key: []byte("1"), Value: []byte("a1"), Headers: []kafka.Header{{Key: "SPAN", Value: []byte("span-1")}}}}))
+	})
+	if err != nil {
+		t.Fatalf("Failed to stage messages: %v", err)
+	}
+	if err = p.Reemit(c.ExecutionId); err != nil {
+		t.Fatalf("Failed to re-emit messages: %v", err)
+	}
+
+	var sent []published
+	n, err := p.Relay(fakeProducer(&sent, nil), 10)
+	if err != nil {
+		t.Fatalf("Failed to relay messages: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("Expected the message and its re-emission to be relayed, got [%d].", n)
+	}
+	for _, s := range sent {
+		if s.headers["SPAN"] != "span-1" {
+			t.Fatalf("Expected the staged headers to be published, got [%v].", s.headers)
+		}
+	}
+}
+
+// TestRelayTaskCommitsEachBatch verifies the messages of a run are marked sent batch by batch, so a relay which dies
+// part way through leaves only its last batch to be published again.
+func TestRelayTaskCommitsEachBatch(t *testing.T) {
+	l := testLogger()
+	ctx := tenant.WithContext(context.Background(), testTenant())
+	db := testDatabase(t)
+	p := outbox.NewProcessor(l, ctx, db)
+
+	const total = 60
+	err := message.Emit(p.Producer())(func(buf *message.Buffer) error {
+		for i := 1; i <= total; i++ {
+			err := buf.Put("TOPIC", buffered(strconv.Itoa(i), "m"+strconv.Itoa(i)))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to stage messages: %v", err)
+	}
+
+	// The producer dies while publishing the last message, as the process would were it stopped mid-run.
+	var sent []published
+	pp := func(topic string) producer2.MessageProducer {
+		return func(provider model.Provider[[]kafka.Message]) error {
+			ms, err := provider()
+			if err != nil {
+				return err
+			}
+			if string(ms[0].Value) == "m"+strconv.Itoa(total) {
+				panic("relay stopped")
+			}
+			return fakeProducer(&sent, nil)(topic)(model.FixedProvider(ms))
+		}
+	}
+	func() {
+		defer func() { _ = recover() }()
+		outbox.NewRelayTask(l, context.Background(), db, pp, time.Second).Run()
+	}()
+
+	ms, err := p.PendingProvider(total)()
+	if err != nil {
+		t.Fatalf("Failed to get pending messages: %v", err)
+	}
+	if len(ms) == 0 || len(ms) >= total-1 {
+		t.Fatalf("Expected only the batch being relayed to stay pending, got [%d] of [%d].", len(ms), total)
+	}
+	if string(ms[len(ms)-1].Value()) != "m"+strconv.Itoa(total) {
+		t.Fatalf("Expected the message being published to stay pending.")
+	}
+}
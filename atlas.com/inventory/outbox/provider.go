@@ -0,0 +1,35 @@
+package outbox
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getPending(tenantId uuid.UUID, limit int) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("sent_at IS NULL").Order("id").Limit(limit), &Entity{TenantId: tenantId})
+	}
+}
+
+func getByExecution(tenantId uuid.UUID, executionId uuid.UUID) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Order("id"), &Entity{TenantId: tenantId, ExecutionId: executionId})
+	}
+}
+
+func getPendingTenants() database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		var results []Entity
+		err := db.Model(&Entity{}).
+			Distinct("tenant_id", "tenant_region", "tenant_major_version", "tenant_minor_version").
+			Where("sent_at IS NULL").
+			Find(&results).Error
+		if err != nil {
+			return model.ErrorProvider[[]Entity](err)
+		}
+		return model.FixedProvider(results)
+	}
+}
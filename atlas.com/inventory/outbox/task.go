@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"atlas-inventory/kafka/producer"
+	"atlas-inventory/lock"
+	"context"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// relayBatchSize is how many messages are published before they are marked sent in a commit of their own, bounding
+// what is published again when a run fails part way. relayRunLimit bounds how many one run relays for each tenant.
+const (
+	relayBatchSize = 25
+	relayRunLimit  = 500
+)
+
+type RelayTask struct {
+	l        logrus.FieldLogger
+	ctx      context.Context
+	db       *gorm.DB
+	pp       producer.Provider
+	interval time.Duration
+}
+
+// NewRelayTask creates a task which publishes staged messages through pp. Each tenant is relayed by one replica at a
+// time, under a lock named for the tenant, so replicas sharing a database do not publish the same messages.
+func NewRelayTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, pp producer.Provider, interval time.Duration) *RelayTask {
+	return &RelayTask{
+		l:        l,
+		ctx:      ctx,
+		db:       db,
+		pp:       pp,
+		interval: interval,
+	}
+}
+
+func (t *RelayTask) Run() {
+	ts, err := PendingTenantsProvider(t.db.WithContext(t.ctx))()
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to retrieve tenants with pending outbox messages.")
+		return
+	}
+	for _, te := range ts {
+		err = t.relay(tenant.WithContext(t.ctx, te), te)
+		if err != nil {
+			t.l.WithError(err).Errorf("Unable to relay outbox messages for tenant [%s].", te.Id().String())
+		}
+	}
+}
+
+// relay publishes the tenant's pending messages in batches, each marked sent in a transaction of its own, so a failure
+// part way through a run does not publish the batches already committed again. It stops at the first batch which comes
+// up short, as the tenant then has nothing pending or has messages held back until the next run.
+func (t *RelayTask) relay(ctx context.Context, te tenant.Model) error {
+	for relayed := 0; relayed < relayRunLimit; {
+		sent := 0
+		ran, err := lock.GetProvider().Exclusive(ctx, t.db, "outbox-relay:"+te.Id().String(), func(tx *gorm.DB) error {
+			var err error
+			sent, err = NewProcessor(t.l, ctx, tx).Relay(t.pp, relayBatchSize)
+			return err
+		})
+		if err != nil || !ran || sent < relayBatchSize {
+			return err
+		}
+		relayed += sent
+	}
+	return nil
+}
+
+func (t *RelayTask) SleepTime() time.Duration {
+	return t.interval
+}
+
+type RetentionTask struct {
+	l         logrus.FieldLogger
+	ctx       context.Context
+	db        *gorm.DB
+	retention time.Duration
+	interval  time.Duration
+}
+
+// NewRetentionTask creates a task which deletes sent messages once they are older than retention.
+func NewRetentionTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, retention time.Duration, interval time.Duration) *RetentionTask {
+	return &RetentionTask{
+		l:         l,
+		ctx:       ctx,
+		db:        db,
+		retention: retention,
+		interval:  interval,
+	}
+}
+
+func (t *RetentionTask) Run() {
+	rows, err := Purge(t.db.WithContext(t.ctx), time.Now().Add(-t.retention))
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to purge outbox messages sent over [%s] ago.", t.retention)
+		return
+	}
+	if rows > 0 {
+		t.l.Debugf("Purged [%d] outbox messages sent over [%s] ago.", rows, t.retention)
+	}
+}
+
+func (t *RetentionTask) SleepTime() time.Duration {
+	return t.interval
+}
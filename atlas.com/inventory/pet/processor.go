@@ -29,6 +29,14 @@ func (p *Processor) GetById(petId uint32) (Model, error) {
 	return p.ByIdProvider(petId)()
 }
 
+// ByIdsProvider yields the pets with the given ids in one request. Ids the pet service does not know are omitted.
+func (p *Processor) ByIdsProvider(ids []uint32) model.Provider[[]Model] {
+	if len(ids) == 0 {
+		return model.FixedProvider[[]Model](nil)
+	}
+	return requests.SliceProvider[RestModel, Model](p.l, p.ctx)(requestByIds(ids), Extract, model.Filters[Model]())
+}
+
 func (p *Processor) Create(characterId uint32, templateId uint32) (Model, error) {
 	i := Model{
 		ownerId:    characterId,
@@ -36,3 +44,7 @@ func (p *Processor) Create(characterId uint32, templateId uint32) (Model, error)
 	}
 	return requests.Provider[RestModel, Model](p.l, p.ctx)(requestCreate(i), Extract)()
 }
+
+func (p *Processor) Delete(petId uint32) error {
+	return deleteById(petId)(p.l, p.ctx)
+}
@@ -12,6 +12,7 @@ import (
 const (
 	Resource = "pets"
 	ById     = Resource + "/%d"
+	ByIds    = Resource + "?filter[ids]=%s"
 )
 
 func getBaseRequest() string {
@@ -22,6 +23,10 @@ func requestById(petId uint32) requests.Request[RestModel] {
 	return rest.MakeGetRequest[RestModel](fmt.Sprintf(getBaseRequest()+ById, petId))
 }
 
+func requestByIds(ids []uint32) requests.Request[[]RestModel] {
+	return rest.MakeGetRequest[[]RestModel](fmt.Sprintf(getBaseRequest()+ByIds, rest.IdList(ids)))
+}
+
 func requestCreate(i Model) requests.Request[RestModel] {
 	rm, err := model.Map(Transform)(model.FixedProvider(i))()
 	if err != nil {
@@ -31,3 +36,7 @@ func requestCreate(i Model) requests.Request[RestModel] {
 	}
 	return rest.MakePostRequest[RestModel](getBaseRequest()+Resource, rm)
 }
+
+func deleteById(petId uint32) requests.EmptyBodyRequest {
+	return rest.MakeDeleteRequest(fmt.Sprintf(getBaseRequest()+ById, petId))
+}
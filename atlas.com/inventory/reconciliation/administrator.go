@@ -0,0 +1,38 @@
+package reconciliation
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, tenantId uuid.UUID, m Model) error {
+	e := &Entity{
+		TenantId:    tenantId,
+		Id:          m.Id(),
+		Trigger:     m.Trigger(),
+		DryRun:      m.DryRun(),
+		StartedAt:   m.StartedAt(),
+		CompletedAt: m.CompletedAt(),
+	}
+	err := db.Create(e).Error
+	if err != nil {
+		return err
+	}
+	for _, f := range m.Findings() {
+		fe := &FindingEntity{
+			TenantId:         tenantId,
+			ReconciliationId: m.Id(),
+			Kind:             f.Kind(),
+			AssetId:          f.AssetId(),
+			CompartmentId:    f.CompartmentId(),
+			ReferenceId:      f.ReferenceId(),
+			ReferenceType:    f.ReferenceType(),
+			Repaired:         f.Repaired(),
+		}
+		err = db.Create(fe).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
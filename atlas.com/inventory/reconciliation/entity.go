@@ -0,0 +1,59 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity is the audit record of one reconciliation run.
+type Entity struct {
+	TenantId    uuid.UUID `gorm:"not null;index"`
+	Id          uuid.UUID `gorm:"primaryKey;type:uuid;not null"`
+	Trigger     string    `gorm:"not null"`
+	DryRun      bool      `gorm:"not null"`
+	StartedAt   time.Time `gorm:"not null;index"`
+	CompletedAt time.Time `gorm:"not null"`
+}
+
+func (e Entity) TableName() string {
+	return "reconciliations"
+}
+
+// FindingEntity records one mismatch a reconciliation run found, and whether it was repaired.
+type FindingEntity struct {
+	TenantId         uuid.UUID `gorm:"not null"`
+	Id               uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	ReconciliationId uuid.UUID `gorm:"not null;index"`
+	Kind             string    `gorm:"not null"`
+	AssetId          uint32    `gorm:"not null"`
+	CompartmentId    uuid.UUID `gorm:"not null"`
+	ReferenceId      uint32    `gorm:"not null"`
+	ReferenceType    string    `gorm:"not null"`
+	Repaired         bool      `gorm:"not null"`
+}
+
+func (e FindingEntity) TableName() string {
+	return "reconciliation_findings"
+}
+
+func Make(e Entity) (Model, error) {
+	return Model{
+		id:          e.Id,
+		trigger:     e.Trigger,
+		dryRun:      e.DryRun,
+		startedAt:   e.StartedAt,
+		completedAt: e.CompletedAt,
+	}, nil
+}
+
+func MakeFinding(e FindingEntity) (Finding, error) {
+	return Finding{
+		kind:          e.Kind,
+		assetId:       e.AssetId,
+		compartmentId: e.CompartmentId,
+		referenceId:   e.ReferenceId,
+		referenceType: e.ReferenceType,
+		repaired:      e.Repaired,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV8 struct {
+	TenantId    uuid.UUID `gorm:"not null;index"`
+	Id          uuid.UUID `gorm:"primaryKey;type:uuid;not null"`
+	Trigger     string    `gorm:"not null"`
+	DryRun      bool      `gorm:"not null"`
+	StartedAt   time.Time `gorm:"not null;index"`
+	CompletedAt time.Time `gorm:"not null"`
+}
+
+func (e entityV8) TableName() string {
+	return "reconciliations"
+}
+
+type findingEntityV8 struct {
+	TenantId         uuid.UUID `gorm:"not null"`
+	Id               uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	ReconciliationId uuid.UUID `gorm:"not null;index"`
+	Kind             string    `gorm:"not null"`
+	AssetId          uint32    `gorm:"not null"`
+	CompartmentId    uuid.UUID `gorm:"not null"`
+	ReferenceId      uint32    `gorm:"not null"`
+	ReferenceType    string    `gorm:"not null"`
+	Repaired         bool      `gorm:"not null"`
+}
+
+func (e findingEntityV8) TableName() string {
+	return "reconciliation_findings"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV8{}, &findingEntityV8{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("reconciliation_findings", "reconciliations")
+}
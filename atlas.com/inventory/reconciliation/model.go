@@ -0,0 +1,115 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TriggerManual    = "MANUAL"
+	TriggerScheduled = "SCHEDULED"
+
+	// KindOrphanedStackable is a stackable no asset refers to.
+	KindOrphanedStackable = "ORPHANED_STACKABLE"
+	// KindOrphanedEquipable is an equipable no asset refers to. It may still be held by a drop, so it is never repaired.
+	KindOrphanedEquipable = "ORPHANED_EQUIPABLE"
+	// KindDanglingAsset is an asset whose reference no longer exists.
+	KindDanglingAsset = "DANGLING_ASSET"
+)
+
+type Model struct {
+	id          uuid.UUID
+	trigger     string
+	dryRun      bool
+	startedAt   time.Time
+	completedAt time.Time
+	findings    []Finding
+}
+
+func (m Model) Id() uuid.UUID {
+	return m.id
+}
+
+// Trigger reports whether the run was requested through the REST endpoint or by the scheduled job.
+func (m Model) Trigger() string {
+	return m.trigger
+}
+
+// DryRun reports whether the run only reported its findings, leaving them unrepaired.
+func (m Model) DryRun() bool {
+	return m.dryRun
+}
+
+func (m Model) StartedAt() time.Time {
+	return m.startedAt
+}
+
+func (m Model) CompletedAt() time.Time {
+	return m.completedAt
+}
+
+func (m Model) Findings() []Finding {
+	return m.findings
+}
+
+// Count yields how many findings of the kind the run made.
+func (m Model) Count(kind string) int {
+	count := 0
+	for _, f := range m.findings {
+		if f.Kind() == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// Repaired yields how many findings the run repaired.
+func (m Model) Repaired() int {
+	count := 0
+	for _, f := range m.findings {
+		if f.Repaired() {
+			count++
+		}
+	}
+	return count
+}
+
+func (m Model) SetFindings(findings []Finding) Model {
+	m.findings = findings
+	return m
+}
+
+type Finding struct {
+	kind          string
+	assetId       uint32
+	compartmentId uuid.UUID
+	referenceId   uint32
+	referenceType string
+	repaired      bool
+}
+
+func (f Finding) Kind() string {
+	return f.kind
+}
+
+// AssetId identifies the dangling asset, and is zero for orphaned references.
+func (f Finding) AssetId() uint32 {
+	return f.assetId
+}
+
+func (f Finding) CompartmentId() uuid.UUID {
+	return f.compartmentId
+}
+
+func (f Finding) ReferenceId() uint32 {
+	return f.referenceId
+}
+
+func (f Finding) ReferenceType() string {
+	return f.referenceType
+}
+
+func (f Finding) Repaired() bool {
+	return f.repaired
+}
@@ -0,0 +1,203 @@
+package reconciliation
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/cash"
+	"atlas-inventory/compartment"
+	"atlas-inventory/equipable"
+	"atlas-inventory/pet"
+	"atlas-inventory/stackable"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l                    logrus.FieldLogger
+	ctx                  context.Context
+	db                   *gorm.DB
+	t                    tenant.Model
+	assetProcessor       *asset.Processor
+	stackableProcessor   *stackable.Processor
+	equipableProcessor   *equipable.Processor
+	cashProcessor        *cash.Processor
+	petProcessor         *pet.Processor
+	compartmentProcessor *compartment.Processor
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:                    l,
+		ctx:                  ctx,
+		db:                   db,
+		t:                    tenant.MustFromContext(ctx),
+		assetProcessor:       asset.NewProcessor(l, ctx, db),
+		stackableProcessor:   stackable.NewProcessor(l, ctx, db),
+		equipableProcessor:   equipable.NewProcessor(l, ctx),
+		cashProcessor:        cash.NewProcessor(l, ctx),
+		petProcessor:         pet.NewProcessor(l, ctx),
+		compartmentProcessor: compartment.NewProcessor(l, ctx, db),
+	}
+	return p
+}
+
+func (p *Processor) ByIdProvider(id uuid.UUID) model.Provider[Model] {
+	return model.Map(p.DecorateFindings)(model.Map(Make)(getById(p.t.Id(), id)(p.db)))
+}
+
+func (p *Processor) GetById(id uuid.UUID) (Model, error) {
+	return p.ByIdProvider(id)()
+}
+
+// AllProvider yields the audit records of every reconciliation run for the tenant, most recent first.
+func (p *Processor) AllProvider() model.Provider[[]Model] {
+	rp := model.SliceMap(Make)(getAll(p.t.Id())(p.db))(model.ParallelMap())
+	return model.SliceMap(p.DecorateFindings)(rp)(model.ParallelMap())
+}
+
+func (p *Processor) DecorateFindings(m Model) (Model, error) {
+	fs, err := model.SliceMap(MakeFinding)(getFindings(p.t.Id(), m.Id())(p.db))(model.ParallelMap())()
+	if err != nil {
+		return Model{}, err
+	}
+	return m.SetFindings(fs), nil
+}
+
+// Reconcile compares the tenant's assets with the stackables, equipables, cash items and pets they refer to, and
+// records what it found. Unless dryRun is set, orphaned stackables are deleted and dangling assets are removed from
+// their compartments. Orphaned equipables are only reported, as a drop on a map may still hold them.
+func (p *Processor) Reconcile(trigger string, dryRun bool) (Model, error) {
+	m := Model{id: uuid.New(), trigger: trigger, dryRun: dryRun, startedAt: time.Now()}
+	p.l.Infof("Reconciling inventory references. Trigger [%s], dry run [%t].", trigger, dryRun)
+
+	// Assets are read either side of the references. An asset and its stackable are created together, so the
+	// stackables read after the first asset read cover every asset in it, and the second asset read covers every
+	// stackable.
+	before, err := p.assetProcessor.AllProvider()()
+	if err != nil {
+		return Model{}, err
+	}
+	ss, err := p.stackableProcessor.AllProvider()()
+	if err != nil {
+		return Model{}, err
+	}
+	es, err := p.equipableProcessor.AllProvider()()
+	equipablesListed := err == nil
+	if err != nil {
+		p.l.WithError(err).Warnf("Unable to list equipables. Orphaned equipables will not be reported.")
+	}
+	after, err := p.assetProcessor.AllProvider()()
+	if err != nil {
+		return Model{}, err
+	}
+
+	stackables := make(map[uint32]bool)
+	for _, s := range ss {
+		stackables[s.Id()] = true
+	}
+	equipables := make(map[uint32]bool)
+	for _, e := range es {
+		equipables[e.Id()] = true
+	}
+	referencedStackables := make(map[uint32]bool)
+	referencedEquipables := make(map[uint32]bool)
+	for _, a := range after {
+		if a.IsConsumable() || a.IsSetup() || a.IsEtc() {
+			referencedStackables[a.ReferenceId()] = true
+		} else if a.IsEquipable() {
+			referencedEquipables[a.ReferenceId()] = true
+		}
+	}
+
+	var findings []Finding
+	for _, s := range ss {
+		if referencedStackables[s.Id()] {
+			continue
+		}
+		f := Finding{kind: KindOrphanedStackable, compartmentId: s.CompartmentId(), referenceId: s.Id()}
+		if !dryRun {
+			err = p.stackableProcessor.Delete(s.Id())
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to delete orphaned stackable [%d].", s.Id())
+			}
+			f.repaired = err == nil
+		}
+		findings = append(findings, f)
+	}
+	for _, e := range es {
+		if referencedEquipables[e.Id()] {
+			continue
+		}
+		findings = append(findings, Finding{kind: KindOrphanedEquipable, referenceId: e.Id(), referenceType: string(asset.ReferenceTypeEquipable)})
+	}
+	for _, a := range before {
+		exists, err := p.referenceExists(a, stackables, equipables, equipablesListed)
+		if err != nil {
+			p.l.WithError(err).Warnf("Unable to verify reference [%d] of asset [%d]. Skipping.", a.ReferenceId(), a.Id())
+			continue
+		}
+		if exists {
+			continue
+		}
+		f := Finding{kind: KindDanglingAsset, assetId: a.Id(), compartmentId: a.CompartmentId(), referenceId: a.ReferenceId(), referenceType: string(a.ReferenceType())}
+		if !dryRun {
+			err = p.compartmentProcessor.DiscardDanglingAssetAndEmit(a)
+			if err != nil {
+				p.l.WithError(err).Errorf("Unable to discard dangling asset [%d].", a.Id())
+			}
+			f.repaired = err == nil
+		}
+		findings = append(findings, f)
+	}
+
+	m.findings = findings
+	m.completedAt = time.Now()
+	err = create(p.db, p.t.Id(), m)
+	if err != nil {
+		p.l.WithError(err).Errorf("Unable to record reconciliation [%s].", m.Id())
+		return Model{}, err
+	}
+	p.l.Infof("Reconciliation [%s] found [%d] orphaned stackables, [%d] orphaned equipables and [%d] dangling assets, repairing [%d].", m.Id(), m.Count(KindOrphanedStackable), m.Count(KindOrphanedEquipable), m.Count(KindDanglingAsset), m.Repaired())
+	return m, nil
+}
+
+// referenceExists reports whether the record an asset refers to still exists. Stackables and, when they could be
+// listed, equipables are checked against what was read; other references are looked up individually.
+func (p *Processor) referenceExists(a asset.Model[any], stackables map[uint32]bool, equipables map[uint32]bool, equipablesListed bool) (bool, error) {
+	if a.IsConsumable() || a.IsSetup() || a.IsEtc() {
+		return stackables[a.ReferenceId()], nil
+	}
+	if a.IsEquipable() {
+		if equipablesListed {
+			return equipables[a.ReferenceId()], nil
+		}
+		_, err := p.equipableProcessor.GetById(a.ReferenceId())
+		return found(err)
+	}
+	if a.IsCashEquipable() || a.IsCash() {
+		_, err := p.cashProcessor.GetById(a.ReferenceId())
+		return found(err)
+	}
+	if a.IsPet() {
+		_, err := p.petProcessor.GetById(a.ReferenceId())
+		return found(err)
+	}
+	return true, nil
+}
+
+func found(err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, requests.ErrNotFound) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,165 @@
+package reconciliation_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/compartment"
+	"atlas-inventory/database"
+	"atlas-inventory/kafka/message"
+	"atlas-inventory/lock"
+	"atlas-inventory/reconciliation"
+	"atlas-inventory/schema"
+	"atlas-inventory/stackable"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func testTenant() tenant.Model {
+	t, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return t
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+// TestReconcile verifies a dry run only reports orphaned stackables and dangling assets, and a repairing run removes
+// them, with both runs audited.
+func TestReconcile(t *testing.T) {
+	characterId := uint32(1)
+
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+
+	mb := message.NewBuffer()
+	cp := compartment.NewProcessor(l, ctx, db)
+	sp := stackable.NewProcessor(l, ctx, db)
+
+	c, err := cp.Create(mb)(uuid.New(), characterId, inventory.TypeValueETC, 24)
+	if err != nil {
+		t.Fatalf("Failed to create compartment: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4000000, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	err = cp.CreateAsset(mb)(uuid.New(), characterId, inventory.TypeValueETC, 4000001, 5, time.Time{}, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create asset: %v", err)
+	}
+	orphan, err := sp.Create(c.Id(), 3, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to create stackable: %v", err)
+	}
+	dangling, err := asset.NewProcessor(l, ctx, db).GetBySlot(c.Id(), 2)
+	if err != nil {
+		t.Fatalf("Failed to get asset: %v", err)
+	}
+	if err = sp.Delete(dangling.ReferenceId()); err != nil {
+		t.Fatalf("Failed to delete stackable: %v", err)
+	}
+
+	rp := reconciliation.NewProcessor(l, ctx, db)
+	m, err := rp.Reconcile(reconciliation.TriggerManual, true)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if m.Count(reconciliation.KindOrphanedStackable) != 1 || m.Count(reconciliation.KindDanglingAsset) != 1 || m.Repaired() != 0 {
+		t.Fatalf("Expected one unrepaired orphan and dangling asset, got [%d] orphans, [%d] dangling, [%d] repaired.", m.Count(reconciliation.KindOrphanedStackable), m.Count(reconciliation.KindDanglingAsset), m.Repaired())
+	}
+	if _, err = sp.GetById(orphan.Id()); err != nil {
+		t.Fatalf("Expected a dry run to leave the orphaned stackable: %v", err)
+	}
+
+	m, err = rp.Reconcile(reconciliation.TriggerManual, false)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if m.Repaired() != 2 {
+		t.Fatalf("Expected both findings to be repaired, got [%d].", m.Repaired())
+	}
+	if _, err = sp.GetById(orphan.Id()); err == nil {
+		t.Fatalf("Expected the orphaned stackable to be deleted.")
+	}
+	cm, err := cp.GetByCharacterAndType(characterId)(inventory.TypeValueETC)
+	if err != nil {
+		t.Fatalf("Failed to get compartment: %v", err)
+	}
+	if len(cm.Assets()) != 1 || cm.Assets()[0].TemplateId() != 4000000 {
+		t.Fatalf("Expected only the intact asset to remain.")
+	}
+
+	m, err = rp.Reconcile(reconciliation.TriggerManual, true)
+	if err != nil {
+		t.Fatalf("Failed to reconcile: %v", err)
+	}
+	if len(m.Findings()) != 0 {
+		t.Fatalf("Expected nothing left to reconcile, got [%d] findings.", len(m.Findings()))
+	}
+
+	ms, err := rp.AllProvider()()
+	if err != nil {
+		t.Fatalf("Failed to get reconciliations: %v", err)
+	}
+	if len(ms) != 3 {
+		t.Fatalf("Expected three audited runs, got [%d].", len(ms))
+	}
+}
+
+// TestTaskSkipsTenantReconciledElsewhere verifies the scheduled task leaves a tenant alone while another replica holds
+// its reconciliation lock, and reconciles it once the lock is free.
+func TestTaskSkipsTenantReconciledElsewhere(t *testing.T) {
+	l := testLogger()
+	te := testTenant()
+	ctx := tenant.WithContext(context.Background(), te)
+	db := testDatabase(t)
+	task := reconciliation.NewTask(l, context.Background(), db, model.FixedProvider([]tenant.Model{te}), time.Hour, false)
+
+	ran, err := lock.GetProvider().Exclusive(ctx, db, "reconciliation:"+te.Id().String(), func(tx *gorm.DB) error {
+		task.Run()
+		return nil
+	})
+	if err != nil || !ran {
+		t.Fatalf("Failed to hold the reconciliation lock: [%t] [%v].", ran, err)
+	}
+	ms, err := reconciliation.NewProcessor(l, ctx, db).AllProvider()()
+	if err != nil {
+		t.Fatalf("Failed to get reconciliations: %v", err)
+	}
+	if len(ms) != 0 {
+		t.Fatalf("Expected no reconciliation while the lock was held, got [%d].", len(ms))
+	}
+
+	task.Run()
+	ms, err = reconciliation.NewProcessor(l, ctx, db).AllProvider()()
+	if err != nil {
+		t.Fatalf("Failed to get reconciliations: %v", err)
+	}
+	if len(ms) != 1 || ms[0].Trigger() != reconciliation.TriggerScheduled {
+		t.Fatalf("Expected a single scheduled reconciliation once the lock was free, got [%d].", len(ms))
+	}
+}
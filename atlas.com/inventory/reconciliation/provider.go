@@ -0,0 +1,27 @@
+package reconciliation
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getAll(tenantId uuid.UUID) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Order("started_at desc"), &Entity{TenantId: tenantId})
+	}
+}
+
+func getById(tenantId uuid.UUID, id uuid.UUID) database.EntityProvider[Entity] {
+	return func(db *gorm.DB) model.Provider[Entity] {
+		return database.Query[Entity](db, &Entity{TenantId: tenantId, Id: id})
+	}
+}
+
+func getFindings(tenantId uuid.UUID, reconciliationId uuid.UUID) database.EntityProvider[[]FindingEntity] {
+	return func(db *gorm.DB) model.Provider[[]FindingEntity] {
+		return database.SliceQuery[FindingEntity](db.Order("id"), &FindingEntity{TenantId: tenantId, ReconciliationId: reconciliationId})
+	}
+}
@@ -0,0 +1,105 @@
+package reconciliation
+
+import (
+	"atlas-inventory/rest"
+	"errors"
+	"net/http"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+func InitResource(si jsonapi.ServerInformation) func(db *gorm.DB) server.RouteInitializer {
+	return func(db *gorm.DB) server.RouteInitializer {
+		return func(router *mux.Router, l logrus.FieldLogger) {
+			registerGet := rest.RegisterHandler(l)(si)
+			r := router.PathPrefix("/inventory/reconciliations").Subrouter()
+			r.HandleFunc("", registerGet("get_reconciliations", handleGetReconciliations(db))).Methods(http.MethodGet)
+			r.HandleFunc("", rest.RegisterInputHandler[InputRestModel](l)(si)("reconcile", handleReconcile(db))).Methods(http.MethodPost)
+			r.HandleFunc("/{reconciliationId}", registerGet("get_reconciliation", handleGetReconciliation(db))).Methods(http.MethodGet)
+		}
+	}
+}
+
+func handleGetReconciliations(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			ms, err := NewProcessor(d.Logger(), d.Context(), db).AllProvider()()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to retrieve reconciliations.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			rm, err := model.SliceMap(Transform)(model.FixedProvider(ms))(model.ParallelMap())()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[[]RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+		}
+	}
+}
+
+func handleGetReconciliation(db *gorm.DB) rest.GetHandler {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext) http.HandlerFunc {
+		return rest.ParseReconciliationId(d.Logger(), func(reconciliationId uuid.UUID) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				m, err := NewProcessor(d.Logger(), d.Context(), db).GetById(reconciliationId)
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Unable to retrieve reconciliation [%s].", reconciliationId)
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				rm, err := model.Map(Transform)(model.FixedProvider(m))()
+				if err != nil {
+					d.Logger().WithError(err).Errorf("Creating REST model.")
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				query := r.URL.Query()
+				queryParams := jsonapi.ParseQueryFields(&query)
+				server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+			}
+		})
+	}
+}
+
+func handleReconcile(db *gorm.DB) rest.InputHandler[InputRestModel] {
+	return func(d *rest.HandlerDependency, c *rest.HandlerContext, i InputRestModel) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			m, err := NewProcessor(d.Logger(), d.Context(), db).Reconcile(TriggerManual, !i.Repair)
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Unable to reconcile inventory references.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			rm, err := model.Map(Transform)(model.FixedProvider(m))()
+			if err != nil {
+				d.Logger().WithError(err).Errorf("Creating REST model.")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			query := r.URL.Query()
+			queryParams := jsonapi.ParseQueryFields(&query)
+			server.MarshalResponse[RestModel](d.Logger())(w)(c.ServerInformation())(queryParams)(rm)
+		}
+	}
+}
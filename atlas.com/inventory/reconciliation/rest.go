@@ -0,0 +1,96 @@
+package reconciliation
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+)
+
+type RestModel struct {
+	Id                 uuid.UUID          `json:"-"`
+	Trigger            string             `json:"trigger"`
+	DryRun             bool               `json:"dryRun"`
+	StartedAt          time.Time          `json:"startedAt"`
+	CompletedAt        time.Time          `json:"completedAt"`
+	OrphanedStackables int                `json:"orphanedStackables"`
+	OrphanedEquipables int                `json:"orphanedEquipables"`
+	DanglingAssets     int                `json:"danglingAssets"`
+	Repaired           int                `json:"repaired"`
+	Findings           []FindingRestModel `json:"findings"`
+}
+
+func (r RestModel) GetName() string {
+	return "reconciliations"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := uuid.Parse(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+	return nil
+}
+
+type FindingRestModel struct {
+	Kind          string    `json:"kind"`
+	AssetId       uint32    `json:"assetId"`
+	CompartmentId uuid.UUID `json:"compartmentId"`
+	ReferenceId   uint32    `json:"referenceId"`
+	ReferenceType string    `json:"referenceType"`
+	Repaired      bool      `json:"repaired"`
+}
+
+func Transform(m Model) (RestModel, error) {
+	fs, err := model.SliceMap(TransformFinding)(model.FixedProvider(m.Findings()))(model.ParallelMap())()
+	if err != nil {
+		return RestModel{}, err
+	}
+	return RestModel{
+		Id:                 m.Id(),
+		Trigger:            m.Trigger(),
+		DryRun:             m.DryRun(),
+		StartedAt:          m.StartedAt(),
+		CompletedAt:        m.CompletedAt(),
+		OrphanedStackables: m.Count(KindOrphanedStackable),
+		OrphanedEquipables: m.Count(KindOrphanedEquipable),
+		DanglingAssets:     m.Count(KindDanglingAsset),
+		Repaired:           m.Repaired(),
+		Findings:           fs,
+	}, nil
+}
+
+func TransformFinding(f Finding) (FindingRestModel, error) {
+	return FindingRestModel{
+		Kind:          f.Kind(),
+		AssetId:       f.AssetId(),
+		CompartmentId: f.CompartmentId(),
+		ReferenceId:   f.ReferenceId(),
+		ReferenceType: f.ReferenceType(),
+		Repaired:      f.Repaired(),
+	}, nil
+}
+
+// InputRestModel requests a reconciliation run. Without repair set, the run only reports what it finds.
+type InputRestModel struct {
+	Id     string `json:"-"`
+	Repair bool   `json:"repair"`
+}
+
+func (r InputRestModel) GetName() string {
+	return "reconciliations"
+}
+
+func (r InputRestModel) GetID() string {
+	return r.Id
+}
+
+func (r *InputRestModel) SetID(strId string) error {
+	r.Id = strId
+	return nil
+}
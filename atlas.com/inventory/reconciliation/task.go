@@ -0,0 +1,62 @@
+package reconciliation
+
+import (
+	"atlas-inventory/lock"
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Task reconciles the references of every tenant. Unless repair is set, it only reports. Each tenant is reconciled by
+// one replica at a time, under a lock named for the tenant, so replicas sharing a database do not repair the same
+// findings or audit the same run twice.
+type Task struct {
+	l        logrus.FieldLogger
+	ctx      context.Context
+	db       *gorm.DB
+	tenants  model.Provider[[]tenant.Model]
+	interval time.Duration
+	repair   bool
+}
+
+func NewTask(l logrus.FieldLogger, ctx context.Context, db *gorm.DB, tenants model.Provider[[]tenant.Model], interval time.Duration, repair bool) *Task {
+	return &Task{
+		l:        l,
+		ctx:      ctx,
+		db:       db,
+		tenants:  tenants,
+		interval: interval,
+		repair:   repair,
+	}
+}
+
+func (t *Task) Run() {
+	ts, err := t.tenants()
+	if err != nil {
+		t.l.WithError(err).Errorf("Unable to retrieve tenants to reconcile.")
+		return
+	}
+	for _, te := range ts {
+		tctx := tenant.WithContext(t.ctx, te)
+		ran, err := lock.GetProvider().Exclusive(tctx, t.db, "reconciliation:"+te.Id().String(), func(tx *gorm.DB) error {
+			// The lock's transaction only keeps other replicas out. Repairs run in transactions of their own, so one
+			// failing does not undo the others or the audit record.
+			_, err := NewProcessor(t.l, tctx, t.db).Reconcile(TriggerScheduled, !t.repair)
+			return err
+		})
+		if err != nil {
+			t.l.WithError(err).Errorf("Unable to reconcile tenant [%s].", te.Id())
+		}
+		if !ran {
+			t.l.Debugf("Tenant [%s] is being reconciled by another replica.", te.Id())
+		}
+	}
+}
+
+func (t *Task) SleepTime() time.Duration {
+	return t.interval
+}
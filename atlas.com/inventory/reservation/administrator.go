@@ -0,0 +1,56 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, t tenant.Model, transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, itemId uint32, quantity uint32, expiry time.Time) (Model, error) {
+	e := &Entity{
+		TenantId:           t.Id(),
+		TenantRegion:       t.Region(),
+		TenantMajorVersion: t.MajorVersion(),
+		TenantMinorVersion: t.MinorVersion(),
+		TransactionId:      transactionId,
+		CharacterId:        characterId,
+		InventoryType:      inventoryType,
+		Slot:               slot,
+		ItemId:             itemId,
+		Quantity:           quantity,
+		Expiry:             expiry,
+	}
+
+	err := db.Create(e).Error
+	if err != nil {
+		return Model{}, err
+	}
+	return Make(*e)
+}
+
+func deleteById(db *gorm.DB, tenantId uuid.UUID, id uint32) error {
+	return db.Where(&Entity{TenantId: tenantId, Id: id}).Delete(&Entity{}).Error
+}
+
+func deleteExpiredById(db *gorm.DB, tenantId uuid.UUID, id uint32, now time.Time) (int64, error) {
+	result := db.Where("expiry <= ?", now).Where(&Entity{TenantId: tenantId, Id: id}).Delete(&Entity{})
+	return result.RowsAffected, result.Error
+}
+
+func updateExpiry(db *gorm.DB, tenantId uuid.UUID, id uint32, expiry time.Time) error {
+	return db.Model(&Entity{}).
+		Where("tenant_id = ? AND id = ?", tenantId, id).
+		Update("expiry", expiry).Error
+}
+
+func updateSlot(db *gorm.DB, tenantId uuid.UUID, ids []uint32, slot int16) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return db.Model(&Entity{}).
+		Where("tenant_id = ? AND id IN ?", tenantId, ids).
+		Update("slot", slot).Error
+}
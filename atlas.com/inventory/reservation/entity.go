@@ -0,0 +1,42 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+type Entity struct {
+	TenantId           uuid.UUID      `gorm:"not null;index:idx_reservations_slot,priority:1;index:idx_reservations_transaction,priority:1;index:idx_reservations_expiry,priority:1"`
+	TenantRegion       string         `gorm:"not null"`
+	TenantMajorVersion uint16         `gorm:"not null"`
+	TenantMinorVersion uint16         `gorm:"not null"`
+	Id                 uint32         `gorm:"primaryKey;autoIncrement;not null"`
+	TransactionId      uuid.UUID      `gorm:"not null;index:idx_reservations_transaction,priority:2"`
+	CharacterId        uint32         `gorm:"not null;index:idx_reservations_slot,priority:2"`
+	InventoryType      inventory.Type `gorm:"not null;index:idx_reservations_slot,priority:3"`
+	Slot               int16          `gorm:"not null;index:idx_reservations_slot,priority:4"`
+	ItemId             uint32         `gorm:"not null"`
+	Quantity           uint32         `gorm:"not null"`
+	Expiry             time.Time      `gorm:"not null;index:idx_reservations_expiry,priority:2"`
+	CreatedAt          time.Time
+}
+
+func (e Entity) TableName() string {
+	return "reservations"
+}
+
+func Make(e Entity) (Model, error) {
+	return Model{
+		id:            e.Id,
+		transactionId: e.TransactionId,
+		characterId:   e.CharacterId,
+		inventoryType: e.InventoryType,
+		slot:          e.Slot,
+		itemId:        e.ItemId,
+		quantity:      e.Quantity,
+		expiry:        e.Expiry,
+		createdAt:     e.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV4 struct {
+	TenantId           uuid.UUID      `gorm:"not null"`
+	TenantRegion       string         `gorm:"not null"`
+	TenantMajorVersion uint16         `gorm:"not null"`
+	TenantMinorVersion uint16         `gorm:"not null"`
+	Id                 uint32         `gorm:"primaryKey;autoIncrement;not null"`
+	TransactionId      uuid.UUID      `gorm:"not null"`
+	CharacterId        uint32         `gorm:"not null"`
+	InventoryType      inventory.Type `gorm:"not null"`
+	Slot               int16          `gorm:"not null"`
+	ItemId             uint32         `gorm:"not null"`
+	Quantity           uint32         `gorm:"not null"`
+	Expiry             time.Time      `gorm:"not null"`
+}
+
+func (e entityV4) TableName() string {
+	return "reservations"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV4{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("reservations")
+}
+
+type entityV13 struct {
+	TenantId           uuid.UUID      `gorm:"not null"`
+	TenantRegion       string         `gorm:"not null"`
+	TenantMajorVersion uint16         `gorm:"not null"`
+	TenantMinorVersion uint16         `gorm:"not null"`
+	Id                 uint32         `gorm:"primaryKey;autoIncrement;not null"`
+	TransactionId      uuid.UUID      `gorm:"not null"`
+	CharacterId        uint32         `gorm:"not null"`
+	InventoryType      inventory.Type `gorm:"not null"`
+	Slot               int16          `gorm:"not null"`
+	ItemId             uint32         `gorm:"not null"`
+	Quantity           uint32         `gorm:"not null"`
+	Expiry             time.Time      `gorm:"not null"`
+	CreatedAt          time.Time
+}
+
+func (e entityV13) TableName() string {
+	return "reservations"
+}
+
+// AddCreatedAt records when each reservation was made. Reservations held before it are treated as made when it ran.
+func AddCreatedAt(db *gorm.DB) error {
+	err := db.Migrator().AddColumn(&entityV13{}, "CreatedAt")
+	if err != nil {
+		return err
+	}
+	return db.Model(&entityV13{}).Where("created_at IS NULL").Update("created_at", time.Now()).Error
+}
+
+func DropCreatedAt(db *gorm.DB) error {
+	return db.Migrator().DropColumn(&entityV13{}, "CreatedAt")
+}
+
+// AddIndexes indexes the lookups of reservations by the slot they hold, by the transaction holding them and by when
+// they lapse.
+func AddIndexes(db *gorm.DB) error {
+	for _, ddl := range []string{
+		"CREATE INDEX idx_reservations_slot ON reservations (tenant_id, character_id, inventory_type, slot)",
+		"CREATE INDEX idx_reservations_transaction ON reservations (tenant_id, transaction_id)",
+		"CREATE INDEX idx_reservations_expiry ON reservations (tenant_id, expiry)",
+	} {
+		err := db.Exec(ddl).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func DropIndexes(db *gorm.DB) error {
+	for _, ddl := range []string{
+		"DROP INDEX idx_reservations_expiry",
+		"DROP INDEX idx_reservations_transaction",
+		"DROP INDEX idx_reservations_slot",
+	} {
+		err := db.Exec(ddl).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
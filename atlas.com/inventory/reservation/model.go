@@ -0,0 +1,56 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+type Model struct {
+	id            uint32
+	transactionId uuid.UUID
+	characterId   uint32
+	inventoryType inventory.Type
+	slot          int16
+	itemId        uint32
+	quantity      uint32
+	expiry        time.Time
+	createdAt     time.Time
+}
+
+func (m Model) Id() uint32 {
+	return m.id
+}
+
+func (m Model) TransactionId() uuid.UUID {
+	return m.transactionId
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m Model) InventoryType() inventory.Type {
+	return m.inventoryType
+}
+
+func (m Model) Slot() int16 {
+	return m.slot
+}
+
+func (m Model) ItemId() uint32 {
+	return m.itemId
+}
+
+func (m Model) Quantity() uint32 {
+	return m.quantity
+}
+
+func (m Model) Expiry() time.Time {
+	return m.expiry
+}
+
+func (m Model) CreatedAt() time.Time {
+	return m.createdAt
+}
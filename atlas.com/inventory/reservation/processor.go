@@ -0,0 +1,184 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+func (p *Processor) ActiveBySlotProvider(characterId uint32, inventoryType inventory.Type, slot int16) model.Provider[[]Model] {
+	return model.SliceMap(Make)(getActiveBySlot(p.t.Id(), characterId, inventoryType, slot, time.Now())(p.db))(model.ParallelMap())
+}
+
+// ActiveProvider yields the active reservations of a character. A zero inventory type or transaction id matches any.
+func (p *Processor) ActiveProvider(characterId uint32, inventoryType inventory.Type, transactionId uuid.UUID) model.Provider[[]Model] {
+	return model.SliceMap(Make)(getActive(p.t.Id(), characterId, inventoryType, transactionId, time.Now())(p.db))(model.ParallelMap())
+}
+
+// GetReservedQuantities totals the actively reserved quantity of each slot in a compartment.
+func (p *Processor) GetReservedQuantities(characterId uint32, inventoryType inventory.Type) (map[int16]uint32, error) {
+	rs, err := p.ActiveProvider(characterId, inventoryType, uuid.Nil)()
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[int16]uint32)
+	for _, r := range rs {
+		results[r.Slot()] += r.Quantity()
+	}
+	return results, nil
+}
+
+func (p *Processor) GetReservedQuantity(characterId uint32, inventoryType inventory.Type, slot int16) (uint32, error) {
+	rs, err := p.ActiveBySlotProvider(characterId, inventoryType, slot)()
+	if err != nil {
+		return 0, err
+	}
+	var totalQuantity uint32
+	for _, r := range rs {
+		totalQuantity += r.Quantity()
+	}
+	return totalQuantity, nil
+}
+
+func (p *Processor) Add(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, itemId uint32, quantity uint32, expiry time.Duration) (Model, error) {
+	p.l.Debugf("Reserving [%d] of item [%d] in slot [%d] of character [%d] inventory [%d] for [%s]. Transaction [%s].", quantity, itemId, slot, characterId, inventoryType, expiry, transactionId.String())
+	return create(p.db, p.t, transactionId, characterId, inventoryType, slot, itemId, quantity, time.Now().Add(expiry))
+}
+
+// Remove deletes the active reservation held by the transaction on the slot, returning what was held.
+func (p *Processor) Remove(transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) (Model, error) {
+	r, err := model.Map(Make)(getActiveByTransactionAndSlot(p.t.Id(), transactionId, characterId, inventoryType, slot, time.Now())(p.db))()
+	if err != nil {
+		return Model{}, err
+	}
+	err = deleteById(p.db, p.t.Id(), r.Id())
+	if err != nil {
+		return Model{}, err
+	}
+	p.l.Debugf("Removed reservation of [%d] item [%d] in slot [%d] of character [%d] inventory [%d]. Transaction [%s].", r.Quantity(), r.ItemId(), slot, characterId, inventoryType, transactionId.String())
+	return r, nil
+}
+
+// RemoveByTransaction deletes every active reservation the transaction holds for the character, returning what was held.
+func (p *Processor) RemoveByTransaction(transactionId uuid.UUID, characterId uint32) ([]Model, error) {
+	rs, err := model.SliceMap(Make)(getActiveByTransaction(p.t.Id(), transactionId, characterId, time.Now())(p.db))(model.ParallelMap())()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rs {
+		err = deleteById(p.db, p.t.Id(), r.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.l.Debugf("Removed [%d] reservations for character [%d]. Transaction [%s].", len(rs), characterId, transactionId.String())
+	return rs, nil
+}
+
+// Swap exchanges the reservations held on two slots, so they follow the assets when they are swapped.
+func (p *Processor) Swap(characterId uint32, inventoryType inventory.Type, oldSlot int16, newSlot int16) error {
+	ids := func(slot int16) ([]uint32, error) {
+		es, err := getBySlot(p.t.Id(), characterId, inventoryType, slot)(p.db)()
+		if err != nil {
+			return nil, err
+		}
+		results := make([]uint32, 0, len(es))
+		for _, e := range es {
+			results = append(results, e.Id)
+		}
+		return results, nil
+	}
+
+	oldIds, err := ids(oldSlot)
+	if err != nil {
+		return err
+	}
+	newIds, err := ids(newSlot)
+	if err != nil {
+		return err
+	}
+	err = updateSlot(p.db, p.t.Id(), oldIds, newSlot)
+	if err != nil {
+		return err
+	}
+	return updateSlot(p.db, p.t.Id(), newIds, oldSlot)
+}
+
+// Renew extends every reservation the transaction still holds for the character to ttl from now, returning those which
+// were extended. No reservation is held for longer than maxTtl after it was made.
+func (p *Processor) Renew(transactionId uuid.UUID, characterId uint32, ttl time.Duration, maxTtl time.Duration) ([]Model, error) {
+	now := time.Now()
+	rs, err := model.SliceMap(Make)(getActiveByTransaction(p.t.Id(), transactionId, characterId, now)(p.db))(model.ParallelMap())()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Model, 0, len(rs))
+	for _, r := range rs {
+		expiry := now.Add(ttl)
+		if limit := r.CreatedAt().Add(maxTtl); expiry.After(limit) {
+			expiry = limit
+		}
+		err = updateExpiry(p.db, p.t.Id(), r.Id(), expiry)
+		if err != nil {
+			return nil, err
+		}
+		r.expiry = expiry
+		results = append(results, r)
+	}
+	p.l.Debugf("Renewed [%d] reservations for character [%d] by [%s]. Transaction [%s].", len(results), characterId, ttl, transactionId.String())
+	return results, nil
+}
+
+func (p *Processor) ExpiredProvider(now time.Time) model.Provider[[]Model] {
+	return model.SliceMap(Make)(getExpired(p.t.Id(), now)(p.db))(model.ParallelMap())
+}
+
+// Expire deletes the reservation if it has lapsed as of now. Returns false if it was already consumed, cancelled or expired elsewhere.
+func (p *Processor) Expire(id uint32, now time.Time) (bool, error) {
+	rows, err := deleteExpiredById(p.db, p.t.Id(), id, now)
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// ExpiredTenantsProvider yields every tenant which holds at least one reservation that has lapsed as of now.
+func ExpiredTenantsProvider(db *gorm.DB) func(now time.Time) model.Provider[[]tenant.Model] {
+	return func(now time.Time) model.Provider[[]tenant.Model] {
+		return model.SliceMap(func(e Entity) (tenant.Model, error) {
+			return tenant.Create(e.TenantId, e.TenantRegion, e.TenantMajorVersion, e.TenantMinorVersion)
+		})(getExpiredTenants(now)(db))()
+	}
+}
@@ -0,0 +1,62 @@
+package reservation
+
+import (
+	"atlas-inventory/database"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getActiveBySlot(tenantId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("slot = ? AND expiry > ?", slot, now), &Entity{TenantId: tenantId, CharacterId: characterId, InventoryType: inventoryType})
+	}
+}
+
+func getBySlot(tenantId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("slot = ?", slot), &Entity{TenantId: tenantId, CharacterId: characterId, InventoryType: inventoryType})
+	}
+}
+
+func getActiveByTransactionAndSlot(tenantId uuid.UUID, transactionId uuid.UUID, characterId uint32, inventoryType inventory.Type, slot int16, now time.Time) database.EntityProvider[Entity] {
+	return func(db *gorm.DB) model.Provider[Entity] {
+		return database.Query[Entity](db.Where("slot = ? AND expiry > ?", slot, now), &Entity{TenantId: tenantId, TransactionId: transactionId, CharacterId: characterId, InventoryType: inventoryType})
+	}
+}
+
+// getActive yields the active reservations of a character. A zero inventory type or transaction id matches any.
+func getActive(tenantId uuid.UUID, characterId uint32, inventoryType inventory.Type, transactionId uuid.UUID, now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("expiry > ?", now).Order("inventory_type, slot, id"), &Entity{TenantId: tenantId, CharacterId: characterId, InventoryType: inventoryType, TransactionId: transactionId})
+	}
+}
+
+func getActiveByTransaction(tenantId uuid.UUID, transactionId uuid.UUID, characterId uint32, now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("expiry > ?", now), &Entity{TenantId: tenantId, TransactionId: transactionId, CharacterId: characterId})
+	}
+}
+
+func getExpired(tenantId uuid.UUID, now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Where("expiry <= ?", now), &Entity{TenantId: tenantId})
+	}
+}
+
+func getExpiredTenants(now time.Time) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		var results []Entity
+		err := db.Model(&Entity{}).
+			Distinct("tenant_id", "tenant_region", "tenant_major_version", "tenant_minor_version").
+			Where("expiry <= ?", now).
+			Find(&results).Error
+		if err != nil {
+			return model.ErrorProvider[[]Entity](err)
+		}
+		return model.FixedProvider(results)
+	}
+}
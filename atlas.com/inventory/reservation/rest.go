@@ -0,0 +1,48 @@
+package reservation
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+)
+
+type RestModel struct {
+	Id            uint32         `json:"-"`
+	TransactionId uuid.UUID      `json:"transactionId"`
+	InventoryType inventory.Type `json:"inventoryType"`
+	Slot          int16          `json:"slot"`
+	ItemId        uint32         `json:"itemId"`
+	Quantity      uint32         `json:"quantity"`
+	Expiry        time.Time      `json:"expiry"`
+}
+
+func (r RestModel) GetName() string {
+	return "reservations"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func Transform(m Model) (RestModel, error) {
+	return RestModel{
+		Id:            m.id,
+		TransactionId: m.transactionId,
+		InventoryType: m.inventoryType,
+		Slot:          m.slot,
+		ItemId:        m.itemId,
+		Quantity:      m.quantity,
+		Expiry:        m.expiry,
+	}, nil
+}
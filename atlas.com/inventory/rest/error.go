@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/jtumidanski/api2go/jsonapi"
+	"github.com/sirupsen/logrus"
+)
+
+type errorDocument struct {
+	Errors []jsonapi.Error `json:"errors"`
+}
+
+// MarshalError writes a JSON:API error document with the given status.
+func MarshalError(l logrus.FieldLogger) func(w http.ResponseWriter) func(status int) func(errs ...jsonapi.Error) {
+	return func(w http.ResponseWriter) func(status int) func(errs ...jsonapi.Error) {
+		return func(status int) func(errs ...jsonapi.Error) {
+			return func(errs ...jsonapi.Error) {
+				for i := range errs {
+					errs[i].Status = strconv.Itoa(status)
+				}
+				w.Header().Set("Content-Type", "application/vnd.api+json")
+				w.WriteHeader(status)
+				err := json.NewEncoder(w).Encode(errorDocument{Errors: errs})
+				if err != nil {
+					l.WithError(err).Errorf("Writing error response.")
+				}
+			}
+		}
+	}
+}
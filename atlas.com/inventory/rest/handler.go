@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Chronicle20/atlas-constants/inventory"
 	"github.com/Chronicle20/atlas-rest/server"
 	"github.com/gorilla/mux"
 	"github.com/jtumidanski/api2go/jsonapi"
@@ -126,3 +127,59 @@ func ParseAssetId(l logrus.FieldLogger, next AssetIdHandler) http.HandlerFunc {
 		next(uint32(assetId))(w, r)
 	}
 }
+
+type TransactionIdHandler func(transactionId uuid.UUID) http.HandlerFunc
+
+func ParseTransactionId(l logrus.FieldLogger, next TransactionIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transactionId, err := uuid.Parse(mux.Vars(r)["transactionId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse transactionId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(transactionId)(w, r)
+	}
+}
+
+type InventoryTypeHandler func(inventoryType inventory.Type) http.HandlerFunc
+
+func ParseInventoryType(l logrus.FieldLogger, next InventoryTypeHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inventoryType, err := strconv.Atoi(mux.Vars(r)["inventoryType"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse inventoryType from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(inventory.Type(inventoryType))(w, r)
+	}
+}
+
+type ReconciliationIdHandler func(reconciliationId uuid.UUID) http.HandlerFunc
+
+func ParseReconciliationId(l logrus.FieldLogger, next ReconciliationIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reconciliationId, err := uuid.Parse(mux.Vars(r)["reconciliationId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse reconciliationId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(reconciliationId)(w, r)
+	}
+}
+
+type LoadoutIdHandler func(loadoutId uuid.UUID) http.HandlerFunc
+
+func ParseLoadoutId(l logrus.FieldLogger, next LoadoutIdHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		loadoutId, err := uuid.Parse(mux.Vars(r)["loadoutId"])
+		if err != nil {
+			l.WithError(err).Errorf("Unable to properly parse loadoutId from path.")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		next(loadoutId)(w, r)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"github.com/Chronicle20/atlas-rest/requests"
 	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
 )
 
 func MakeGetRequest[A any](url string) requests.Request[A] {
@@ -37,3 +39,12 @@ func MakeDeleteRequest(url string) requests.EmptyBodyRequest {
 		return requests.MakeDeleteRequest(url, sd, td)(l, ctx)
 	}
 }
+
+// IdList renders ids as the comma separated list a filter[ids] query parameter expects.
+func IdList(ids []uint32) string {
+	strs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		strs = append(strs, strconv.FormatUint(uint64(id), 10))
+	}
+	return strings.Join(strs, ",")
+}
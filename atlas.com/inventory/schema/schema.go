@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/compartment"
+	"atlas-inventory/data/cache"
+	"atlas-inventory/database"
+	"atlas-inventory/ledger"
+	"atlas-inventory/loadout"
+	"atlas-inventory/outbox"
+	"atlas-inventory/reconciliation"
+	"atlas-inventory/reservation"
+	"atlas-inventory/stackable"
+	"atlas-inventory/statistics"
+	"atlas-inventory/warning"
+)
+
+// Migrations lists every schema migration in the order it is applied. Append new migrations with the next version;
+// never renumber or edit one which has shipped. Each step works on a frozen copy of the schema it changes, so a later
+// change to an entity does not alter what an earlier step does.
+func Migrations() []database.Migration {
+	return []database.Migration{
+		{Version: 1, Name: "create_compartments", Up: compartment.CreateTable, Down: compartment.DropTable},
+		{Version: 2, Name: "create_assets", Up: asset.CreateTable, Down: asset.DropTable},
+		{Version: 3, Name: "create_stackables", Up: stackable.CreateTable, Down: stackable.DropTable},
+		{Version: 4, Name: "create_reservations", Up: reservation.CreateTable, Down: reservation.DropTable},
+		{Version: 5, Name: "create_outbox", Up: outbox.CreateTable, Down: outbox.DropTable},
+		{Version: 6, Name: "create_command_ledger", Up: ledger.CreateTable, Down: ledger.DropTable},
+		{Version: 7, Name: "create_expiry_warnings", Up: warning.CreateTable, Down: warning.DropTable},
+		{Version: 8, Name: "create_reconciliations", Up: reconciliation.CreateTable, Down: reconciliation.DropTable},
+		{Version: 9, Name: "create_equipment_statistics", Up: statistics.CreateTable, Down: statistics.DropTable},
+		{Version: 10, Name: "create_loadouts", Up: loadout.CreateTable, Down: loadout.DropTable},
+		{Version: 11, Name: "add_compartment_unique_index", Up: compartment.AddUniqueIndex, Down: compartment.DropUniqueIndex},
+		{Version: 12, Name: "add_asset_indexes", Up: asset.AddIndexes, Down: asset.DropIndexes},
+		{Version: 13, Name: "add_reservation_created_at", Up: reservation.AddCreatedAt, Down: reservation.DropCreatedAt},
+		{Version: 14, Name: "add_reservation_indexes", Up: reservation.AddIndexes, Down: reservation.DropIndexes},
+		{Version: 15, Name: "create_data_cache_generations", Up: cache.CreateTable, Down: cache.DropTable},
+	}
+}
@@ -0,0 +1,131 @@
+package schema_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/compartment"
+	"atlas-inventory/data/cache"
+	"atlas-inventory/database"
+	"atlas-inventory/ledger"
+	"atlas-inventory/loadout"
+	"atlas-inventory/outbox"
+	"atlas-inventory/reconciliation"
+	"atlas-inventory/reservation"
+	"atlas-inventory/schema"
+	"atlas-inventory/stackable"
+	"atlas-inventory/statistics"
+	"atlas-inventory/warning"
+	"testing"
+
+	"github.com/Chronicle20/atlas-constants/inventory"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// entities are the live entities, which the migrations must together produce.
+func entities() []interface{} {
+	return []interface{}{
+		&compartment.Entity{}, &asset.Entity{}, &stackable.Entity{}, &reservation.Entity{}, &outbox.Entity{},
+		&ledger.Entity{}, &warning.Entity{}, &reconciliation.Entity{}, &reconciliation.FindingEntity{},
+		&statistics.Entity{}, &loadout.Entity{}, &loadout.SlotEntity{}, &cache.Entity{},
+	}
+}
+
+// TestMigrationsMatchEntities verifies that applying every migration yields the columns and indexes of the live
+// entities, and that rolling them back removes only what each undone migration added, down to the baseline tables.
+func TestMigrationsMatchEntities(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	for _, e := range entities() {
+		stmt := &gorm.Statement{DB: db}
+		if err = stmt.Parse(e); err != nil {
+			t.Fatalf("Failed to parse entity: %v", err)
+		}
+		for _, f := range stmt.Schema.Fields {
+			if f.DBName != "" && !db.Migrator().HasColumn(e, f.DBName) {
+				t.Errorf("Expected table [%s] to have column [%s].", stmt.Schema.Table, f.DBName)
+			}
+		}
+		for _, idx := range stmt.Schema.ParseIndexes() {
+			if !db.Migrator().HasIndex(e, idx.Name) {
+				t.Errorf("Expected table [%s] to have index [%s].", stmt.Schema.Table, idx.Name)
+			}
+		}
+	}
+
+	if err = database.Rollback(l, db, schema.Migrations(), len(schema.Migrations())-3); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if db.Migrator().HasTable(&reservation.Entity{}) || db.Migrator().HasIndex(&asset.Entity{}, "idx_assets_compartment_slot") {
+		t.Fatalf("Expected the rolled back migrations to be undone.")
+	}
+	if !db.Migrator().HasTable(&compartment.Entity{}) || !db.Migrator().HasTable(&asset.Entity{}) || !db.Migrator().HasTable(&stackable.Entity{}) {
+		t.Fatalf("Expected the migrations not rolled back to be kept.")
+	}
+	if err = database.Rollback(l, db, schema.Migrations(), 3); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	for _, e := range entities() {
+		if db.Migrator().HasTable(e) {
+			t.Errorf("Expected table of [%T] to be dropped.", e)
+		}
+	}
+}
+
+// TestUniqueIndexesResolveDuplicates verifies that compartments and slots duplicated before the unique indexes existed
+// are merged and reslotted so the indexes can be created.
+func TestUniqueIndexesResolveDuplicates(t *testing.T) {
+	l, _ := test.NewNullLogger()
+	db, err := gorm.Open(sqlite.Open("file:duplicates?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	ms := schema.Migrations()
+	if err = database.Migrate(l, db, ms[:10]); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	tenantId := uuid.New()
+	kept := compartment.Entity{TenantId: tenantId, Id: uuid.New(), CharacterId: 1, InventoryType: inventory.TypeValueETC, Capacity: 48}
+	merged := compartment.Entity{TenantId: tenantId, Id: uuid.New(), CharacterId: 1, InventoryType: inventory.TypeValueETC, Capacity: 24}
+	if err = db.Create(&[]compartment.Entity{kept, merged}).Error; err != nil {
+		t.Fatalf("Failed to create compartments: %v", err)
+	}
+	as := []asset.Entity{
+		{TenantId: tenantId, CompartmentId: kept.Id, Slot: 1, TemplateId: 4000000},
+		{TenantId: tenantId, CompartmentId: kept.Id, Slot: 2, TemplateId: 4000001},
+		{TenantId: tenantId, CompartmentId: merged.Id, Slot: 1, TemplateId: 4000002},
+	}
+	if err = db.Create(&as).Error; err != nil {
+		t.Fatalf("Failed to create assets: %v", err)
+	}
+
+	if err = database.Migrate(l, db, ms); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+	var cs []compartment.Entity
+	if err = db.Where("tenant_id = ?", tenantId).Find(&cs).Error; err != nil {
+		t.Fatalf("Failed to get compartments: %v", err)
+	}
+	if len(cs) != 1 || cs[0].Id != kept.Id {
+		t.Fatalf("Expected only the largest compartment to be kept, got [%+v].", cs)
+	}
+	var rs []asset.Entity
+	if err = db.Where("tenant_id = ?", tenantId).Order("id").Find(&rs).Error; err != nil {
+		t.Fatalf("Failed to get assets: %v", err)
+	}
+	if len(rs) != 3 || rs[0].Slot != 1 || rs[1].Slot != 2 || rs[2].CompartmentId != kept.Id || rs[2].Slot != 3 {
+		t.Fatalf("Expected the merged asset to move to the lowest free slot, got [%+v].", rs)
+	}
+	if !db.Migrator().HasIndex(&asset.Entity{}, "idx_assets_compartment_slot") {
+		t.Fatalf("Expected the unique slot index to be created.")
+	}
+}
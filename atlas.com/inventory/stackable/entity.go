@@ -2,13 +2,8 @@ package stackable
 
 import (
 	"github.com/google/uuid"
-	"gorm.io/gorm"
 )
 
-func Migration(db *gorm.DB) error {
-	return db.AutoMigrate(&Entity{})
-}
-
 type Entity struct {
 	TenantId      uuid.UUID `gorm:"not null"`
 	Id            uint32    `gorm:"primaryKey;autoIncrement;not null"`
@@ -25,10 +20,11 @@ func (e Entity) TableName() string {
 
 func Make(e Entity) (Model, error) {
 	return Model{
-		id:           e.Id,
-		quantity:     e.Quantity,
-		ownerId:      e.OwnerId,
-		flag:         e.Flag,
-		rechargeable: e.Rechargeable,
+		id:            e.Id,
+		compartmentId: e.CompartmentId,
+		quantity:      e.Quantity,
+		ownerId:       e.OwnerId,
+		flag:          e.Flag,
+		rechargeable:  e.Rechargeable,
 	}, nil
 }
@@ -0,0 +1,33 @@
+package stackable
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+// entityV3 is the stackables table as the service created it before migrations were versioned.
+type entityV3 struct {
+	TenantId      uuid.UUID `gorm:"not null"`
+	Id            uint32    `gorm:"primaryKey;autoIncrement;not null"`
+	CompartmentId uuid.UUID `gorm:"not null"`
+	Quantity      uint32    `gorm:"not null"`
+	OwnerId       uint32    `gorm:"not null"`
+	Flag          uint16    `gorm:"not null"`
+	Rechargeable  uint64    `gorm:"not null;default=0"`
+}
+
+func (e entityV3) TableName() string {
+	return "stackables"
+}
+
+// CreateTable creates the stackables table, or leaves it as it is where it predates versioned migrations.
+func CreateTable(db *gorm.DB) error {
+	return db.AutoMigrate(&entityV3{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("stackables")
+}
@@ -1,11 +1,14 @@
 package stackable
 
+import "github.com/google/uuid"
+
 type Model struct {
-	id           uint32
-	quantity     uint32
-	ownerId      uint32
-	flag         uint16
-	rechargeable uint64
+	id            uint32
+	compartmentId uuid.UUID
+	quantity      uint32
+	ownerId       uint32
+	flag          uint16
+	rechargeable  uint64
 }
 
 type ModelBuilder struct {
@@ -55,6 +58,10 @@ func (m Model) Id() uint32 {
 	return m.id
 }
 
+func (m Model) CompartmentId() uuid.UUID {
+	return m.compartmentId
+}
+
 func (m Model) Quantity() uint32 {
 	return m.quantity
 }
@@ -27,12 +27,13 @@ func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Proce
 }
 
 func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
-	return &Processor{
-		l:       p.l,
-		ctx:     p.ctx,
-		db:      db,
-		GetById: p.GetById,
+	tp := &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
 	}
+	tp.GetById = model.CollapseProvider(tp.ByIdProvider)
+	return tp
 }
 
 func (p *Processor) ByCompartmentIdProvider(compartmentId uuid.UUID) model.Provider[[]Model] {
@@ -40,6 +41,12 @@ func (p *Processor) ByCompartmentIdProvider(compartmentId uuid.UUID) model.Provi
 	return model.SliceMap(Make)(getByCompartmentId(t.Id(), compartmentId)(p.db))(model.ParallelMap())
 }
 
+// AllProvider yields every stackable held by the tenant.
+func (p *Processor) AllProvider() model.Provider[[]Model] {
+	t := tenant.MustFromContext(p.ctx)
+	return model.SliceMap(Make)(getAll(t.Id())(p.db))(model.ParallelMap())
+}
+
 func (p *Processor) Delete(id uint32) error {
 	t := tenant.MustFromContext(p.ctx)
 	p.l.Debugf("Attempting to delete stackable item [%d].", id)
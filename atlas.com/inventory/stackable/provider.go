@@ -14,6 +14,12 @@ func getById(tenantId uuid.UUID, id uint32) database.EntityProvider[Entity] {
 	}
 }
 
+func getAll(tenantId uuid.UUID) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db.Order("id"), &Entity{TenantId: tenantId})
+	}
+}
+
 func getByCompartmentId(tenantId uuid.UUID, compartmentId uuid.UUID) database.EntityProvider[[]Entity] {
 	return func(db *gorm.DB) model.Provider[[]Entity] {
 		return database.SliceQuery[Entity](db, &Entity{TenantId: tenantId, CompartmentId: compartmentId})
@@ -0,0 +1,40 @@
+package statistics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// save records the totals announced for a character, replacing any recorded before.
+func save(db *gorm.DB, tenantId uuid.UUID, characterId uint32, t Totals, now time.Time) error {
+	e := &Entity{
+		TenantId:      tenantId,
+		CharacterId:   characterId,
+		Strength:      t.Strength,
+		Dexterity:     t.Dexterity,
+		Intelligence:  t.Intelligence,
+		Luck:          t.Luck,
+		HP:            t.HP,
+		MP:            t.MP,
+		WeaponAttack:  t.WeaponAttack,
+		MagicAttack:   t.MagicAttack,
+		WeaponDefense: t.WeaponDefense,
+		MagicDefense:  t.MagicDefense,
+		Accuracy:      t.Accuracy,
+		Avoidability:  t.Avoidability,
+		Speed:         t.Speed,
+		Jump:          t.Jump,
+		UpdatedAt:     now,
+	}
+	err := deleteByCharacterId(db, tenantId, characterId)
+	if err != nil {
+		return err
+	}
+	return db.Create(e).Error
+}
+
+func deleteByCharacterId(db *gorm.DB, tenantId uuid.UUID, characterId uint32) error {
+	return db.Where("tenant_id = ? AND character_id = ?", tenantId, characterId).Delete(&Entity{}).Error
+}
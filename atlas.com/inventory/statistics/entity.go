@@ -0,0 +1,52 @@
+package statistics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity records the equipment statistics last announced for a character, so that only changes are announced.
+type Entity struct {
+	TenantId      uuid.UUID `gorm:"not null;uniqueIndex:idx_equipment_statistics_tenant_character"`
+	Id            uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId   uint32    `gorm:"not null;uniqueIndex:idx_equipment_statistics_tenant_character"`
+	Strength      uint32    `gorm:"not null"`
+	Dexterity     uint32    `gorm:"not null"`
+	Intelligence  uint32    `gorm:"not null"`
+	Luck          uint32    `gorm:"not null"`
+	HP            uint32    `gorm:"not null"`
+	MP            uint32    `gorm:"not null"`
+	WeaponAttack  uint32    `gorm:"not null"`
+	MagicAttack   uint32    `gorm:"not null"`
+	WeaponDefense uint32    `gorm:"not null"`
+	MagicDefense  uint32    `gorm:"not null"`
+	Accuracy      uint32    `gorm:"not null"`
+	Avoidability  uint32    `gorm:"not null"`
+	Speed         uint32    `gorm:"not null"`
+	Jump          uint32    `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (e Entity) TableName() string {
+	return "equipment_statistics"
+}
+
+func MakeTotals(e Entity) (Totals, error) {
+	return Totals{
+		Strength:      e.Strength,
+		Dexterity:     e.Dexterity,
+		Intelligence:  e.Intelligence,
+		Luck:          e.Luck,
+		HP:            e.HP,
+		MP:            e.MP,
+		WeaponAttack:  e.WeaponAttack,
+		MagicAttack:   e.MagicAttack,
+		WeaponDefense: e.WeaponDefense,
+		MagicDefense:  e.MagicDefense,
+		Accuracy:      e.Accuracy,
+		Avoidability:  e.Avoidability,
+		Speed:         e.Speed,
+		Jump:          e.Jump,
+	}, nil
+}
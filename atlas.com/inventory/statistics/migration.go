@@ -0,0 +1,44 @@
+package statistics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV9 struct {
+	TenantId      uuid.UUID `gorm:"not null;uniqueIndex:idx_equipment_statistics_tenant_character"`
+	Id            uint64    `gorm:"primaryKey;autoIncrement;not null"`
+	CharacterId   uint32    `gorm:"not null;uniqueIndex:idx_equipment_statistics_tenant_character"`
+	Strength      uint32    `gorm:"not null"`
+	Dexterity     uint32    `gorm:"not null"`
+	Intelligence  uint32    `gorm:"not null"`
+	Luck          uint32    `gorm:"not null"`
+	HP            uint32    `gorm:"not null"`
+	MP            uint32    `gorm:"not null"`
+	WeaponAttack  uint32    `gorm:"not null"`
+	MagicAttack   uint32    `gorm:"not null"`
+	WeaponDefense uint32    `gorm:"not null"`
+	MagicDefense  uint32    `gorm:"not null"`
+	Accuracy      uint32    `gorm:"not null"`
+	Avoidability  uint32    `gorm:"not null"`
+	Speed         uint32    `gorm:"not null"`
+	Jump          uint32    `gorm:"not null"`
+	UpdatedAt     time.Time `gorm:"not null"`
+}
+
+func (e entityV9) TableName() string {
+	return "equipment_statistics"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV9{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("equipment_statistics")
+}
@@ -0,0 +1,212 @@
+package statistics
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/data/set"
+	"sort"
+)
+
+// Totals are the statistics granted by equipment.
+type Totals struct {
+	Strength      uint32
+	Dexterity     uint32
+	Intelligence  uint32
+	Luck          uint32
+	HP            uint32
+	MP            uint32
+	WeaponAttack  uint32
+	MagicAttack   uint32
+	WeaponDefense uint32
+	MagicDefense  uint32
+	Accuracy      uint32
+	Avoidability  uint32
+	Speed         uint32
+	Jump          uint32
+}
+
+func (t Totals) Add(o Totals) Totals {
+	return Totals{
+		Strength:      t.Strength + o.Strength,
+		Dexterity:     t.Dexterity + o.Dexterity,
+		Intelligence:  t.Intelligence + o.Intelligence,
+		Luck:          t.Luck + o.Luck,
+		HP:            t.HP + o.HP,
+		MP:            t.MP + o.MP,
+		WeaponAttack:  t.WeaponAttack + o.WeaponAttack,
+		MagicAttack:   t.MagicAttack + o.MagicAttack,
+		WeaponDefense: t.WeaponDefense + o.WeaponDefense,
+		MagicDefense:  t.MagicDefense + o.MagicDefense,
+		Accuracy:      t.Accuracy + o.Accuracy,
+		Avoidability:  t.Avoidability + o.Avoidability,
+		Speed:         t.Speed + o.Speed,
+		Jump:          t.Jump + o.Jump,
+	}
+}
+
+func fromStatisticData(s asset.StatisticData) Totals {
+	return Totals{
+		Strength:      uint32(s.Strength()),
+		Dexterity:     uint32(s.Dexterity()),
+		Intelligence:  uint32(s.Intelligence()),
+		Luck:          uint32(s.Luck()),
+		HP:            uint32(s.HP()),
+		MP:            uint32(s.MP()),
+		WeaponAttack:  uint32(s.WeaponAttack()),
+		MagicAttack:   uint32(s.MagicAttack()),
+		WeaponDefense: uint32(s.WeaponDefense()),
+		MagicDefense:  uint32(s.MagicDefense()),
+		Accuracy:      uint32(s.Accuracy()),
+		Avoidability:  uint32(s.Avoidability()),
+		Speed:         uint32(s.Speed()),
+		Jump:          uint32(s.Jump()),
+	}
+}
+
+func fromBonus(b set.Bonus) Totals {
+	return Totals{
+		Strength:      uint32(b.Strength()),
+		Dexterity:     uint32(b.Dexterity()),
+		Intelligence:  uint32(b.Intelligence()),
+		Luck:          uint32(b.Luck()),
+		HP:            uint32(b.HP()),
+		MP:            uint32(b.MP()),
+		WeaponAttack:  uint32(b.WeaponAttack()),
+		MagicAttack:   uint32(b.MagicAttack()),
+		WeaponDefense: uint32(b.WeaponDefense()),
+		MagicDefense:  uint32(b.MagicDefense()),
+		Accuracy:      uint32(b.Accuracy()),
+		Avoidability:  uint32(b.Avoidability()),
+		Speed:         uint32(b.Speed()),
+		Jump:          uint32(b.Jump()),
+	}
+}
+
+// Slot is what the equipment worn in one slot contributes.
+type Slot struct {
+	slot       int16
+	assetId    uint32
+	templateId uint32
+	totals     Totals
+}
+
+func (s Slot) Slot() int16 {
+	return s.slot
+}
+
+func (s Slot) AssetId() uint32 {
+	return s.assetId
+}
+
+func (s Slot) TemplateId() uint32 {
+	return s.templateId
+}
+
+func (s Slot) Totals() Totals {
+	return s.totals
+}
+
+// SetBonus is what a set contributes for the number of its items worn.
+type SetBonus struct {
+	setId  uint32
+	name   string
+	count  uint32
+	totals Totals
+}
+
+func (b SetBonus) SetId() uint32 {
+	return b.setId
+}
+
+func (b SetBonus) Name() string {
+	return b.name
+}
+
+func (b SetBonus) Count() uint32 {
+	return b.count
+}
+
+func (b SetBonus) Totals() Totals {
+	return b.totals
+}
+
+// Model is what a character's equipment contributes to its statistics.
+type Model struct {
+	characterId uint32
+	totals      Totals
+	slots       []Slot
+	setBonuses  []SetBonus
+}
+
+func (m Model) CharacterId() uint32 {
+	return m.characterId
+}
+
+func (m Model) Totals() Totals {
+	return m.totals
+}
+
+func (m Model) Slots() []Slot {
+	return m.slots
+}
+
+func (m Model) SetBonuses() []SetBonus {
+	return m.setBonuses
+}
+
+// Sum totals the statistics of the equipment worn among a character's equipment assets, those in negative slots.
+func Sum(characterId uint32, as []asset.Model[any]) Model {
+	m := Model{characterId: characterId, slots: make([]Slot, 0)}
+	for _, a := range as {
+		if a.Slot() >= 0 {
+			continue
+		}
+		var sd asset.StatisticData
+		switch rd := a.ReferenceData().(type) {
+		case asset.EquipableReferenceData:
+			sd = rd.StatisticData
+		case asset.CashEquipableReferenceData:
+			sd = rd.StatisticData
+		default:
+			continue
+		}
+		t := fromStatisticData(sd)
+		m.slots = append(m.slots, Slot{slot: a.Slot(), assetId: a.Id(), templateId: a.TemplateId(), totals: t})
+		m.totals = m.totals.Add(t)
+	}
+	sort.Slice(m.slots, func(i, j int) bool { return m.slots[i].slot > m.slots[j].slot })
+	return m
+}
+
+// WithSetBonuses adds the bonuses of every set with items worn. Each bonus applies once at least its count of the
+// set's items are worn, in addition to those for fewer items.
+func (m Model) WithSetBonuses(sets []set.Model) Model {
+	worn := make(map[uint32]bool)
+	for _, s := range m.slots {
+		worn[s.templateId] = true
+	}
+	r := m
+	r.setBonuses = make([]SetBonus, 0)
+	for _, s := range sets {
+		count := uint32(0)
+		for _, id := range s.ItemIds() {
+			if worn[id] {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		b := SetBonus{setId: s.Id(), name: s.Name(), count: count}
+		for _, sb := range s.Bonuses() {
+			if sb.Count() <= count {
+				b.totals = b.totals.Add(fromBonus(sb))
+			}
+		}
+		if b.totals == (Totals{}) {
+			continue
+		}
+		r.setBonuses = append(r.setBonuses, b)
+		r.totals = r.totals.Add(b.totals)
+	}
+	return r
+}
@@ -0,0 +1,151 @@
+package statistics_test
+
+import (
+	"atlas-inventory/asset"
+	"atlas-inventory/data/set"
+	"atlas-inventory/database"
+	"atlas-inventory/schema"
+	"atlas-inventory/statistics"
+	"context"
+	"testing"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func testDatabase(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to database: %v", err)
+	}
+	l, _ := test.NewNullLogger()
+	if err = database.Migrate(l, db, schema.Migrations()); err != nil {
+		t.Fatalf("Failed to migrate database: %v", err)
+	}
+	return db
+}
+
+func testContext() context.Context {
+	t, _ := tenant.Create(uuid.New(), "GMS", 83, 1)
+	return tenant.WithContext(context.Background(), t)
+}
+
+func testLogger() logrus.FieldLogger {
+	l, _ := test.NewNullLogger()
+	return l
+}
+
+func equipped(compartmentId uuid.UUID, id uint32, templateId uint32, slot int16, strength uint16, weaponAttack uint16) asset.Model[any] {
+	rd := asset.NewEquipableReferenceDataBuilder().SetStrength(strength).SetWeaponAttack(weaponAttack).Build()
+	return asset.NewBuilder[any](id, compartmentId, templateId, id, asset.ReferenceTypeEquipable).SetSlot(slot).SetReferenceData(rd).Build()
+}
+
+// TestSum verifies only worn equipment is totalled, cash equipment included, and slots are listed from the first.
+func TestSum(t *testing.T) {
+	cid := uuid.New()
+	cash := asset.NewBuilder[any](4, cid, 1702000, 4, asset.ReferenceTypeCashEquipable).
+		SetSlot(-111).
+		SetReferenceData(asset.NewCashEquipableReferenceDataBuilder().SetWeaponAttack(1).Build()).
+		Build()
+	as := []asset.Model[any]{
+		equipped(cid, 1, 1302000, -11, 3, 17),
+		equipped(cid, 2, 1002000, -1, 1, 0),
+		equipped(cid, 3, 1040002, 4, 10, 10),
+		cash,
+	}
+
+	m := statistics.Sum(1, as)
+	if m.Totals().Strength != 4 || m.Totals().WeaponAttack != 18 {
+		t.Fatalf("Unexpected totals [%+v].", m.Totals())
+	}
+	if len(m.Slots()) != 3 || m.Slots()[0].Slot() != -1 || m.Slots()[1].Slot() != -11 || m.Slots()[2].Slot() != -111 {
+		t.Fatalf("Unexpected slots [%+v].", m.Slots())
+	}
+}
+
+// TestWithSetBonuses verifies every bonus tier reached by the number of a set's items worn is added.
+func TestWithSetBonuses(t *testing.T) {
+	cid := uuid.New()
+	s, err := set.Extract(set.RestModel{Id: 1, Name: "Zakum", ItemIds: []uint32{1002357, 1122000, 1032030}, Bonuses: []set.BonusRestModel{
+		{Count: 2, Strength: 5},
+		{Count: 3, WeaponAttack: 10},
+	}})
+	if err != nil {
+		t.Fatalf("Failed to extract set: %v", err)
+	}
+	unworn, err := set.Extract(set.RestModel{Id: 2, Name: "Unworn", ItemIds: []uint32{1002000}, Bonuses: []set.BonusRestModel{{Count: 1, Luck: 5}}})
+	if err != nil {
+		t.Fatalf("Failed to extract set: %v", err)
+	}
+
+	two := statistics.Sum(1, []asset.Model[any]{
+		equipped(cid, 1, 1002357, -1, 1, 0),
+		equipped(cid, 2, 1122000, -17, 1, 0),
+	}).WithSetBonuses([]set.Model{s, unworn})
+	if len(two.SetBonuses()) != 1 || two.SetBonuses()[0].Count() != 2 {
+		t.Fatalf("Unexpected set bonuses [%+v].", two.SetBonuses())
+	}
+	if two.Totals().Strength != 7 || two.Totals().WeaponAttack != 0 {
+		t.Fatalf("Unexpected totals [%+v].", two.Totals())
+	}
+
+	three := statistics.Sum(1, []asset.Model[any]{
+		equipped(cid, 1, 1002357, -1, 1, 0),
+		equipped(cid, 2, 1122000, -17, 1, 0),
+		equipped(cid, 3, 1032030, -4, 1, 0),
+	}).WithSetBonuses([]set.Model{s, unworn})
+	if three.Totals().Strength != 8 || three.Totals().WeaponAttack != 10 {
+		t.Fatalf("Unexpected totals [%+v].", three.Totals())
+	}
+}
+
+// TestRecord verifies a change is reported only when the totals differ from those last recorded.
+func TestRecord(t *testing.T) {
+	db := testDatabase(t)
+	p := statistics.NewProcessor(testLogger(), testContext(), db)
+
+	changed, err := p.Record(1, statistics.Totals{})
+	if err != nil || changed {
+		t.Fatalf("Expected no equipment to be unchanged, got [%t] and error [%v].", changed, err)
+	}
+	changed, err = p.Record(1, statistics.Totals{Strength: 3, WeaponAttack: 17})
+	if err != nil || !changed {
+		t.Fatalf("Expected equipping to change the totals, got [%t] and error [%v].", changed, err)
+	}
+	changed, err = p.Record(1, statistics.Totals{Strength: 3, WeaponAttack: 17})
+	if err != nil || changed {
+		t.Fatalf("Expected the same totals to be unchanged, got [%t] and error [%v].", changed, err)
+	}
+	changed, err = p.Record(1, statistics.Totals{})
+	if err != nil || !changed {
+		t.Fatalf("Expected unequipping to change the totals, got [%t] and error [%v].", changed, err)
+	}
+}
+
+// TestDeleteByCharacterId verifies that deleting a character's record forgets only that character's totals.
+func TestDeleteByCharacterId(t *testing.T) {
+	db := testDatabase(t)
+	p := statistics.NewProcessor(testLogger(), testContext(), db)
+
+	totals := statistics.Totals{Strength: 3, WeaponAttack: 17}
+	for _, characterId := range []uint32{1, 2} {
+		if _, err := p.Record(characterId, totals); err != nil {
+			t.Fatalf("Failed to record totals: %v", err)
+		}
+	}
+	if err := p.DeleteByCharacterId(1); err != nil {
+		t.Fatalf("Failed to delete totals: %v", err)
+	}
+	changed, err := p.Record(1, totals)
+	if err != nil || !changed {
+		t.Fatalf("Expected the deleted character's totals to be forgotten, got [%t] and error [%v].", changed, err)
+	}
+	changed, err = p.Record(2, totals)
+	if err != nil || changed {
+		t.Fatalf("Expected another character's totals to be kept, got [%t] and error [%v].", changed, err)
+	}
+}
@@ -0,0 +1,64 @@
+package statistics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+// Record remembers the totals announced for a character, reporting whether they differ from those last announced. A
+// character never announced is taken to have had none. Run it in the transaction which stages the announcement, so
+// the record is only kept if the announcement is sent.
+func (p *Processor) Record(characterId uint32, t Totals) (bool, error) {
+	var previous Totals
+	e, err := getByCharacterId(p.t.Id(), characterId)(p.db)()
+	if err == nil {
+		previous, err = MakeTotals(e)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	if previous == t {
+		return false, nil
+	}
+	err = save(p.db, p.t.Id(), characterId, t, time.Now())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteByCharacterId forgets the totals announced for a character, as when its inventory is deleted.
+func (p *Processor) DeleteByCharacterId(characterId uint32) error {
+	return deleteByCharacterId(p.db, p.t.Id(), characterId)
+}
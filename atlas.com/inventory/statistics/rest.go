@@ -0,0 +1,94 @@
+package statistics
+
+import "strconv"
+
+type TotalsRestModel struct {
+	Strength      uint32 `json:"strength"`
+	Dexterity     uint32 `json:"dexterity"`
+	Intelligence  uint32 `json:"intelligence"`
+	Luck          uint32 `json:"luck"`
+	HP            uint32 `json:"hp"`
+	MP            uint32 `json:"mp"`
+	WeaponAttack  uint32 `json:"weaponAttack"`
+	MagicAttack   uint32 `json:"magicAttack"`
+	WeaponDefense uint32 `json:"weaponDefense"`
+	MagicDefense  uint32 `json:"magicDefense"`
+	Accuracy      uint32 `json:"accuracy"`
+	Avoidability  uint32 `json:"avoidability"`
+	Speed         uint32 `json:"speed"`
+	Jump          uint32 `json:"jump"`
+}
+
+type SlotRestModel struct {
+	TotalsRestModel
+	Slot       int16  `json:"slot"`
+	AssetId    uint32 `json:"assetId"`
+	TemplateId uint32 `json:"templateId"`
+}
+
+type SetBonusRestModel struct {
+	TotalsRestModel
+	SetId uint32 `json:"setId"`
+	Name  string `json:"name"`
+	Count uint32 `json:"count"`
+}
+
+type RestModel struct {
+	TotalsRestModel
+	Id         uint32              `json:"-"`
+	Slots      []SlotRestModel     `json:"slots"`
+	SetBonuses []SetBonusRestModel `json:"setBonuses,omitempty"`
+}
+
+func (r RestModel) GetName() string {
+	return "equipment-statistics"
+}
+
+func (r RestModel) GetID() string {
+	return strconv.Itoa(int(r.Id))
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := strconv.Atoi(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = uint32(id)
+	return nil
+}
+
+func TransformTotals(t Totals) TotalsRestModel {
+	return TotalsRestModel{
+		Strength:      t.Strength,
+		Dexterity:     t.Dexterity,
+		Intelligence:  t.Intelligence,
+		Luck:          t.Luck,
+		HP:            t.HP,
+		MP:            t.MP,
+		WeaponAttack:  t.WeaponAttack,
+		MagicAttack:   t.MagicAttack,
+		WeaponDefense: t.WeaponDefense,
+		MagicDefense:  t.MagicDefense,
+		Accuracy:      t.Accuracy,
+		Avoidability:  t.Avoidability,
+		Speed:         t.Speed,
+		Jump:          t.Jump,
+	}
+}
+
+func Transform(m Model) (RestModel, error) {
+	ss := make([]SlotRestModel, 0, len(m.Slots()))
+	for _, s := range m.Slots() {
+		ss = append(ss, SlotRestModel{TotalsRestModel: TransformTotals(s.Totals()), Slot: s.Slot(), AssetId: s.AssetId(), TemplateId: s.TemplateId()})
+	}
+	var bs []SetBonusRestModel
+	for _, b := range m.SetBonuses() {
+		bs = append(bs, SetBonusRestModel{TotalsRestModel: TransformTotals(b.Totals()), SetId: b.SetId(), Name: b.Name(), Count: b.Count()})
+	}
+	return RestModel{
+		TotalsRestModel: TransformTotals(m.Totals()),
+		Id:              m.CharacterId(),
+		Slots:           ss,
+		SetBonuses:      bs,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package tasks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Task interface {
+	Run()
+	SleepTime() time.Duration
+}
+
+// Register runs the task on its interval until the context is cancelled. A panic in one run is logged and does not stop later runs.
+func Register(l logrus.FieldLogger, ctx context.Context, wg *sync.WaitGroup) func(t Task) {
+	return func(t Task) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ticker := time.NewTicker(t.SleepTime())
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					l.Debugf("Stopping task execution.")
+					return
+				case <-ticker.C:
+					run(l, t)
+				}
+			}
+		}()
+	}
+}
+
+func run(l logrus.FieldLogger, t Task) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.Errorf("Recovered from panic in task execution: %v", r)
+		}
+	}()
+	t.Run()
+}
@@ -0,0 +1,29 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/Chronicle20/atlas-rest/requests"
+	tenant2 "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+	}
+	return p
+}
+
+// AllProvider yields every tenant known to the tenants service. Scheduled work walks these rather than the tenants
+// found in local rows, as assets only record the id of their tenant.
+func (p *Processor) AllProvider() model.Provider[[]tenant2.Model] {
+	return requests.SliceProvider[RestModel, tenant2.Model](p.l, p.ctx)(requestAll(), Extract, model.Filters[tenant2.Model]())
+}
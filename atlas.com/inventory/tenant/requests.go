@@ -0,0 +1,24 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/Chronicle20/atlas-rest/requests"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	Resource = "tenants"
+)
+
+func getBaseRequest() string {
+	return requests.RootUrl("TENANTS")
+}
+
+// requestAll lists every tenant. It is made outside of any one tenant, so only the span header is attached.
+func requestAll() requests.Request[[]RestModel] {
+	return func(l logrus.FieldLogger, ctx context.Context) ([]RestModel, error) {
+		sd := requests.AddHeaderDecorator(requests.SpanHeaderDecorator(ctx))
+		return requests.MakeGetRequest[[]RestModel](getBaseRequest()+Resource, sd)(l, ctx)
+	}
+}
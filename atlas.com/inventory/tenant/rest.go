@@ -0,0 +1,35 @@
+package tenant
+
+import (
+	tenant2 "github.com/Chronicle20/atlas-tenant"
+	"github.com/google/uuid"
+)
+
+type RestModel struct {
+	Id           uuid.UUID `json:"-"`
+	Name         string    `json:"name"`
+	Region       string    `json:"region"`
+	MajorVersion uint16    `json:"majorVersion"`
+	MinorVersion uint16    `json:"minorVersion"`
+}
+
+func (r RestModel) GetName() string {
+	return "tenants"
+}
+
+func (r RestModel) GetID() string {
+	return r.Id.String()
+}
+
+func (r *RestModel) SetID(strId string) error {
+	id, err := uuid.Parse(strId)
+	if err != nil {
+		return err
+	}
+	r.Id = id
+	return nil
+}
+
+func Extract(rm RestModel) (tenant2.Model, error) {
+	return tenant2.Create(rm.Id, rm.Region, rm.MajorVersion, rm.MinorVersion)
+}
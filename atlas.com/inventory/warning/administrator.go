@@ -0,0 +1,24 @@
+package warning
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func create(db *gorm.DB, tenantId uuid.UUID, assetId uint32, expiration time.Time, threshold time.Duration, now time.Time) error {
+	e := &Entity{
+		TenantId:   tenantId,
+		AssetId:    assetId,
+		Expiration: expiration,
+		Threshold:  threshold,
+		SentAt:     now,
+	}
+	return db.Create(e).Error
+}
+
+func deleteLapsed(db *gorm.DB, tenantId uuid.UUID, now time.Time) (int64, error) {
+	result := db.Where("tenant_id = ? AND expiration <= ?", tenantId, now).Delete(&Entity{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,32 @@
+package warning
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity records that an asset was warned it is about to expire. The expiration is part of the key, so extending an
+// asset's expiration makes its warnings due again.
+type Entity struct {
+	TenantId   uuid.UUID     `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	Id         uint64        `gorm:"primaryKey;autoIncrement;not null"`
+	AssetId    uint32        `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	Expiration time.Time     `gorm:"not null;uniqueIndex:idx_expiry_warning;index"`
+	Threshold  time.Duration `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	SentAt     time.Time     `gorm:"not null"`
+}
+
+func (e Entity) TableName() string {
+	return "expiry_warnings"
+}
+
+func Make(e Entity) (Model, error) {
+	return Model{
+		id:         e.Id,
+		assetId:    e.AssetId,
+		expiration: e.Expiration,
+		threshold:  e.Threshold,
+		sentAt:     e.SentAt,
+	}, nil
+}
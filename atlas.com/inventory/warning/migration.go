@@ -0,0 +1,32 @@
+package warning
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The entities below are the schema as the migrations of this package left it. They are frozen: a change to the
+// live entities ships as a new migration, never as an edit to these.
+
+type entityV7 struct {
+	TenantId   uuid.UUID     `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	Id         uint64        `gorm:"primaryKey;autoIncrement;not null"`
+	AssetId    uint32        `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	Expiration time.Time     `gorm:"not null;uniqueIndex:idx_expiry_warning;index"`
+	Threshold  time.Duration `gorm:"not null;uniqueIndex:idx_expiry_warning"`
+	SentAt     time.Time     `gorm:"not null"`
+}
+
+func (e entityV7) TableName() string {
+	return "expiry_warnings"
+}
+
+func CreateTable(db *gorm.DB) error {
+	return db.Migrator().CreateTable(&entityV7{})
+}
+
+func DropTable(db *gorm.DB) error {
+	return db.Migrator().DropTable("expiry_warnings")
+}
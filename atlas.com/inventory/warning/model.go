@@ -0,0 +1,33 @@
+package warning
+
+import "time"
+
+type Model struct {
+	id         uint64
+	assetId    uint32
+	expiration time.Time
+	threshold  time.Duration
+	sentAt     time.Time
+}
+
+func (m Model) Id() uint64 {
+	return m.id
+}
+
+func (m Model) AssetId() uint32 {
+	return m.assetId
+}
+
+// Expiration is the expiration the asset had when it was warned.
+func (m Model) Expiration() time.Time {
+	return m.expiration
+}
+
+// Threshold is how long before the expiration the warning became due.
+func (m Model) Threshold() time.Duration {
+	return m.threshold
+}
+
+func (m Model) SentAt() time.Time {
+	return m.sentAt
+}
@@ -0,0 +1,69 @@
+package warning
+
+import (
+	"context"
+	"time"
+
+	"github.com/Chronicle20/atlas-model/model"
+	tenant "github.com/Chronicle20/atlas-tenant"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type Processor struct {
+	l   logrus.FieldLogger
+	ctx context.Context
+	db  *gorm.DB
+	t   tenant.Model
+}
+
+func NewProcessor(l logrus.FieldLogger, ctx context.Context, db *gorm.DB) *Processor {
+	p := &Processor{
+		l:   l,
+		ctx: ctx,
+		db:  db,
+		t:   tenant.MustFromContext(ctx),
+	}
+	return p
+}
+
+func (p *Processor) WithTransaction(db *gorm.DB) *Processor {
+	return &Processor{
+		l:   p.l,
+		ctx: p.ctx,
+		db:  db,
+		t:   p.t,
+	}
+}
+
+// ByAssetIdProvider yields the warnings already sent for an asset.
+func (p *Processor) ByAssetIdProvider(assetId uint32) model.Provider[[]Model] {
+	return model.SliceMap(Make)(getByAssetId(p.t.Id(), assetId)(p.db))(model.ParallelMap())
+}
+
+// SentThresholds reports which thresholds an asset was already warned of while it held the given expiration.
+func (p *Processor) SentThresholds(assetId uint32, expiration time.Time) (map[time.Duration]bool, error) {
+	ws, err := p.ByAssetIdProvider(assetId)()
+	if err != nil {
+		return nil, err
+	}
+	results := make(map[time.Duration]bool)
+	for _, w := range ws {
+		if w.Expiration().Equal(expiration) {
+			results[w.Threshold()] = true
+		}
+	}
+	return results, nil
+}
+
+// Record remembers that an asset was warned of a threshold. Run it in the transaction which stages the warning, so
+// the record is only kept if the warning is sent.
+func (p *Processor) Record(assetId uint32, expiration time.Time, threshold time.Duration, now time.Time) error {
+	return create(p.db, p.t.Id(), assetId, expiration, threshold, now)
+}
+
+// PurgeLapsed forgets the warnings for expirations which have passed as of now. The assets have since either expired
+// or been given a new expiration, so the warnings can no longer repeat.
+func (p *Processor) PurgeLapsed(now time.Time) (int64, error) {
+	return deleteLapsed(p.db, p.t.Id(), now)
+}
@@ -0,0 +1,15 @@
+package warning
+
+import (
+	"atlas-inventory/database"
+
+	"github.com/Chronicle20/atlas-model/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func getByAssetId(tenantId uuid.UUID, assetId uint32) database.EntityProvider[[]Entity] {
+	return func(db *gorm.DB) model.Provider[[]Entity] {
+		return database.SliceQuery[Entity](db, &Entity{TenantId: tenantId, AssetId: assetId})
+	}
+}